@@ -11,9 +11,15 @@ import (
 
 	"github.com/davidsonmarra/receitas-app/internal/models"
 	"github.com/davidsonmarra/receitas-app/internal/server"
+	"github.com/davidsonmarra/receitas-app/pkg/analytics"
 	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/cost"
 	"github.com/davidsonmarra/receitas-app/pkg/database"
 	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/privacy"
+	"github.com/davidsonmarra/receitas-app/pkg/publishing"
+	"github.com/davidsonmarra/receitas-app/pkg/recommendation"
+	"github.com/davidsonmarra/receitas-app/pkg/similarity"
 )
 
 func main() {
@@ -51,9 +57,31 @@ func main() {
 		&models.User{},
 		&models.Recipe{},
 		&models.Ingredient{},
+		&models.IngredientNutrient{},
 		&models.RecipeIngredient{},
+		&models.RecipeStep{},
+		&models.RecipeRevision{},
+		&models.Tag{},
 		&models.Rating{},
+		&models.MealPlan{},
+		&models.MealPlanSlot{},
+		&models.MealPlanMember{},
+		&models.ShoppingList{},
+		&models.ShoppingListItem{},
+		&models.PantryItem{},
+		&models.NutritionGoal{},
+		&models.IngredientPrice{},
+		&models.IngredientSubstitution{},
+		&models.RecipeSimilarity{},
+		&models.RecipeRatingSimilarity{},
+		&models.RecipeEvent{},
+		&models.RecipeDailyStat{},
+		&models.CookLog{},
 		&models.RefreshToken{},
+		&models.APIKey{},
+		&models.FoodAnalysis{},
+		&models.DataExport{},
+		&models.DataErasure{},
 	); err != nil {
 		log.Error("failed to migrate database", "error", err)
 		os.Exit(1)
@@ -64,6 +92,24 @@ func main() {
 	// Iniciar job de limpeza de refresh tokens expirados (a cada 24 horas)
 	auth.StartRefreshTokenCleanup(24 * time.Hour)
 
+	// Iniciar job de descarte de exportações de dados expiradas (a cada hora)
+	privacy.StartDataExportCleanup(time.Hour)
+
+	// Iniciar job de publicação de receitas agendadas (a cada minuto)
+	publishing.StartScheduler(time.Minute)
+
+	// Iniciar job de recálculo de custos quando preços agendados entram em vigor (a cada hora)
+	cost.StartScheduler(time.Hour)
+
+	// Iniciar job de reconstrução das receitas semelhantes quando receitas mudam (a cada 10 minutos)
+	similarity.StartScheduler(10 * time.Minute)
+
+	// Iniciar job de treino do modelo de recomendação quando há avaliações novas (a cada hora)
+	recommendation.StartScheduler(time.Hour)
+
+	// Iniciar job de receitas em alta e descarte de eventos de deduplicação (a cada 15 minutos)
+	analytics.StartScheduler(15 * time.Minute)
+
 	// Configuração da porta (lê de PORT env var ou usa 8080)
 	port := getPort()
 
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/recommendation"
+	"github.com/davidsonmarra/receitas-app/pkg/similarity"
+)
+
+func main() {
+	// Inicializar logger
+	logConfig := log.Config{
+		Level:       "info",
+		Development: true,
+	}
+	if err := log.Init(logConfig); err != nil {
+		fmt.Printf("❌ Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Conectar database
+	if err := database.Connect(); err != nil {
+		log.Error("failed to connect to database", "error", err)
+		fmt.Printf("❌ Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	fmt.Println("🔄 Reconstruindo receitas semelhantes...")
+	started := time.Now()
+	neighbors, err := similarity.Rebuild(database.DB)
+	if err != nil {
+		log.Error("failed to rebuild similar recipes", "error", err)
+		fmt.Printf("❌ Falha ao reconstruir receitas semelhantes: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %d vizinhos gravados em %s\n", neighbors, time.Since(started).Round(time.Millisecond))
+
+	fmt.Println("🔄 Treinando modelo de recomendação...")
+	started = time.Now()
+	pairs, err := recommendation.Rebuild(database.DB)
+	if err != nil {
+		log.Error("failed to rebuild recommendation model", "error", err)
+		fmt.Printf("❌ Falha ao treinar modelo de recomendação: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %d pares de receitas gravados em %s\n", pairs, time.Since(started).Round(time.Millisecond))
+}
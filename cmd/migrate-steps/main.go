@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/markdown"
+)
+
+// Converte o modo de preparo em Markdown (listas numeradas) em passos estruturados
+// Apenas receitas com instruções e ainda sem passos são convertidas; o campo instructions é mantido
+func main() {
+	dryRun := flag.Bool("dry-run", false, "apenas mostra o que seria convertido, sem gravar")
+	flag.Parse()
+
+	// Inicializar logger
+	logConfig := log.Config{
+		Level:       "info",
+		Development: true,
+	}
+	if err := log.Init(logConfig); err != nil {
+		fmt.Printf("❌ Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Conectar database
+	if err := database.Connect(); err != nil {
+		log.Error("failed to connect to database", "error", err)
+		fmt.Printf("❌ Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	fmt.Println("🔍 Buscando receitas sem passos estruturados...")
+
+	var recipes []models.Recipe
+	if err := database.DB.
+		Select("id", "title", "instructions").
+		Where("instructions IS NOT NULL AND instructions <> ''").
+		Where("NOT EXISTS (SELECT 1 FROM recipe_steps WHERE recipe_steps.recipe_id = recipes.id)").
+		Order("id").
+		Find(&recipes).Error; err != nil {
+		log.Error("failed to list recipes", "error", err)
+		fmt.Printf("❌ Failed to list recipes: %v\n", err)
+		os.Exit(1)
+	}
+
+	converted, skipped, totalSteps := 0, 0, 0
+	for _, recipe := range recipes {
+		parsed := markdown.ExtractSteps(recipe.Instructions)
+		if len(parsed) == 0 {
+			skipped++
+			fmt.Printf("⚠️  #%d %s: nenhuma lista numerada encontrada\n", recipe.ID, recipe.Title)
+			continue
+		}
+
+		steps := make([]models.RecipeStep, len(parsed))
+		for i, p := range parsed {
+			steps[i] = models.RecipeStep{
+				RecipeID:        recipe.ID,
+				Order:           i + 1,
+				Text:            p.Text,
+				DurationSeconds: p.DurationSeconds,
+			}
+		}
+
+		if !*dryRun {
+			err := database.DB.Transaction(func(tx *gorm.DB) error {
+				return tx.Create(&steps).Error
+			})
+			if err != nil {
+				log.Error("failed to create recipe steps", "recipe_id", recipe.ID, "error", err)
+				fmt.Printf("❌ #%d %s: %v\n", recipe.ID, recipe.Title, err)
+				continue
+			}
+		}
+
+		converted++
+		totalSteps += len(steps)
+		fmt.Printf("✅ #%d %s: %d passos\n", recipe.ID, recipe.Title, len(steps))
+	}
+
+	log.Info("recipe steps migration finished",
+		"converted", converted,
+		"skipped", skipped,
+		"steps", totalSteps,
+		"dry_run", *dryRun)
+
+	fmt.Printf("\n📊 Receitas convertidas: %d | Sem lista numerada: %d | Passos criados: %d\n", converted, skipped, totalSteps)
+	if *dryRun {
+		fmt.Println("ℹ️  Execução em modo dry-run: nada foi gravado")
+	}
+}
@@ -49,11 +49,16 @@ func main() {
 	// Inserir ingredientes no banco
 	inserted := 0
 	skipped := 0
+	backfilled := 0
 
 	for i, ing := range ingredients {
 		if err := database.DB.Create(&ing).Error; err != nil {
 			if strings.Contains(err.Error(), "duplicate key") {
 				skipped++
+				// Ingredientes importados antes do perfil estendido ganham os nutrientes
+				if completarNutrientes(ing) {
+					backfilled++
+				}
 			} else {
 				log.Error("failed to create ingredient", "name", ing.Name, "error", err)
 			}
@@ -68,10 +73,65 @@ func main() {
 	fmt.Printf("\n✅ Seed completo!\n")
 	fmt.Printf("   Inseridos: %d\n", inserted)
 	fmt.Printf("   Ignorados (duplicados): %d\n", skipped)
+	fmt.Printf("   Nutrientes completados em existentes: %d\n", backfilled)
 
 	var count int64
 	database.DB.Model(&models.Ingredient{}).Count(&count)
 	fmt.Printf("   Total no banco: %d ingredientes\n", count)
+
+	fmt.Printf("   Substituições curadas criadas: %d\n", seedSubstituicoes())
+}
+
+// substituicaoPadrao é uma substituição curada inicial, localizada pelo início do nome TACO
+type substituicaoPadrao struct {
+	Original   string
+	Substituto string
+	Ratio      float64
+	Notes      string
+}
+
+// substituicoesPadrao são as substituições clássicas da cozinha; os admins mantêm a lista depois
+// (POST /admin/ingredients/{id}/substitutes)
+var substituicoesPadrao = []substituicaoPadrao{
+	{"Manteiga", "Óleo, de soja", 0.8, "Em massas de bolo; a textura fica mais úmida"},
+	{"Manteiga", "Margarina", 1, ""},
+	{"Creme de Leite", "Iogurte, natural", 1, "Acrescente no fim do preparo, sem ferver"},
+	{"Açúcar, refinado", "Mel", 0.75, "Reduza um pouco os líquidos da receita"},
+	{"Farinha, de trigo", "Amido, de milho", 0.5, "Para engrossar molhos e cremes"},
+	{"Leite, de vaca, integral", "Leite, de coco", 1, "Sabor mais marcante; bom em doces e molhos"},
+	{"Ovo, de galinha, inteiro, cru", "Banana, prata, crua", 1, "Em bolos e panquecas; use a banana amassada"},
+}
+
+// seedSubstituicoes cria as substituições padrão cujos ingredientes existem no banco
+func seedSubstituicoes() int {
+	created := 0
+	for _, padrao := range substituicoesPadrao {
+		var original, substituto models.Ingredient
+		if err := database.DB.Where("name ILIKE ?", padrao.Original+"%").Order("id ASC").First(&original).Error; err != nil {
+			continue
+		}
+		if err := database.DB.Where("name ILIKE ?", padrao.Substituto+"%").Order("id ASC").First(&substituto).Error; err != nil {
+			continue
+		}
+
+		sub := models.IngredientSubstitution{
+			IngredientID: original.ID,
+			SubstituteID: substituto.ID,
+			Ratio:        padrao.Ratio,
+			Notes:        padrao.Notes,
+		}
+		result := database.DB.Where("ingredient_id = ? AND substitute_id = ?", original.ID, substituto.ID).
+			Omit("Substitute").
+			FirstOrCreate(&sub)
+		if result.Error != nil {
+			log.Error("failed to create substitution", "original", original.Name, "substitute", substituto.Name, "error", result.Error)
+			continue
+		}
+		if result.RowsAffected > 0 {
+			created++
+		}
+	}
+	return created
 }
 
 // parseTACOCSV lê o arquivo CSV da Tabela TACO e retorna uma lista de ingredientes
@@ -110,6 +170,15 @@ func parseTACOCSV(filePath string) ([]models.Ingredient, error) {
 	carboidratoCol := colMap["Carboidrato (g)"]
 	fibraCol := colMap["Fibra Alimentar (g)"]
 
+	// Colunas do perfil estendido presentes neste arquivo
+	nutrienteCols := make(map[string]int)
+	for nutrient, coluna := range colunasNutrientes {
+		if idx, ok := colMap[coluna]; ok {
+			nutrienteCols[nutrient] = idx
+		}
+	}
+	fmt.Printf("🧪 Colunas de nutrientes encontradas: %d de %d\n", len(nutrienteCols), len(colunasNutrientes))
+
 	lineNumber := 1
 	for {
 		record, err := reader.Read()
@@ -161,6 +230,20 @@ func parseTACOCSV(filePath string) ([]models.Ingredient, error) {
 			Unit:     "g",
 			Source:   "taco",
 		}
+		inferirRestricoes(&ingredient)
+
+		for _, nutrient := range models.Nutrients {
+			idx, ok := nutrienteCols[nutrient.Key]
+			if !ok {
+				continue
+			}
+			amount, status := parseNutriente(record[idx])
+			ingredient.Nutrients = append(ingredient.Nutrients, models.IngredientNutrient{
+				Nutrient: nutrient.Key,
+				Amount:   amount,
+				Status:   status,
+			})
+		}
 
 		ingredients = append(ingredients, ingredient)
 	}
@@ -168,6 +251,78 @@ func parseTACOCSV(filePath string) ([]models.Ingredient, error) {
 	return ingredients, nil
 }
 
+// colunasNutrientes mapeia cada nutriente do perfil estendido para a coluna do CSV da TACO
+var colunasNutrientes = map[string]string{
+	models.NutrientMoisture:           "Umidade (%)",
+	models.NutrientCholesterol:        "Colesterol (mg)",
+	models.NutrientSaturatedFat:       "Saturados (g)",
+	models.NutrientMonounsaturatedFat: "Mono-insaturados (g)",
+	models.NutrientPolyunsaturatedFat: "Poli-insaturados (g)",
+	models.NutrientAsh:                "Cinzas (g)",
+	models.NutrientCalcium:            "Cálcio (mg)",
+	models.NutrientMagnesium:          "Magnésio (mg)",
+	models.NutrientManganese:          "Manganês (mg)",
+	models.NutrientPhosphorus:         "Fósforo (mg)",
+	models.NutrientIron:               "Ferro (mg)",
+	models.NutrientSodium:             "Sódio (mg)",
+	models.NutrientPotassium:          "Potássio (mg)",
+	models.NutrientCopper:             "Cobre (mg)",
+	models.NutrientZinc:               "Zinco (mg)",
+	models.NutrientRetinol:            "Retinol (mcg)",
+	models.NutrientVitaminA:           "RAE (mcg)",
+	models.NutrientThiamine:           "Tiamina (mg)",
+	models.NutrientRiboflavin:         "Riboflavina (mg)",
+	models.NutrientPyridoxine:         "Piridoxina (mg)",
+	models.NutrientNiacin:             "Niacina (mg)",
+	models.NutrientVitaminC:           "Vitamina C (mg)",
+}
+
+// parseNutriente converte um valor da TACO preservando traço e "não medido"
+// "Tr" = traço; "NA", "*", "-" ou vazio = não analisado; números = valor medido
+func parseNutriente(s string) (*float64, string) {
+	s = strings.TrimSpace(s)
+
+	switch strings.ToLower(s) {
+	case "tr":
+		return nil, models.NutrientStatusTrace
+	case "", "na", "*", "-":
+		return nil, models.NutrientStatusNotMeasured
+	}
+
+	value, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil || value < 0 {
+		return nil, models.NutrientStatusNotMeasured
+	}
+	return &value, models.NutrientStatusMeasured
+}
+
+// completarNutrientes grava o perfil estendido de um ingrediente já existente que ainda não o tem
+func completarNutrientes(ing models.Ingredient) bool {
+	if len(ing.Nutrients) == 0 {
+		return false
+	}
+
+	var existing models.Ingredient
+	if err := database.DB.Where("name = ?", ing.Name).First(&existing).Error; err != nil {
+		return false
+	}
+
+	var count int64
+	database.DB.Model(&models.IngredientNutrient{}).Where("ingredient_id = ?", existing.ID).Count(&count)
+	if count > 0 {
+		return false
+	}
+
+	for i := range ing.Nutrients {
+		ing.Nutrients[i].IngredientID = existing.ID
+	}
+	if err := database.DB.Create(&ing.Nutrients).Error; err != nil {
+		log.Error("failed to backfill ingredient nutrients", "name", ing.Name, "error", err)
+		return false
+	}
+	return true
+}
+
 // parseFloat converte string para float64, tratando valores inválidos
 func parseFloat(s string) float64 {
 	s = strings.TrimSpace(s)
@@ -237,3 +392,72 @@ func normalizarCategoria(s string) string {
 		return s
 	}
 }
+
+// Palavras-chave (na descrição TACO) usadas para inferir as flags alimentares
+var (
+	palavrasGluten    = []string{"trigo", "pão", "macarrão", "biscoito", "bolo", "cevada", "centeio", "aveia", "malte", "cerveja", "pizza", "torrada", "bolacha", "lasanha", "empada", "pastel", "coxinha", "quibe"}
+	palavrasLactose   = []string{"leite", "queijo", "manteiga", "creme de leite", "iogurte", "requeijão", "nata", "ricota", "doce de leite", "chocolate ao leite", "sorvete"}
+	palavrasNozes     = []string{"castanha", "noz", "nozes", "amêndoa", "avelã", "pistache", "macadâmia", "amendoim", "pinhão", "pé-de-moleque", "paçoca"}
+	palavrasOvo       = []string{"ovo", "maionese", "omelete", "gema", "clara"}
+	palavrasCrustaceo = []string{"camarão", "lagosta", "caranguejo", "siri", "marisco", "mexilhão", "ostra", "lula", "polvo", "sururu", "vôngole"}
+	palavrasSoja      = []string{"soja", "tofu", "shoyu", "missô"}
+	palavrasMel       = []string{"mel", "gelatina"}
+)
+
+// inferirRestricoes preenche as flags alimentares a partir da categoria e da descrição TACO
+// A inferência é conservadora e deve ser revisada pelos admins (PUT /admin/ingredients/{id})
+func inferirRestricoes(ing *models.Ingredient) {
+	descricao := strings.ToLower(ing.Name)
+
+	switch ing.Category {
+	case "carnes", "peixes e frutos do mar":
+		ing.ContainsMeat = true
+	case "laticínios":
+		// Leite de soja e similares vegetais ficam na mesma categoria da TACO
+		if !contemAlguma(descricao, palavrasSoja) {
+			ing.ContainsLactose = true
+		}
+	case "ovos":
+		ing.ContainsEgg = true
+	case "nozes e sementes":
+		ing.ContainsNuts = contemAlguma(descricao, palavrasNozes)
+	}
+
+	if contemAlguma(descricao, palavrasGluten) {
+		ing.ContainsGluten = true
+	}
+	if contemAlguma(descricao, palavrasLactose) && !contemAlguma(descricao, palavrasSoja) && !strings.Contains(descricao, "coco") {
+		ing.ContainsLactose = true
+	}
+	if contemAlguma(descricao, palavrasNozes) {
+		ing.ContainsNuts = true
+	}
+	if contemAlguma(descricao, palavrasOvo) {
+		ing.ContainsEgg = true
+	}
+	if contemAlguma(descricao, palavrasCrustaceo) {
+		ing.ContainsShellfish = true
+		ing.ContainsMeat = true
+	}
+	if contemAlguma(descricao, palavrasSoja) {
+		ing.ContainsSoy = true
+	}
+
+	ing.AnimalOrigin = ing.ContainsMeat || ing.ContainsLactose || ing.ContainsEgg || ing.ContainsShellfish ||
+		contemAlguma(descricao, palavrasMel)
+}
+
+// contemAlguma verifica se o texto contém alguma das palavras (como palavra inteira)
+func contemAlguma(texto string, palavras []string) bool {
+	campos := strings.FieldsFunc(texto, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '(' || r == ')' || r == '/'
+	})
+	normalizado := " " + strings.Join(campos, " ") + " "
+
+	for _, palavra := range palavras {
+		if strings.Contains(normalizado, " "+palavra+" ") {
+			return true
+		}
+	}
+	return false
+}
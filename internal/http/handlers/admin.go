@@ -9,19 +9,31 @@ import (
 	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
 	"github.com/davidsonmarra/receitas-app/internal/models"
 	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/dietary"
 	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
 	"github.com/davidsonmarra/receitas-app/pkg/pagination"
 	"github.com/davidsonmarra/receitas-app/pkg/response"
 	"github.com/davidsonmarra/receitas-app/pkg/validation"
 )
 
 // AdminListRecipes lista todas as receitas (incluindo com dono) para admin
-// Diferente do endpoint público, este inclui informações do usuário criador
+// Diferente do endpoint público, este inclui informações do usuário criador e rascunhos
+// Filtro opcional: ?status=draft|published|archived
 func AdminListRecipes(w http.ResponseWriter, r *http.Request) {
 	params := pagination.ExtractParams(r)
 
+	query := database.DB.Model(&models.Recipe{})
+	if status := r.URL.Query().Get("status"); status != "" {
+		if !isValidRecipeStatus(status) {
+			response.ValidationError(w, "Status inválido. Use draft, published ou archived.")
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+
 	var total int64
-	if err := database.DB.Model(&models.Recipe{}).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		log.ErrorCtx(r.Context(), "admin failed to count recipes", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to count recipes")
 		return
@@ -31,7 +43,7 @@ func AdminListRecipes(w http.ResponseWriter, r *http.Request) {
 	offset := pagination.CalculateOffset(params)
 
 	// Admin vê todas receitas, incluindo relação com usuário (Preload)
-	if err := database.DB.Preload("User").Limit(params.Limit).Offset(offset).
+	if err := query.Preload("User").Limit(params.Limit).Offset(offset).
 		Order("created_at DESC").
 		Find(&recipes).Error; err != nil {
 		log.ErrorCtx(r.Context(), "admin failed to list recipes", "error", err)
@@ -70,6 +82,10 @@ func AdminUpdateRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !sanitizeInstructions(w, updateReq.Instructions) {
+		return
+	}
+
 	// Aplicar updates
 	if updateReq.Title != nil {
 		recipe.Title = *updateReq.Title
@@ -90,13 +106,14 @@ func AdminUpdateRecipe(w http.ResponseWriter, r *http.Request) {
 		recipe.Difficulty = *updateReq.Difficulty
 	}
 
-	if err := database.DB.Save(&recipe).Error; err != nil {
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+
+	if err := saveRecipeWithRevision(&recipe, userID); err != nil {
 		log.ErrorCtx(r.Context(), "admin failed to update recipe", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to update recipe")
 		return
 	}
 
-	userID, _ := middleware.GetUserIDFromContext(r.Context())
 	log.InfoCtx(r.Context(), "admin updated recipe",
 		"admin_id", userID,
 		"recipe_id", recipe.ID,
@@ -148,9 +165,28 @@ func AdminCreateGeneralRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !sanitizeInstructions(w, &recipe.Instructions) {
+		return
+	}
+
 	// Receita geral: user_id = nil (forçar)
 	recipe.UserID = nil
 
+	// Como qualquer receita nova, nasce como rascunho
+	recipe.Status = models.RecipeStatusDraft
+	recipe.PublishAt = nil
+	recipe.PublishedAt = nil
+	recipe.ForkedFromID = nil
+	dietary.Classification{}.ApplyTo(&recipe)
+	nutrition.Macros{}.ApplyTo(&recipe)
+	recipe.CostPerServing = nil
+
+	if err := applyRecipeVisibility(&recipe, recipe.Visibility); err != nil {
+		log.ErrorCtx(r.Context(), "admin failed to generate share token", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create recipe")
+		return
+	}
+
 	if err := database.DB.Create(&recipe).Error; err != nil {
 		log.ErrorCtx(r.Context(), "admin failed to create general recipe", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to create recipe")
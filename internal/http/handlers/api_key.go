@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// CreateAPIKeyRequest representa os dados para criar uma chave de API
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" validate:"required,min=3,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1"`
+	ExpiresInDays *int     `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
+
+// APIKeyResponse representa uma chave de API retornada ao cliente
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Key        string     `json:"key,omitempty"` // Apenas na criação
+}
+
+// newAPIKeyResponse converte o modelo para a resposta da API
+func newAPIKeyResponse(k *models.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// CreateAPIKey cria uma nova chave de API para o usuário autenticado
+func CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Usuário não autenticado")
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		t := time.Now().Add(time.Duration(*req.ExpiresInDays) * 24 * time.Hour)
+		expiresAt = &t
+	}
+
+	key, apiKey, err := auth.CreateAPIKey(auth.APIKeyInfo{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Scopes:    req.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrAPIKeyInvalidScope):
+			response.ValidationError(w, "Escopo inválido. Use: "+strings.Join(auth.ValidScopes, ", ")+".")
+		case errors.Is(err, auth.ErrAPIKeyLimitReached):
+			response.ValidationError(w, "Limite de chaves de API ativas atingido.")
+		default:
+			log.ErrorCtx(r.Context(), "failed to create api key", "error", err)
+			response.Error(w, http.StatusInternalServerError, "Erro ao criar chave de API")
+		}
+		return
+	}
+
+	log.InfoCtx(r.Context(), "api key created", "api_key_id", apiKey.ID, "user_id", userID, "scopes", apiKey.Scopes)
+
+	resp := newAPIKeyResponse(apiKey)
+	resp.Key = key
+	response.JSON(w, http.StatusCreated, resp)
+}
+
+// ListAPIKeys lista as chaves de API do usuário autenticado
+func ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Usuário não autenticado")
+		return
+	}
+
+	keys, err := auth.GetUserAPIKeys(userID)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to list api keys", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao buscar chaves de API")
+		return
+	}
+
+	resp := make([]APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		resp = append(resp, newAPIKeyResponse(&keys[i]))
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"api_keys": resp,
+	})
+}
+
+// RevokeAPIKey revoga uma chave de API (dono ou admin)
+func RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Usuário não autenticado")
+		return
+	}
+
+	keyID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		response.ValidationError(w, "ID da chave inválido")
+		return
+	}
+
+	var apiKey models.APIKey
+	if err := database.DB.First(&apiKey, keyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(w, http.StatusNotFound, "Chave de API não encontrada")
+			return
+		}
+		log.ErrorCtx(r.Context(), "failed to find api key", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao buscar chave de API")
+		return
+	}
+
+	// Apenas o dono ou um admin pode revogar
+	if apiKey.UserID != userID && !isAdmin(userID) {
+		response.Error(w, http.StatusNotFound, "Chave de API não encontrada")
+		return
+	}
+
+	if err := auth.RevokeAPIKey(apiKey.ID); err != nil {
+		log.ErrorCtx(r.Context(), "failed to revoke api key", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao revogar chave de API")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "api key revoked", "api_key_id", apiKey.ID, "owner_id", apiKey.UserID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Chave de API revogada com sucesso"})
+}
@@ -2,10 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"os"
 	"strings"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
 	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
 	"github.com/davidsonmarra/receitas-app/pkg/auth"
 	"github.com/davidsonmarra/receitas-app/pkg/log"
 	"github.com/davidsonmarra/receitas-app/pkg/response"
@@ -44,6 +51,17 @@ type DevicesResponse struct {
 	Devices []DeviceInfo `json:"devices"`
 }
 
+// RenameDeviceRequest representa a alteração do nome de um dispositivo
+type RenameDeviceRequest struct {
+	DeviceName string `json:"device_name" validate:"required,min=1,max=100"`
+}
+
+// DeviceIDHeader é o header com o ID estável do dispositivo, gerado pelo cliente na instalação
+const DeviceIDHeader = "X-Device-ID"
+
+// maxDeviceIDLength limita o tamanho do device ID aceito
+const maxDeviceIDLength = 100
+
 // getDeviceFingerprint gera um fingerprint do dispositivo baseado no User-Agent
 func getDeviceFingerprint(r *http.Request) string {
 	userAgent := r.Header.Get("User-Agent")
@@ -51,6 +69,32 @@ func getDeviceFingerprint(r *http.Request) string {
 	return auth.HashString(userAgent)
 }
 
+// getDeviceID extrai o device ID do header, ignorando valores inválidos
+func getDeviceID(r *http.Request) string {
+	deviceID := strings.TrimSpace(r.Header.Get(DeviceIDHeader))
+	if deviceID == "" || len(deviceID) > maxDeviceIDLength {
+		return ""
+	}
+
+	for _, c := range deviceID {
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlnum && c != '-' && c != '_' && c != '.' && c != ':' {
+			return ""
+		}
+	}
+
+	return deviceID
+}
+
+// getDeviceContext reúne as informações do dispositivo da requisição
+func getDeviceContext(r *http.Request) auth.DeviceContext {
+	return auth.DeviceContext{
+		DeviceID:    getDeviceID(r),
+		Fingerprint: getDeviceFingerprint(r),
+		IPAddress:   getClientIP(r),
+	}
+}
+
 // getClientIP extrai o IP do cliente da requisição
 func getClientIP(r *http.Request) string {
 	// Verificar headers de proxy primeiro
@@ -70,6 +114,69 @@ func getClientIP(r *http.Request) string {
 	return strings.Split(r.RemoteAddr, ":")[0]
 }
 
+// getTrustedClientIP extrai o IP do cliente sem confiar em headers enviados por ele
+// X-Forwarded-For só é considerado quando a conexão vem de um proxy listado em TRUSTED_PROXIES
+// (IPs ou CIDRs separados por vírgula); nesse caso vale a entrada mais à direita que não é proxy
+func getTrustedClientIP(r *http.Request) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+
+	proxies := trustedProxies()
+	if !isTrustedProxy(remote, proxies) {
+		return remote
+	}
+
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(forwarded[i])
+		if ip == "" || net.ParseIP(ip) == nil {
+			break
+		}
+		if !isTrustedProxy(ip, proxies) {
+			return ip
+		}
+	}
+	return remote
+}
+
+// trustedProxies lê TRUSTED_PROXIES; IPs sem máscara viram /32 (ou /128)
+func trustedProxies() []*net.IPNet {
+	var proxies []*net.IPNet
+	for _, entry := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			proxies = append(proxies, network)
+		}
+	}
+	return proxies
+}
+
+// isTrustedProxy indica se o IP pertence a um dos proxies confiáveis
+func isTrustedProxy(ip string, proxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range proxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
 // getDeviceName extrai um nome amigável do dispositivo baseado no User-Agent
 func getDeviceName(r *http.Request) string {
 	userAgent := r.Header.Get("User-Agent")
@@ -118,11 +225,10 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Obter informações do dispositivo
-	deviceFingerprint := getDeviceFingerprint(r)
-	ipAddress := getClientIP(r)
+	device := getDeviceContext(r)
 
 	// Validar e renovar tokens
-	result, err := auth.RefreshAccessToken(req.RefreshToken, deviceFingerprint, ipAddress)
+	result, err := auth.RefreshAccessToken(req.RefreshToken, device)
 	if err != nil {
 		// Mapear erros específicos
 		switch err {
@@ -132,7 +238,7 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) {
 			response.ErrorWithCode(w, http.StatusUnauthorized, "Refresh token expirado", "REFRESH_TOKEN_EXPIRED")
 		case auth.ErrRefreshTokenRevoked:
 			response.ErrorWithCode(w, http.StatusUnauthorized, "Refresh token revogado", "REFRESH_TOKEN_REVOKED")
-		case auth.ErrDeviceFingerprintMismatch:
+		case auth.ErrDeviceMismatch:
 			response.ErrorWithCode(w, http.StatusUnauthorized, "Dispositivo não reconhecido", "DEVICE_MISMATCH")
 		default:
 			log.ErrorCtx(r.Context(), "erro ao renovar token", "error", err)
@@ -141,7 +247,7 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.InfoCtx(r.Context(), "token renovado com sucesso", "ip", ipAddress)
+	log.InfoCtx(r.Context(), "token renovado com sucesso", "ip", device.IPAddress)
 
 	// Retornar novos tokens
 	resp := RefreshTokenResponse{
@@ -176,8 +282,7 @@ func RevokeToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validar que o token pertence ao usuário antes de revogar
-	deviceFingerprint := getDeviceFingerprint(r)
-	refreshToken, err := auth.ValidateRefreshToken(req.RefreshToken, deviceFingerprint)
+	refreshToken, err := auth.ValidateRefreshToken(req.RefreshToken, getDeviceContext(r))
 	if err != nil {
 		response.ErrorWithCode(w, http.StatusBadRequest, "Refresh token inválido", "REFRESH_TOKEN_INVALID")
 		return
@@ -237,7 +342,8 @@ func ListDevices(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Obter fingerprint do dispositivo atual
+	// Identificar o dispositivo atual (device ID quando disponível, senão fingerprint)
+	currentDeviceID := getDeviceID(r)
 	currentFingerprint := getDeviceFingerprint(r)
 
 	// Converter para response
@@ -255,7 +361,7 @@ func ListDevices(w http.ResponseWriter, r *http.Request) {
 			IPAddress:  token.IPAddress,
 			LastUsedAt: lastUsedAt,
 			CreatedAt:  token.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			IsCurrent:  token.DeviceFingerprint == currentFingerprint,
+			IsCurrent:  isCurrentDevice(&token, currentDeviceID, currentFingerprint),
 		}
 		devices = append(devices, device)
 	}
@@ -266,3 +372,83 @@ func ListDevices(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, resp)
 }
 
+// isCurrentDevice verifica se a sessão pertence ao dispositivo da requisição
+func isCurrentDevice(token *models.RefreshToken, deviceID, fingerprint string) bool {
+	if token.DeviceID != "" || deviceID != "" {
+		return token.DeviceID == deviceID
+	}
+	return token.DeviceFingerprint == fingerprint
+}
+
+// RenameDevice altera o nome de exibição de um dispositivo
+func RenameDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Usuário não autenticado")
+		return
+	}
+
+	var req RenameDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	req.DeviceName = strings.TrimSpace(req.DeviceName)
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	tokenID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.ValidationError(w, "ID do dispositivo inválido")
+		return
+	}
+
+	token, err := auth.RenameDevice(userID, tokenID.String(), req.DeviceName)
+	if err != nil {
+		if errors.Is(err, auth.ErrDeviceNotFound) {
+			response.Error(w, http.StatusNotFound, "Dispositivo não encontrado")
+			return
+		}
+		log.ErrorCtx(r.Context(), "erro ao renomear dispositivo", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao renomear dispositivo")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "dispositivo renomeado", "user_id", userID, "token_id", token.ID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"id":          token.ID.String(),
+		"device_name": req.DeviceName,
+	})
+}
+
+// RevokeDevice encerra a sessão de um dispositivo pelo ID
+func RevokeDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Usuário não autenticado")
+		return
+	}
+
+	tokenID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.ValidationError(w, "ID do dispositivo inválido")
+		return
+	}
+
+	if err := auth.RevokeDevice(userID, tokenID.String()); err != nil {
+		if errors.Is(err, auth.ErrDeviceNotFound) {
+			response.Error(w, http.StatusNotFound, "Dispositivo não encontrado")
+			return
+		}
+		log.ErrorCtx(r.Context(), "erro ao revogar dispositivo", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao revogar dispositivo")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "dispositivo revogado", "user_id", userID, "token_id", tokenID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Dispositivo desconectado com sucesso"})
+}
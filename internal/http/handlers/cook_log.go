@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/pagination"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/storage"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// cookLogFolder é a pasta das fotos de "fiz esta receita" no Cloudinary
+const cookLogFolder = "cook-logs"
+
+// CreateCookLogRequest representa um registro de "fiz esta receita"
+// A foto é enviada depois, pelo fluxo de upload direto (photo/upload-url e photo/confirm)
+type CreateCookLogRequest struct {
+	Notes       string     `json:"notes" validate:"omitempty,max=2000"`
+	Adjustments string     `json:"adjustments" validate:"omitempty,max=1000"`
+	CookedAt    *time.Time `json:"cooked_at"` // Padrão: agora
+}
+
+// CreateCookLog registra que o usuário autenticado preparou a receita
+// Também conta como preparo nas estatísticas da receita
+func CreateCookLog(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, chi.URLParam(r, "id")).Error; err != nil || !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	// O corpo é opcional
+	var req CreateCookLogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	cookedAt := time.Now()
+	if req.CookedAt != nil {
+		if req.CookedAt.After(cookedAt) {
+			response.ValidationError(w, "cooked_at não pode estar no futuro.")
+			return
+		}
+		cookedAt = *req.CookedAt
+	}
+
+	cookLog := models.CookLog{
+		RecipeID:    recipe.ID,
+		UserID:      userID,
+		Notes:       strings.TrimSpace(req.Notes),
+		Adjustments: strings.TrimSpace(req.Adjustments),
+		CookedAt:    cookedAt,
+	}
+	if err := database.DB.Create(&cookLog).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to create cook log", "recipe_id", recipe.ID, "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create cook log")
+		return
+	}
+
+	if _, err := recordRecipeEvent(r, &recipe, models.RecipeEventCook); err != nil {
+		log.ErrorCtx(r.Context(), "failed to record cook event", "recipe_id", recipe.ID, "user_id", userID, "error", err)
+	}
+
+	log.InfoCtx(r.Context(), "cook log created", "id", cookLog.ID, "recipe_id", recipe.ID, "user_id", userID)
+	response.JSON(w, http.StatusCreated, cookLog)
+}
+
+// ListRecipeCookLogs lista a galeria de "fiz esta receita", mais recentes primeiro
+// Filtro opcional: ?with_photo=true (apenas registros com foto)
+func ListRecipeCookLogs(w http.ResponseWriter, r *http.Request) {
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, chi.URLParam(r, "id")).Error; err != nil || !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	params := pagination.ExtractParams(r)
+
+	query := database.DB.Model(&models.CookLog{}).Where("recipe_id = ?", recipe.ID)
+	switch r.URL.Query().Get("with_photo") {
+	case "", "false":
+	case "true":
+		query = query.Where("photo_url <> ''")
+	default:
+		response.ValidationError(w, "Parâmetro with_photo inválido. Use true ou false.")
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to count cook logs", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list cook logs")
+		return
+	}
+
+	// Na galeria pública, apenas nome e avatar de quem preparou
+	var cookLogs []models.CookLog
+	if err := query.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "avatar_url")
+	}).
+		Order("cooked_at DESC, id DESC").
+		Limit(params.Limit).
+		Offset(pagination.CalculateOffset(params)).
+		Find(&cookLogs).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list cook logs", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list cook logs")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, pagination.BuildResponse(cookLogs, params, total))
+}
+
+// ListMyCookLogs lista o histórico de preparos do usuário autenticado, mais recentes primeiro
+func ListMyCookLogs(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	params := pagination.ExtractParams(r)
+	query := database.DB.Model(&models.CookLog{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to count user cook logs", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list cook logs")
+		return
+	}
+
+	var cookLogs []models.CookLog
+	if err := query.Preload("Recipe").
+		Order("cooked_at DESC, id DESC").
+		Limit(params.Limit).
+		Offset(pagination.CalculateOffset(params)).
+		Find(&cookLogs).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list user cook logs", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list cook logs")
+		return
+	}
+	hideUnavailableCookLogRecipes(r, cookLogs)
+
+	response.JSON(w, http.StatusOK, pagination.BuildResponse(cookLogs, params, total))
+}
+
+// DeleteCookLog remove um registro do usuário autenticado, com a foto
+func DeleteCookLog(w http.ResponseWriter, r *http.Request) {
+	cookLog, ok := loadOwnCookLog(w, r)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(cookLog).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete cook log", "id", cookLog.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete cook log")
+		return
+	}
+
+	// Remover foto (best effort)
+	if cookLog.PhotoPublicID != "" {
+		if imageService, err := storage.ServiceFactory(); err == nil {
+			imageService.DeleteImage(r.Context(), cookLog.PhotoPublicID)
+		}
+	}
+
+	log.InfoCtx(r.Context(), "cook log deleted", "id", cookLog.ID, "user_id", cookLog.UserID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Registro removido com sucesso"})
+}
+
+// GenerateCookLogPhotoUploadURL gera assinatura para upload direto da foto de um registro
+func GenerateCookLogPhotoUploadURL(w http.ResponseWriter, r *http.Request) {
+	cookLog, ok := loadOwnCookLog(w, r)
+	if !ok {
+		return
+	}
+
+	imageService, err := storage.ServiceFactory()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to initialize image service", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao configurar serviço de imagens")
+		return
+	}
+
+	publicID := fmt.Sprintf("%s%d", cookLogPhotoPublicIDPrefix(cookLog), time.Now().Unix())
+
+	uploadSig, err := imageService.GenerateUploadSignature(publicID, cookLogFolder)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate cook log upload signature", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao gerar URL de upload")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "cook log upload signature generated",
+		"cook_log_id", cookLog.ID,
+		"public_id", publicID,
+		"user_id", cookLog.UserID)
+
+	response.JSON(w, http.StatusOK, uploadSig)
+}
+
+// ConfirmCookLogPhotoUpload confirma o upload direto e salva a foto do registro
+func ConfirmCookLogPhotoUpload(w http.ResponseWriter, r *http.Request) {
+	cookLog, ok := loadOwnCookLog(w, r)
+	if !ok {
+		return
+	}
+
+	var req ConfirmImageUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Dados inválidos")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		response.ValidationError(w, "Dados de confirmação incompletos")
+		return
+	}
+
+	// O public_id precisa ter sido gerado para este registro
+	if !strings.HasPrefix(path.Base(req.PublicID), cookLogPhotoPublicIDPrefix(cookLog)) {
+		response.Error(w, http.StatusForbidden, "Imagem não pertence a este registro")
+		return
+	}
+
+	imageService, serviceErr := storage.ServiceFactory()
+
+	// Se já tinha foto antiga, tentar deletar (best effort)
+	if cookLog.PhotoPublicID != "" && cookLog.PhotoPublicID != req.PublicID && serviceErr == nil {
+		imageService.DeleteImage(r.Context(), cookLog.PhotoPublicID)
+	}
+
+	cookLog.PhotoURL = req.SecureURL
+	cookLog.PhotoPublicID = req.PublicID
+
+	if err := database.DB.Save(cookLog).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update cook log with photo", "cook_log_id", cookLog.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao atualizar registro")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "cook log photo confirmed",
+		"cook_log_id", cookLog.ID,
+		"user_id", cookLog.UserID,
+		"public_id", req.PublicID)
+
+	response.JSON(w, http.StatusOK, cookLog)
+}
+
+// DeleteCookLogPhoto remove a foto de um registro
+func DeleteCookLogPhoto(w http.ResponseWriter, r *http.Request) {
+	cookLog, ok := loadOwnCookLog(w, r)
+	if !ok {
+		return
+	}
+
+	if cookLog.PhotoPublicID == "" {
+		response.Error(w, http.StatusNotFound, "Este registro não possui foto")
+		return
+	}
+
+	imageService, err := storage.ServiceFactory()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to initialize image service", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao configurar serviço de imagens")
+		return
+	}
+
+	if err := imageService.DeleteImage(r.Context(), cookLog.PhotoPublicID); err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete cook log photo", "public_id", cookLog.PhotoPublicID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao deletar imagem")
+		return
+	}
+
+	cookLog.PhotoURL = ""
+	cookLog.PhotoPublicID = ""
+
+	if err := database.DB.Save(cookLog).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update cook log", "cook_log_id", cookLog.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao atualizar registro")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "cook log photo deleted", "cook_log_id", cookLog.ID, "user_id", cookLog.UserID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Imagem removida com sucesso"})
+}
+
+// hideUnavailableCookLogRecipes omite a receita dos registros que quem consulta não pode mais ver
+// O registro continua no histórico; receitas que ficaram privadas ou arquivadas são tratadas como removidas
+func hideUnavailableCookLogRecipes(r *http.Request, cookLogs []models.CookLog) {
+	for i := range cookLogs {
+		if cookLogs[i].Recipe != nil && !canViewRecipe(r, cookLogs[i].Recipe) {
+			cookLogs[i].Recipe = nil
+		}
+	}
+}
+
+// loadOwnCookLog busca o registro da URL; registros de outros usuários não são encontrados
+func loadOwnCookLog(w http.ResponseWriter, r *http.Request) (*models.CookLog, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return nil, false
+	}
+
+	var cookLog models.CookLog
+	if err := database.DB.Where("id = ? AND user_id = ?", chi.URLParam(r, "id"), userID).First(&cookLog).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Cook log not found")
+		return nil, false
+	}
+	return &cookLog, true
+}
+
+// cookLogPhotoPublicIDPrefix é o prefixo do public_id das fotos de um registro
+func cookLogPhotoPublicIDPrefix(cookLog *models.CookLog) string {
+	return fmt.Sprintf("cooklog_%d_", cookLog.ID)
+}
+
+// loadCookCounts preenche cook_count das receitas com uma única consulta
+func loadCookCounts(db *gorm.DB, recipes []models.Recipe) {
+	if len(recipes) == 0 {
+		return
+	}
+	ids := make([]uint, len(recipes))
+	for i := range recipes {
+		ids[i] = recipes[i].ID
+	}
+
+	var counts []struct {
+		RecipeID uint
+		Total    int64
+	}
+	if err := db.Model(&models.CookLog{}).
+		Select("recipe_id, COUNT(*) AS total").
+		Where("recipe_id IN ?", ids).
+		Group("recipe_id").
+		Scan(&counts).Error; err != nil {
+		return
+	}
+
+	byRecipe := make(map[uint]int64, len(counts))
+	for _, c := range counts {
+		byRecipe[c.RecipeID] = c.Total
+	}
+	for i := range recipes {
+		recipes[i].CookCount = byRecipe[recipes[i].ID]
+	}
+}
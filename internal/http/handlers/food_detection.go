@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
 	"github.com/davidsonmarra/receitas-app/internal/models"
@@ -74,7 +76,8 @@ func AnalyzeFood(w http.ResponseWriter, r *http.Request) {
 	jobqueue.GlobalQueue.CreateJob(jobID)
 
 	// Processar imagem em goroutine (assíncrono)
-	go processImageAsync(jobID, imageData, userID)
+	// O handle do banco é capturado aqui para não depender do global durante o processamento
+	go processImageAsync(database.DB, jobID, imageData, userID)
 
 	// Retornar imediatamente com job_id
 	log.InfoCtx(ctx, "análise de alimentos iniciada", "job_id", jobID, "user_id", userID)
@@ -109,7 +112,7 @@ func GetAnalysisResult(w http.ResponseWriter, r *http.Request) {
 }
 
 // processImageAsync processa a imagem em background
-func processImageAsync(jobID string, imageData []byte, userID uint) {
+func processImageAsync(db *gorm.DB, jobID string, imageData []byte, userID uint) {
 	log.Info("processando imagem", "job_id", jobID, "size", len(imageData))
 
 	// Criar cliente Gemini
@@ -120,12 +123,14 @@ func processImageAsync(jobID string, imageData []byte, userID uint) {
 	if err != nil {
 		log.Error("erro ao analisar com Gemini", "job_id", jobID, "error", err)
 		jobqueue.GlobalQueue.FailJob(jobID, fmt.Sprintf("Erro ao analisar imagem: %v", err))
+		saveFoodAnalysis(db, jobID, userID, models.FoodAnalysisStatusFailed, nil, "Erro ao analisar imagem")
 		return
 	}
 
 	if len(detected.Foods) == 0 {
 		log.Warn("nenhum alimento detectado", "job_id", jobID)
 		jobqueue.GlobalQueue.FailJob(jobID, "Nenhum alimento detectado na imagem")
+		saveFoodAnalysis(db, jobID, userID, models.FoodAnalysisStatusFailed, nil, "Nenhum alimento detectado na imagem")
 		return
 	}
 
@@ -136,7 +141,7 @@ func processImageAsync(jobID string, imageData []byte, userID uint) {
 	for _, food := range detected.Foods {
 		// Buscar no banco de ingredientes
 		var ingredient models.Ingredient
-		result := database.DB.Where("name ILIKE ?", "%"+food.Name+"%").First(&ingredient)
+		result := db.Where("name ILIKE ?", "%"+food.Name+"%").First(&ingredient)
 
 		var calories, protein, carbs, fat float64
 		foundInDB := result.Error == nil
@@ -192,10 +197,35 @@ func processImageAsync(jobID string, imageData []byte, userID uint) {
 
 	// Marcar job como completado
 	jobqueue.GlobalQueue.CompleteJob(jobID, finalResult)
+	saveFoodAnalysis(db, jobID, userID, models.FoodAnalysisStatusCompleted, finalResult, "")
 
 	log.Info("análise completada", "job_id", jobID, "foods_detected", len(results), "total_calories", totalCalories)
 }
 
+// saveFoodAnalysis persiste o resultado da análise no histórico do usuário
+// Falhas são apenas logadas: o resultado continua disponível na fila em memória
+func saveFoodAnalysis(db *gorm.DB, jobID string, userID uint, status string, result interface{}, errMsg string) {
+	analysis := models.FoodAnalysis{
+		JobID:  jobID,
+		UserID: userID,
+		Status: status,
+		Error:  errMsg,
+	}
+
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			log.Error("erro ao serializar resultado da análise", "job_id", jobID, "error", err)
+			return
+		}
+		analysis.Result = string(data)
+	}
+
+	if err := db.Create(&analysis).Error; err != nil {
+		log.Error("erro ao salvar histórico da análise", "job_id", jobID, "user_id", userID, "error", err)
+	}
+}
+
 // roundToOneDecimal arredonda para uma casa decimal
 func roundToOneDecimal(value float64) float64 {
 	return float64(int(value*10+0.5)) / 10
@@ -7,10 +7,13 @@ import (
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
 
 	"github.com/davidsonmarra/receitas-app/internal/models"
 	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/dietary"
 	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
 	"github.com/davidsonmarra/receitas-app/pkg/pagination"
 	"github.com/davidsonmarra/receitas-app/pkg/response"
 	"github.com/davidsonmarra/receitas-app/pkg/validation"
@@ -170,7 +173,7 @@ func GetIngredient(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var ingredient models.Ingredient
-	if err := database.DB.First(&ingredient, id).Error; err != nil {
+	if err := database.DB.Preload("Nutrients").First(&ingredient, id).Error; err != nil {
 		response.Error(w, http.StatusNotFound, "Ingredient not found")
 		return
 	}
@@ -193,6 +196,12 @@ func CreateIngredient(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := nutrition.ValidateNutrients(ingredient.Nutrients); err != nil {
+		response.ValidationError(w, err.Error())
+		return
+	}
+	normalizeNutrients(ingredient.Nutrients)
+
 	// Normalizar categoria para lowercase
 	ingredient.Category = strings.ToLower(ingredient.Category)
 	if ingredient.Source == "" {
@@ -230,16 +239,106 @@ func UpdateIngredient(w http.ResponseWriter, r *http.Request) {
 		updateData["category"] = strings.ToLower(category)
 	}
 
-	if err := database.DB.Model(&ingredient).Updates(updateData).Error; err != nil {
-		log.ErrorCtx(r.Context(), "failed to update ingredient", "error", err)
+	// Perfil de nutrientes, se enviado, substitui o atual
+	var nutrients []models.IngredientNutrient
+	rawNutrients, replaceNutrients := updateData["nutrients"]
+	delete(updateData, "nutrients")
+	if replaceNutrients {
+		encoded, _ := json.Marshal(rawNutrients)
+		if err := json.Unmarshal(encoded, &nutrients); err != nil {
+			response.ValidationError(w, "Formato de nutrientes inválido.")
+			return
+		}
+		if err := nutrition.ValidateNutrients(nutrients); err != nil {
+			response.ValidationError(w, err.Error())
+			return
+		}
+		normalizeNutrients(nutrients)
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if len(updateData) > 0 {
+			if err := tx.Model(&ingredient).Updates(updateData).Error; err != nil {
+				return err
+			}
+		}
+		if replaceNutrients {
+			if err := replaceIngredientNutrients(tx, ingredient.ID, nutrients); err != nil {
+				return err
+			}
+		}
+
+		// Flags alimentares alteradas mudam a classificação das receitas que usam o ingrediente
+		if touchesDietaryFlags(updateData) {
+			if err := dietary.RecomputeForIngredient(tx, ingredient.ID); err != nil {
+				return err
+			}
+		}
+
+		// Macronutrientes alterados mudam a nutrição por porção das receitas que usam o ingrediente
+		if touchesMacros(updateData) {
+			return nutrition.RecomputeForIngredient(tx, ingredient.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to update ingredient", "ingredient_id", ingredient.ID, "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to update ingredient")
 		return
 	}
 
+	database.DB.Preload("Nutrients").First(&ingredient, ingredient.ID)
+
 	log.InfoCtx(r.Context(), "ingredient updated", "id", ingredient.ID)
 	response.JSON(w, http.StatusOK, ingredient)
 }
 
+// normalizeNutrients aplica o status padrão (medido) às medições sem status
+func normalizeNutrients(nutrients []models.IngredientNutrient) {
+	for i := range nutrients {
+		if nutrients[i].Status == "" {
+			nutrients[i].Status = models.NutrientStatusMeasured
+		}
+	}
+}
+
+// replaceIngredientNutrients substitui o perfil de nutrientes de um ingrediente
+func replaceIngredientNutrients(tx *gorm.DB, ingredientID uint, nutrients []models.IngredientNutrient) error {
+	if err := tx.Where("ingredient_id = ?", ingredientID).Delete(&models.IngredientNutrient{}).Error; err != nil {
+		return err
+	}
+	for i := range nutrients {
+		nutrients[i].ID = 0
+		nutrients[i].IngredientID = ingredientID
+	}
+	if len(nutrients) == 0 {
+		return nil
+	}
+	return tx.Create(&nutrients).Error
+}
+
+// touchesDietaryFlags indica se a atualização altera alguma flag alimentar do ingrediente
+func touchesDietaryFlags(updateData map[string]interface{}) bool {
+	for _, column := range models.AllergenColumns {
+		if _, ok := updateData[column]; ok {
+			return true
+		}
+	}
+	_, meat := updateData["contains_meat"]
+	_, animal := updateData["animal_origin"]
+	return meat || animal
+}
+
+// touchesMacros indica se a atualização altera algum macronutriente do ingrediente
+func touchesMacros(updateData map[string]interface{}) bool {
+	for macro := range nutrition.MacroColumns {
+		if _, ok := updateData[macro]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // DeleteIngredient remove um ingrediente (admin only)
 func DeleteIngredient(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -253,7 +352,27 @@ func DeleteIngredient(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := database.DB.Delete(&models.Ingredient{}, id).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ingredient_id = ?", id).Delete(&models.IngredientNutrient{}).Error; err != nil {
+			return err
+		}
+		// Itens de listas de compras mantêm nome e quantidade, sem o vínculo
+		if err := tx.Model(&models.ShoppingListItem{}).Where("ingredient_id = ?", id).UpdateColumn("ingredient_id", nil).Error; err != nil {
+			return err
+		}
+		// Lotes de despensa não fazem sentido sem o ingrediente
+		if err := tx.Where("ingredient_id = ?", id).Delete(&models.PantryItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ingredient_id = ?", id).Delete(&models.IngredientPrice{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ingredient_id = ? OR substitute_id = ?", id, id).Delete(&models.IngredientSubstitution{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Ingredient{}, id).Error
+	})
+	if err != nil {
 		log.ErrorCtx(r.Context(), "failed to delete ingredient", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to delete ingredient")
 		return
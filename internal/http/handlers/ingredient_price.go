@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/cost"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/units"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// CreateIngredientPriceRequest representa um preço cadastrado pelo admin
+type CreateIngredientPriceRequest struct {
+	Region        string  `json:"region" validate:"omitempty,alpha,len=2"` // UF; padrão BR (referência nacional)
+	Price         float64 `json:"price" validate:"required,gt=0"`
+	Quantity      float64 `json:"quantity" validate:"omitempty,gt=0"` // Padrão: 1
+	Unit          string  `json:"unit" validate:"required,max=50"`
+	EffectiveFrom string  `json:"effective_from"` // YYYY-MM-DD; padrão: hoje
+}
+
+// ListIngredientPrices lista o histórico de preços de um ingrediente (admin only)
+// Filtro opcional: ?region=SP
+func ListIngredientPrices(w http.ResponseWriter, r *http.Request) {
+	var ingredient models.Ingredient
+	if err := database.DB.First(&ingredient, chi.URLParam(r, "id")).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Ingredient not found")
+		return
+	}
+
+	query := database.DB.Where("ingredient_id = ?", ingredient.ID)
+	if region := r.URL.Query().Get("region"); region != "" {
+		query = query.Where("region = ?", strings.ToUpper(region))
+	}
+
+	var prices []models.IngredientPrice
+	if err := query.Order("region ASC, effective_from DESC, id DESC").Find(&prices).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list ingredient prices", "ingredient_id", ingredient.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list prices")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"ingredient_id": ingredient.ID,
+		"currency":      cost.Currency,
+		"prices":        prices,
+	})
+}
+
+// CreateIngredientPrice cadastra um preço do ingrediente para uma região a partir de uma data (admin only)
+// O preço é convertido para a unidade base (R$/g, R$/ml ou R$/un) usada no cálculo de custo das receitas
+func CreateIngredientPrice(w http.ResponseWriter, r *http.Request) {
+	var ingredient models.Ingredient
+	if err := database.DB.First(&ingredient, chi.URLParam(r, "id")).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Ingredient not found")
+		return
+	}
+
+	var req CreateIngredientPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if req.Region == "" {
+		req.Region = models.DefaultPriceRegion
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+	if req.EffectiveFrom == "" {
+		req.EffectiveFrom = time.Now().Format(models.DateLayout)
+	} else if _, err := time.Parse(models.DateLayout, req.EffectiveFrom); err != nil {
+		response.ValidationError(w, "Data de vigência inválida. Use o formato YYYY-MM-DD.")
+		return
+	}
+
+	baseQuantity, baseUnit := units.Normalize(req.Quantity, strings.TrimSpace(req.Unit))
+	price := models.IngredientPrice{
+		IngredientID:  ingredient.ID,
+		Region:        strings.ToUpper(req.Region),
+		Price:         req.Price,
+		Quantity:      req.Quantity,
+		Unit:          strings.TrimSpace(req.Unit),
+		UnitPrice:     req.Price / baseQuantity,
+		BaseUnit:      baseUnit,
+		EffectiveFrom: req.EffectiveFrom,
+	}
+	// Preços já em vigor são aplicados abaixo; os com data futura ficam para o job de recálculo
+	if req.EffectiveFrom <= time.Now().Format(models.DateLayout) {
+		now := time.Now()
+		price.AppliedAt = &now
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&price).Error; err != nil {
+			return err
+		}
+		// O custo por porção gravado nas receitas usa a referência nacional
+		if price.Region != models.DefaultPriceRegion {
+			return nil
+		}
+		return cost.RecomputeForIngredient(tx, ingredient.ID)
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to create ingredient price", "ingredient_id", ingredient.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create price")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "ingredient price created",
+		"ingredient_id", ingredient.ID,
+		"price_id", price.ID,
+		"region", price.Region,
+		"effective_from", price.EffectiveFrom)
+	response.JSON(w, http.StatusCreated, price)
+}
+
+// DeleteIngredientPrice remove um preço cadastrado por engano (admin only)
+func DeleteIngredientPrice(w http.ResponseWriter, r *http.Request) {
+	var price models.IngredientPrice
+	if err := database.DB.Where("ingredient_id = ?", chi.URLParam(r, "id")).
+		First(&price, chi.URLParam(r, "price_id")).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Price not found")
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&price).Error; err != nil {
+			return err
+		}
+		if price.Region != models.DefaultPriceRegion {
+			return nil
+		}
+		return cost.RecomputeForIngredient(tx, price.IngredientID)
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete ingredient price", "price_id", price.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete price")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "ingredient price deleted", "ingredient_id", price.IngredientID, "price_id", price.ID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Price deleted"})
+}
+
+// GetRecipeCost estima o custo da receita a partir dos preços vigentes hoje
+// ?region=SP usa os preços da UF e, na falta, a referência nacional (BR)
+func GetRecipeCost(w http.ResponseWriter, r *http.Request) {
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, chi.URLParam(r, "id")).Error; err != nil || !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	region := strings.ToUpper(r.URL.Query().Get("region"))
+	if region == "" {
+		region = models.DefaultPriceRegion
+	}
+	if !isPriceRegion(region) {
+		response.ValidationError(w, "Região inválida. Use a sigla da UF (ex.: SP) ou BR.")
+		return
+	}
+
+	var items []models.RecipeIngredient
+	if err := database.DB.Preload("Ingredient").
+		Where("recipe_id = ?", recipe.ID).
+		Order("\"order\" ASC, id ASC").
+		Find(&items).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to load recipe ingredients", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to estimate cost")
+		return
+	}
+
+	ingredientIDs := make([]uint, len(items))
+	for i, item := range items {
+		ingredientIDs[i] = item.IngredientID
+	}
+	prices, err := cost.CurrentPrices(database.DB, ingredientIDs, region, time.Now())
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to load ingredient prices", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to estimate cost")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, cost.Calculate(items, prices, region, recipe.Servings))
+}
+
+// isPriceRegion valida a sigla da região de preço (UF ou BR)
+func isPriceRegion(region string) bool {
+	if len(region) != 2 {
+		return false
+	}
+	for _, c := range region {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/substitution"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// Quantidade de sugestões por ingrediente
+const (
+	maxIngredientSubstitutes = 10
+	maxRecipeSwapOptions     = 5
+)
+
+// CreateSubstitutionRequest representa uma substituição curada cadastrada pelo admin
+type CreateSubstitutionRequest struct {
+	SubstituteID uint    `json:"substitute_id" validate:"required"`
+	Ratio        float64 `json:"ratio" validate:"omitempty,gt=0"` // Padrão: 1
+	Notes        string  `json:"notes" validate:"max=500"`
+}
+
+// SwapOption é um substituto com a quantidade já ajustada para a linha da receita
+type SwapOption struct {
+	substitution.Substitute
+	Quantity float64 `json:"quantity"`
+}
+
+// RecipeSwap é um ingrediente da receita que viola as restrições, com os substitutos que as atendem
+type RecipeSwap struct {
+	RecipeIngredientID uint              `json:"recipe_ingredient_id"`
+	Ingredient         models.Ingredient `json:"ingredient"`
+	Quantity           float64           `json:"quantity"`
+	Unit               string            `json:"unit"`
+	Violations         []string          `json:"violations"` // Dietas e alérgenos violados
+	Substitutes        []SwapOption      `json:"substitutes"`
+}
+
+// ListIngredientSubstitutes sugere substitutos para um ingrediente
+// Filtros opcionais: ?diet=vegan e ?exclude_allergens=lactose restringem os substitutos
+func ListIngredientSubstitutes(w http.ResponseWriter, r *http.Request) {
+	var ingredient models.Ingredient
+	if err := database.DB.First(&ingredient, chi.URLParam(r, "id")).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Ingredient not found")
+		return
+	}
+
+	diets, allergens, err := parseDietParams(r.URL.Query())
+	if err != nil {
+		response.ValidationError(w, err.Error())
+		return
+	}
+
+	substitutes, err := substitution.Find(database.DB, &ingredient,
+		substitution.Constraints{Diets: diets, ExcludeAllergens: allergens}, maxIngredientSubstitutes)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to find substitutes", "ingredient_id", ingredient.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to find substitutes")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"ingredient":  ingredient,
+		"substitutes": substitutes,
+	})
+}
+
+// GetRecipeSubstitutions propõe trocas para a receita atender a uma restrição alimentar
+// ?diet=vegan e/ou ?exclude_allergens=egg (ao menos um é obrigatório)
+func GetRecipeSubstitutions(w http.ResponseWriter, r *http.Request) {
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, chi.URLParam(r, "id")).Error; err != nil || !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	diets, allergens, err := parseDietParams(r.URL.Query())
+	if err != nil {
+		response.ValidationError(w, err.Error())
+		return
+	}
+	constraints := substitution.Constraints{Diets: diets, ExcludeAllergens: allergens}
+	if constraints.Empty() {
+		response.ValidationError(w, "Informe a restrição: diet e/ou exclude_allergens.")
+		return
+	}
+
+	var items []models.RecipeIngredient
+	if err := database.DB.Preload("Ingredient").
+		Where("recipe_id = ?", recipe.ID).
+		Order("\"order\" ASC, id ASC").
+		Find(&items).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to load recipe ingredients", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to find substitutions")
+		return
+	}
+
+	swaps := []RecipeSwap{}
+	satisfiable := true
+	for _, item := range items {
+		violations := constraints.Violations(&item.Ingredient)
+		if len(violations) == 0 {
+			continue
+		}
+
+		substitutes, err := substitution.Find(database.DB, &item.Ingredient, constraints, maxRecipeSwapOptions)
+		if err != nil {
+			log.ErrorCtx(r.Context(), "failed to find substitutes", "ingredient_id", item.IngredientID, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to find substitutions")
+			return
+		}
+
+		options := make([]SwapOption, len(substitutes))
+		for i, sub := range substitutes {
+			options[i] = SwapOption{Substitute: sub, Quantity: item.Quantity * sub.Ratio}
+		}
+		if len(options) == 0 {
+			satisfiable = false
+		}
+
+		swaps = append(swaps, RecipeSwap{
+			RecipeIngredientID: item.ID,
+			Ingredient:         item.Ingredient,
+			Quantity:           item.Quantity,
+			Unit:               item.Unit,
+			Violations:         violations,
+			Substitutes:        options,
+		})
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"recipe_id":         recipe.ID,
+		"diets":             diets,
+		"exclude_allergens": allergens,
+		"swaps":             swaps,
+		"satisfiable":       satisfiable, // Toda linha que viola a restrição tem ao menos um substituto
+	})
+}
+
+// CreateIngredientSubstitution cadastra uma substituição curada (admin only)
+func CreateIngredientSubstitution(w http.ResponseWriter, r *http.Request) {
+	var ingredient models.Ingredient
+	if err := database.DB.First(&ingredient, chi.URLParam(r, "id")).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Ingredient not found")
+		return
+	}
+
+	var req CreateSubstitutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if req.SubstituteID == ingredient.ID {
+		response.ValidationError(w, "O ingrediente não pode substituir a si mesmo.")
+		return
+	}
+
+	var substitute models.Ingredient
+	if err := database.DB.First(&substitute, req.SubstituteID).Error; err != nil {
+		response.ValidationError(w, "Ingrediente substituto não encontrado.")
+		return
+	}
+
+	var count int64
+	database.DB.Model(&models.IngredientSubstitution{}).
+		Where("ingredient_id = ? AND substitute_id = ?", ingredient.ID, substitute.ID).
+		Count(&count)
+	if count > 0 {
+		response.Error(w, http.StatusConflict, "Substituição já cadastrada")
+		return
+	}
+
+	if req.Ratio == 0 {
+		req.Ratio = 1
+	}
+	sub := models.IngredientSubstitution{
+		IngredientID: ingredient.ID,
+		SubstituteID: substitute.ID,
+		Substitute:   substitute,
+		Ratio:        req.Ratio,
+		Notes:        req.Notes,
+	}
+	if err := database.DB.Omit("Substitute").Create(&sub).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to create substitution", "ingredient_id", ingredient.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create substitution")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "ingredient substitution created",
+		"ingredient_id", ingredient.ID,
+		"substitute_id", substitute.ID,
+		"ratio", sub.Ratio)
+	response.JSON(w, http.StatusCreated, sub)
+}
+
+// DeleteIngredientSubstitution remove uma substituição curada (admin only)
+func DeleteIngredientSubstitution(w http.ResponseWriter, r *http.Request) {
+	var sub models.IngredientSubstitution
+	if err := database.DB.Where("ingredient_id = ?", chi.URLParam(r, "id")).
+		First(&sub, chi.URLParam(r, "substitution_id")).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Substitution not found")
+		return
+	}
+
+	if err := database.DB.Delete(&sub).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete substitution", "substitution_id", sub.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete substitution")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "ingredient substitution deleted", "ingredient_id", sub.IngredientID, "substitution_id", sub.ID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Substitution deleted"})
+}
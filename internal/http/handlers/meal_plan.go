@@ -0,0 +1,732 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// MealPlanRequest representa os dados para criar ou renomear um planejamento
+type MealPlanRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+// MealPlanSlotRequest representa uma receita a ser planejada em uma refeição
+type MealPlanSlotRequest struct {
+	Date     string `json:"date" validate:"required"`
+	MealType string `json:"meal_type" validate:"required,oneof=breakfast lunch dinner snack"`
+	RecipeID uint   `json:"recipe_id" validate:"required"`
+	Servings int    `json:"servings" validate:"omitempty,min=1,max=50"` // Padrão: 1
+	Notes    string `json:"notes" validate:"max=500"`
+}
+
+// UpdateMealPlanSlotRequest representa os campos editáveis de uma refeição planejada
+type UpdateMealPlanSlotRequest struct {
+	Date     *string `json:"date"`
+	MealType *string `json:"meal_type" validate:"omitempty,oneof=breakfast lunch dinner snack"`
+	RecipeID *uint   `json:"recipe_id" validate:"omitempty,min=1"`
+	Servings *int    `json:"servings" validate:"omitempty,min=1,max=50"`
+	Notes    *string `json:"notes" validate:"omitempty,max=500"`
+}
+
+// CopyMealPlanWeekRequest representa a cópia de uma semana do plano para outra
+// As datas podem ser qualquer dia da semana: ambas são normalizadas para a segunda-feira
+type CopyMealPlanWeekRequest struct {
+	From    string `json:"from" validate:"required"`
+	To      string `json:"to" validate:"required"`
+	Replace bool   `json:"replace"` // Remove as refeições já planejadas na semana de destino
+}
+
+// AddMealPlanMemberRequest representa o convite de um membro da casa
+type AddMealPlanMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// MealPlanDay são os totais nutricionais de um dia do plano
+type MealPlanDay struct {
+	Date  string           `json:"date"`
+	Slots int              `json:"slots"`
+	Total nutrition.Macros `json:"total"`
+}
+
+// ListMealPlans lista os planejamentos do usuário: os próprios e os compartilhados com ele
+func ListMealPlans(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var plans []models.MealPlan
+	if err := database.DB.
+		Where("user_id = ? OR id IN (?)", userID,
+			database.DB.Model(&models.MealPlanMember{}).Select("meal_plan_id").Where("user_id = ?", userID)).
+		Preload("Members").
+		Order("created_at DESC").
+		Find(&plans).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list meal plans", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list meal plans")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"meal_plans": plans,
+	})
+}
+
+// CreateMealPlan cria um planejamento vazio para o usuário autenticado
+func CreateMealPlan(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var req MealPlanRequest
+	if !decodeMealPlanRequest(w, r, &req) {
+		return
+	}
+
+	plan := models.MealPlan{UserID: userID, Name: strings.TrimSpace(req.Name)}
+	if err := database.DB.Create(&plan).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to create meal plan", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create meal plan")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "meal plan created", "meal_plan_id", plan.ID, "user_id", userID)
+	response.JSON(w, http.StatusCreated, plan)
+}
+
+// GetMealPlan retorna o planejamento com membros e refeições
+// Filtros opcionais de período: ?from=YYYY-MM-DD&to=YYYY-MM-DD (inclusivos)
+func GetMealPlan(w http.ResponseWriter, r *http.Request) {
+	plan, _, ok := loadMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	for _, value := range []string{from, to} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(models.DateLayout, value); err != nil {
+			response.ValidationError(w, "Data inválida. Use o formato YYYY-MM-DD.")
+			return
+		}
+	}
+
+	slots := func(db *gorm.DB) *gorm.DB {
+		if from != "" {
+			db = db.Where("date >= ?", from)
+		}
+		if to != "" {
+			db = db.Where("date <= ?", to)
+		}
+		return db.Order("date ASC, id ASC")
+	}
+
+	if err := database.DB.
+		Preload("Members.User").
+		Preload("Slots", slots).
+		Preload("Slots.Recipe").
+		First(plan, plan.ID).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to load meal plan", "meal_plan_id", plan.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to load meal plan")
+		return
+	}
+	hideUnavailableSlotRecipes(r, plan.Slots)
+
+	response.JSON(w, http.StatusOK, plan)
+}
+
+// UpdateMealPlan renomeia o planejamento (apenas o dono)
+func UpdateMealPlan(w http.ResponseWriter, r *http.Request) {
+	plan, userID, ok := loadOwnedMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	var req MealPlanRequest
+	if !decodeMealPlanRequest(w, r, &req) {
+		return
+	}
+
+	if err := database.DB.Model(plan).Update("name", strings.TrimSpace(req.Name)).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update meal plan", "meal_plan_id", plan.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update meal plan")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "meal plan updated", "meal_plan_id", plan.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, plan)
+}
+
+// DeleteMealPlan remove o planejamento com suas refeições e membros (apenas o dono)
+func DeleteMealPlan(w http.ResponseWriter, r *http.Request) {
+	plan, userID, ok := loadOwnedMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("meal_plan_id = ?", plan.ID).Delete(&models.MealPlanSlot{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("meal_plan_id = ?", plan.ID).Delete(&models.MealPlanMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(plan).Error
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete meal plan", "meal_plan_id", plan.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete meal plan")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "meal plan deleted", "meal_plan_id", plan.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "Planejamento removido com sucesso",
+	})
+}
+
+// AddMealPlanSlot planeja uma receita em uma refeição do plano (dono ou membros)
+func AddMealPlanSlot(w http.ResponseWriter, r *http.Request) {
+	plan, userID, ok := loadMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	var req MealPlanSlotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if _, err := time.Parse(models.DateLayout, req.Date); err != nil {
+		response.ValidationError(w, "Data inválida. Use o formato YYYY-MM-DD.")
+		return
+	}
+
+	recipe, ok := loadPlannableRecipe(w, r, req.RecipeID)
+	if !ok {
+		return
+	}
+
+	if req.Servings == 0 {
+		req.Servings = 1
+	}
+
+	slot := models.MealPlanSlot{
+		MealPlanID: plan.ID,
+		Date:       req.Date,
+		MealType:   req.MealType,
+		RecipeID:   recipe.ID,
+		Servings:   req.Servings,
+		Notes:      strings.TrimSpace(req.Notes),
+	}
+	if err := database.DB.Create(&slot).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to create meal plan slot", "meal_plan_id", plan.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to add meal")
+		return
+	}
+	slot.Recipe = recipe
+
+	log.InfoCtx(r.Context(), "meal plan slot added", "meal_plan_id", plan.ID, "slot_id", slot.ID, "user_id", userID)
+	response.JSON(w, http.StatusCreated, slot)
+}
+
+// UpdateMealPlanSlot altera uma refeição planejada (dono ou membros)
+func UpdateMealPlanSlot(w http.ResponseWriter, r *http.Request) {
+	plan, userID, ok := loadMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	slot, ok := loadMealPlanSlot(w, r, plan)
+	if !ok {
+		return
+	}
+
+	var req UpdateMealPlanSlotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if req.Date != nil {
+		if _, err := time.Parse(models.DateLayout, *req.Date); err != nil {
+			response.ValidationError(w, "Data inválida. Use o formato YYYY-MM-DD.")
+			return
+		}
+		slot.Date = *req.Date
+	}
+	if req.MealType != nil {
+		slot.MealType = *req.MealType
+	}
+	if req.RecipeID != nil && *req.RecipeID != slot.RecipeID {
+		recipe, ok := loadPlannableRecipe(w, r, *req.RecipeID)
+		if !ok {
+			return
+		}
+		slot.RecipeID = recipe.ID
+	}
+	if req.Servings != nil {
+		slot.Servings = *req.Servings
+	}
+	if req.Notes != nil {
+		slot.Notes = strings.TrimSpace(*req.Notes)
+	}
+
+	if err := database.DB.Select("date", "meal_type", "recipe_id", "servings", "notes").Save(slot).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update meal plan slot", "slot_id", slot.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update meal")
+		return
+	}
+
+	database.DB.Preload("Recipe").First(slot, slot.ID)
+	hideUnavailableSlotRecipe(r, slot)
+
+	log.InfoCtx(r.Context(), "meal plan slot updated", "meal_plan_id", plan.ID, "slot_id", slot.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, slot)
+}
+
+// DeleteMealPlanSlot remove uma refeição planejada (dono ou membros)
+func DeleteMealPlanSlot(w http.ResponseWriter, r *http.Request) {
+	plan, userID, ok := loadMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	slot, ok := loadMealPlanSlot(w, r, plan)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(slot).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete meal plan slot", "slot_id", slot.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete meal")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "meal plan slot deleted", "meal_plan_id", plan.ID, "slot_id", slot.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "Refeição removida com sucesso",
+	})
+}
+
+// CopyMealPlanWeek copia as refeições de uma semana (segunda a domingo) para outra
+// Sem replace, as refeições copiadas são somadas às já planejadas no destino
+func CopyMealPlanWeek(w http.ResponseWriter, r *http.Request) {
+	plan, userID, ok := loadMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	var req CopyMealPlanWeekRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	from, errFrom := time.Parse(models.DateLayout, req.From)
+	to, errTo := time.Parse(models.DateLayout, req.To)
+	if errFrom != nil || errTo != nil {
+		response.ValidationError(w, "Datas inválidas. Use o formato YYYY-MM-DD.")
+		return
+	}
+	from, to = weekStart(from), weekStart(to)
+	if from.Equal(to) {
+		response.ValidationError(w, "As semanas de origem e destino devem ser diferentes.")
+		return
+	}
+
+	var copied []models.MealPlanSlot
+	skipped := 0
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var source []models.MealPlanSlot
+		if err := weekSlots(tx, plan.ID, from).Preload("Recipe").Order("date ASC, id ASC").Find(&source).Error; err != nil {
+			return err
+		}
+		hideUnavailableSlotRecipes(r, source)
+
+		if req.Replace {
+			if err := weekSlots(tx, plan.ID, to).Delete(&models.MealPlanSlot{}).Error; err != nil {
+				return err
+			}
+		}
+
+		offset := to.Sub(from)
+		for _, slot := range source {
+			// Receitas removidas ou que deixaram de ser visíveis não são replanejadas
+			if slot.Recipe == nil {
+				skipped++
+				continue
+			}
+			date, _ := time.Parse(models.DateLayout, slot.Date)
+			copied = append(copied, models.MealPlanSlot{
+				MealPlanID: plan.ID,
+				Date:       date.Add(offset).Format(models.DateLayout),
+				MealType:   slot.MealType,
+				RecipeID:   slot.RecipeID,
+				Servings:   slot.Servings,
+				Notes:      slot.Notes,
+			})
+		}
+		if len(copied) == 0 {
+			return nil
+		}
+		return tx.Create(&copied).Error
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to copy meal plan week", "meal_plan_id", plan.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to copy week")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "meal plan week copied", "meal_plan_id", plan.ID, "user_id", userID,
+		"from", from.Format(models.DateLayout), "to", to.Format(models.DateLayout), "slots", len(copied), "skipped", skipped)
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"from":    from.Format(models.DateLayout),
+		"to":      to.Format(models.DateLayout),
+		"copied":  len(copied),
+		"skipped": skipped,
+	})
+}
+
+// GetMealPlanNutrition calcula os totais nutricionais por dia e da semana (?week=YYYY-MM-DD, padrão: semana atual)
+// Cada refeição contribui com (total da receita / porções da receita) * porções planejadas,
+// usando o mesmo cálculo por 100g de GetRecipeNutrition
+func GetMealPlanNutrition(w http.ResponseWriter, r *http.Request) {
+	plan, _, ok := loadMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	start := weekStart(time.Now())
+	if week := r.URL.Query().Get("week"); week != "" {
+		parsed, err := time.Parse(models.DateLayout, week)
+		if err != nil {
+			response.ValidationError(w, "Semana inválida. Use o formato YYYY-MM-DD.")
+			return
+		}
+		start = weekStart(parsed)
+	}
+
+	var slots []models.MealPlanSlot
+	if err := weekSlots(database.DB, plan.ID, start).Preload("Recipe").Find(&slots).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to load meal plan slots", "meal_plan_id", plan.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to calculate nutrition")
+		return
+	}
+	hideUnavailableSlotRecipes(r, slots)
+
+	recipeIDs := make([]uint, 0, len(slots))
+	for _, slot := range slots {
+		if slot.Recipe != nil {
+			recipeIDs = append(recipeIDs, slot.RecipeID)
+		}
+	}
+
+	var items []models.RecipeIngredient
+	if len(recipeIDs) > 0 {
+		if err := database.DB.Where("recipe_id IN ?", recipeIDs).Preload("Ingredient").Find(&items).Error; err != nil {
+			log.ErrorCtx(r.Context(), "failed to load recipe ingredients", "meal_plan_id", plan.ID, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to calculate nutrition")
+			return
+		}
+	}
+
+	byRecipe := make(map[uint][]models.RecipeIngredient)
+	for _, item := range items {
+		byRecipe[item.RecipeID] = append(byRecipe[item.RecipeID], item)
+	}
+
+	days := make([]MealPlanDay, 7)
+	index := make(map[string]int, 7)
+	for i := range days {
+		days[i].Date = start.AddDate(0, 0, i).Format(models.DateLayout)
+		index[days[i].Date] = i
+	}
+
+	// Refeições cuja receita foi removida (ou deixou de ser visível) não entram nos totais
+	skipped := []uint{}
+	var week nutrition.Macros
+	for _, slot := range slots {
+		if slot.Recipe == nil {
+			skipped = append(skipped, slot.ID)
+			continue
+		}
+		meal := nutrition.SumMacros(byRecipe[slot.RecipeID]).Scale(servingsFactor(slot.Servings, slot.Recipe.Servings))
+
+		day := &days[index[slot.Date]]
+		day.Slots++
+		day.Total = day.Total.Add(meal)
+		week = week.Add(meal)
+	}
+
+	// Média diária considera apenas os dias com refeições planejadas
+	plannedDays := 0
+	for _, day := range days {
+		if day.Slots > 0 {
+			plannedDays++
+		}
+	}
+	var average nutrition.Macros
+	if plannedDays > 0 {
+		average = week.Scale(1 / float64(plannedDays))
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"week_start":    days[0].Date,
+		"week_end":      days[6].Date,
+		"days":          days,
+		"total":         week,
+		"daily_average": average,
+		"planned_days":  plannedDays,
+		"skipped_slots": skipped,
+	})
+}
+
+// AddMealPlanMember compartilha o planejamento com outro usuário, pelo e-mail (apenas o dono)
+func AddMealPlanMember(w http.ResponseWriter, r *http.Request) {
+	plan, userID, ok := loadOwnedMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	var req AddMealPlanMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	var member models.User
+	if err := database.DB.Where("email = ?", strings.TrimSpace(req.Email)).First(&member).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Usuário não encontrado")
+		return
+	}
+	if member.ID == plan.UserID {
+		response.ValidationError(w, "O dono do planejamento não pode ser adicionado como membro.")
+		return
+	}
+
+	var existing int64
+	database.DB.Model(&models.MealPlanMember{}).Where("meal_plan_id = ? AND user_id = ?", plan.ID, member.ID).Count(&existing)
+	if existing > 0 {
+		response.Error(w, http.StatusConflict, "Usuário já é membro deste planejamento")
+		return
+	}
+
+	membership := models.MealPlanMember{MealPlanID: plan.ID, UserID: member.ID}
+	if err := database.DB.Create(&membership).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to add meal plan member", "meal_plan_id", plan.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to add member")
+		return
+	}
+	membership.User = &member
+
+	log.InfoCtx(r.Context(), "meal plan member added", "meal_plan_id", plan.ID, "member_id", member.ID, "user_id", userID)
+	response.JSON(w, http.StatusCreated, membership)
+}
+
+// RemoveMealPlanMember remove um membro do planejamento
+// O dono remove qualquer membro; um membro pode sair do plano removendo a si mesmo
+func RemoveMealPlanMember(w http.ResponseWriter, r *http.Request) {
+	plan, userID, ok := loadMealPlan(w, r)
+	if !ok {
+		return
+	}
+
+	memberID := chi.URLParam(r, "user_id")
+	if plan.UserID != userID && memberID != strconv.FormatUint(uint64(userID), 10) {
+		response.Error(w, http.StatusForbidden, "Apenas o dono pode remover outros membros")
+		return
+	}
+
+	result := database.DB.Where("meal_plan_id = ? AND user_id = ?", plan.ID, memberID).Delete(&models.MealPlanMember{})
+	if result.Error != nil {
+		log.ErrorCtx(r.Context(), "failed to remove meal plan member", "meal_plan_id", plan.ID, "error", result.Error)
+		response.Error(w, http.StatusInternalServerError, "Failed to remove member")
+		return
+	}
+	if result.RowsAffected == 0 {
+		response.Error(w, http.StatusNotFound, "Membro não encontrado")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "meal plan member removed", "meal_plan_id", plan.ID, "member_id", memberID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "Membro removido com sucesso",
+	})
+}
+
+// loadMealPlan carrega o planejamento da URL se o usuário for o dono ou membro
+// Para quem não tem acesso, o plano é tratado como inexistente (404)
+func loadMealPlan(w http.ResponseWriter, r *http.Request) (*models.MealPlan, uint, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return nil, 0, false
+	}
+
+	var plan models.MealPlan
+	if err := database.DB.First(&plan, chi.URLParam(r, "id")).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Meal plan not found")
+		return nil, 0, false
+	}
+
+	if !canAccessMealPlan(&plan, userID) {
+		response.Error(w, http.StatusNotFound, "Meal plan not found")
+		return nil, 0, false
+	}
+
+	return &plan, userID, true
+}
+
+// canAccessMealPlan verifica se o usuário é o dono ou membro do planejamento
+func canAccessMealPlan(plan *models.MealPlan, userID uint) bool {
+	if plan.UserID == userID {
+		return true
+	}
+
+	var count int64
+	database.DB.Model(&models.MealPlanMember{}).Where("meal_plan_id = ? AND user_id = ?", plan.ID, userID).Count(&count)
+	return count > 0
+}
+
+// loadOwnedMealPlan carrega o planejamento exigindo que o usuário seja o dono
+func loadOwnedMealPlan(w http.ResponseWriter, r *http.Request) (*models.MealPlan, uint, bool) {
+	plan, userID, ok := loadMealPlan(w, r)
+	if !ok {
+		return nil, 0, false
+	}
+
+	if plan.UserID != userID {
+		response.Error(w, http.StatusForbidden, "Apenas o dono pode gerenciar o planejamento")
+		return nil, 0, false
+	}
+
+	return plan, userID, true
+}
+
+// loadMealPlanSlot carrega a refeição da URL, garantindo que pertence ao plano
+func loadMealPlanSlot(w http.ResponseWriter, r *http.Request, plan *models.MealPlan) (*models.MealPlanSlot, bool) {
+	var slot models.MealPlanSlot
+	if err := database.DB.Where("meal_plan_id = ?", plan.ID).First(&slot, chi.URLParam(r, "slot_id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(w, http.StatusNotFound, "Meal not found")
+			return nil, false
+		}
+		log.ErrorCtx(r.Context(), "failed to load meal plan slot", "meal_plan_id", plan.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to load meal")
+		return nil, false
+	}
+	return &slot, true
+}
+
+// loadPlannableRecipe carrega uma receita que o usuário pode planejar (ver canPlanRecipe)
+func loadPlannableRecipe(w http.ResponseWriter, r *http.Request, recipeID uint) (*models.Recipe, bool) {
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, recipeID).Error; err != nil || !canPlanRecipe(r, &recipe) {
+		response.ValidationError(w, "Receita não encontrada.")
+		return nil, false
+	}
+	return &recipe, true
+}
+
+// canPlanRecipe verifica se a receita pode aparecer nos planejamentos do usuário da requisição
+// Apenas receitas listadas publicamente ou que o usuário pode editar: o link de compartilhamento de
+// uma receita não listada vale só para quem o tem e não fica gravado no plano, então ela não é aceita
+func canPlanRecipe(r *http.Request, recipe *models.Recipe) bool {
+	if recipe.IsPubliclyListed() {
+		return true
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	return ok && canModifyRecipe(recipe, userID)
+}
+
+// hideUnavailableSlotRecipes aplica hideUnavailableSlotRecipe a cada refeição
+func hideUnavailableSlotRecipes(r *http.Request, slots []models.MealPlanSlot) {
+	for i := range slots {
+		hideUnavailableSlotRecipe(r, &slots[i])
+	}
+}
+
+// hideUnavailableSlotRecipe omite a receita da refeição se quem consulta não pode planejá-la
+// Receitas que ficaram privadas, não listadas ou arquivadas depois de planejadas são tratadas como removidas
+func hideUnavailableSlotRecipe(r *http.Request, slot *models.MealPlanSlot) {
+	if slot.Recipe != nil && !canPlanRecipe(r, slot.Recipe) {
+		slot.Recipe = nil
+	}
+}
+
+// decodeMealPlanRequest decodifica e valida o corpo de criação/edição do planejamento
+func decodeMealPlanRequest(w http.ResponseWriter, r *http.Request, req *MealPlanRequest) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return false
+	}
+
+	if errs := validation.ValidateStruct(*req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return false
+	}
+
+	return true
+}
+
+// weekSlots filtra as refeições do plano na semana iniciada em start (segunda a domingo)
+func weekSlots(db *gorm.DB, planID uint, start time.Time) *gorm.DB {
+	end := start.AddDate(0, 0, 6)
+	return db.Where("meal_plan_id = ? AND date BETWEEN ? AND ?", planID,
+		start.Format(models.DateLayout), end.Format(models.DateLayout))
+}
+
+// weekStart retorna a segunda-feira da semana da data (semanas de segunda a domingo)
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
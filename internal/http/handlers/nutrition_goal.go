@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// NutritionGoalRequest define as metas diárias a partir de um preset e/ou de valores explícitos
+// Valores explícitos substituem os do preset; omitidos sem preset ficam sem meta
+type NutritionGoalRequest struct {
+	Preset   string   `json:"preset" validate:"omitempty,max=30"`
+	Calories *float64 `json:"calories" validate:"omitempty,gte=0,lte=10000"`
+	Protein  *float64 `json:"protein" validate:"omitempty,gte=0,lte=1000"`
+	Carbs    *float64 `json:"carbs" validate:"omitempty,gte=0,lte=2000"`
+	Fat      *float64 `json:"fat" validate:"omitempty,gte=0,lte=1000"`
+	Fiber    *float64 `json:"fiber" validate:"omitempty,gte=0,lte=500"`
+}
+
+// ListNutritionGoalPresets lista os presets de metas diárias
+func ListNutritionGoalPresets(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"presets": nutrition.GoalPresets,
+	})
+}
+
+// GetNutritionGoal retorna as metas diárias do usuário autenticado
+func GetNutritionGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	goal, err := loadNutritionGoal(userID)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to load nutrition goals", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to load nutrition goals")
+		return
+	}
+	if goal == nil {
+		response.Error(w, http.StatusNotFound, "Nutrition goals not set")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, goal)
+}
+
+// SetNutritionGoal cria ou substitui as metas diárias do usuário autenticado
+func SetNutritionGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var req NutritionGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	var targets nutrition.Macros
+	if req.Preset != "" {
+		preset, found := nutrition.FindGoalPreset(req.Preset)
+		if !found {
+			response.ValidationError(w, "Preset inválido. Consulte GET /nutrition-goals/presets.")
+			return
+		}
+		targets = preset.Macros
+	}
+
+	customized := false
+	for _, field := range []struct {
+		value  *float64
+		target *float64
+	}{
+		{req.Calories, &targets.Calories},
+		{req.Protein, &targets.Protein},
+		{req.Carbs, &targets.Carbs},
+		{req.Fat, &targets.Fat},
+		{req.Fiber, &targets.Fiber},
+	} {
+		if field.value != nil {
+			*field.target = *field.value
+			customized = true
+		}
+	}
+
+	if targets == (nutrition.Macros{}) {
+		response.ValidationError(w, "Informe um preset ou ao menos uma meta.")
+		return
+	}
+
+	goal, err := loadNutritionGoal(userID)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to load nutrition goals", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to save nutrition goals")
+		return
+	}
+	if goal == nil {
+		goal = &models.NutritionGoal{UserID: userID}
+	}
+
+	// O preset só é mantido quando os valores não foram alterados
+	goal.Preset = req.Preset
+	if customized {
+		goal.Preset = ""
+	}
+	goal.Calories = targets.Calories
+	goal.Protein = targets.Protein
+	goal.Carbs = targets.Carbs
+	goal.Fat = targets.Fat
+	goal.Fiber = targets.Fiber
+
+	if err := database.DB.Save(goal).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to save nutrition goals", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to save nutrition goals")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "nutrition goals saved", "user_id", userID, "preset", goal.Preset)
+	response.JSON(w, http.StatusOK, goal)
+}
+
+// DeleteNutritionGoal remove as metas diárias do usuário autenticado
+func DeleteNutritionGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	result := database.DB.Where("user_id = ?", userID).Delete(&models.NutritionGoal{})
+	if result.Error != nil {
+		log.ErrorCtx(r.Context(), "failed to delete nutrition goals", "user_id", userID, "error", result.Error)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete nutrition goals")
+		return
+	}
+	if result.RowsAffected == 0 {
+		response.Error(w, http.StatusNotFound, "Nutrition goals not set")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "nutrition goals deleted", "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "Metas removidas",
+	})
+}
+
+// loadNutritionGoal busca as metas do usuário (nil quando não definidas)
+func loadNutritionGoal(userID uint) (*models.NutritionGoal, error) {
+	var goal models.NutritionGoal
+	if err := database.DB.Where("user_id = ?", userID).First(&goal).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &goal, nil
+}
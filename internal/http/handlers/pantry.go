@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/pantry"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/units"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// Janelas padrão (em dias) para itens vencendo e sugestões de receitas
+const (
+	defaultExpiringDays = 3
+	defaultSuggestDays  = 7
+	maxExpiringDays     = 30
+)
+
+// AddPantryItemRequest representa um lote adicionado à despensa
+type AddPantryItemRequest struct {
+	IngredientID uint    `json:"ingredient_id" validate:"required"`
+	Quantity     float64 `json:"quantity" validate:"required,gt=0"`
+	Unit         string  `json:"unit" validate:"required,max=50"`
+	ExpiresOn    *string `json:"expires_on"` // YYYY-MM-DD
+}
+
+// ConsumePantryRequest representa o consumo manual de um ingrediente
+type ConsumePantryRequest struct {
+	IngredientID uint    `json:"ingredient_id" validate:"required"`
+	Quantity     float64 `json:"quantity" validate:"required,gt=0"`
+	Unit         string  `json:"unit" validate:"required,max=50"`
+}
+
+// CookRecipeRequest representa o preparo de uma receita
+type CookRecipeRequest struct {
+	Servings int `json:"servings" validate:"omitempty,min=1,max=100"` // Padrão: porções da receita
+}
+
+// ExpiringPantryItem é um lote com a contagem de dias até a validade
+type ExpiringPantryItem struct {
+	models.PantryItem
+	DaysLeft int  `json:"days_left"` // Negativo quando já venceu
+	Expired  bool `json:"expired"`
+}
+
+// ListPantry lista a despensa do usuário, dos lotes que vencem antes aos sem validade
+func ListPantry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var items []models.PantryItem
+	if err := database.DB.Where("user_id = ?", userID).
+		Preload("Ingredient").
+		Order("expires_on IS NULL, expires_on ASC, id ASC").
+		Find(&items).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list pantry", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list pantry")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"items": items,
+	})
+}
+
+// AddPantryItem adiciona um lote à despensa
+// A quantidade é guardada na unidade base (1 kg vira 1000 g) para permitir o consumo em outras unidades
+func AddPantryItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var req AddPantryItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if req.ExpiresOn != nil {
+		if _, err := time.Parse(models.DateLayout, *req.ExpiresOn); err != nil {
+			response.ValidationError(w, "Validade inválida. Use o formato YYYY-MM-DD.")
+			return
+		}
+	}
+
+	var ingredient models.Ingredient
+	if err := database.DB.First(&ingredient, req.IngredientID).Error; err != nil {
+		response.ValidationError(w, "Ingrediente não encontrado.")
+		return
+	}
+
+	quantity, unit := units.Normalize(req.Quantity, strings.TrimSpace(req.Unit))
+	item := models.PantryItem{
+		UserID:       userID,
+		IngredientID: ingredient.ID,
+		Quantity:     quantity,
+		Unit:         unit,
+		ExpiresOn:    req.ExpiresOn,
+	}
+	if err := database.DB.Create(&item).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to add pantry item", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to add pantry item")
+		return
+	}
+	item.Ingredient = &ingredient
+
+	log.InfoCtx(r.Context(), "pantry item added", "pantry_item_id", item.ID, "user_id", userID, "ingredient_id", ingredient.ID)
+	response.JSON(w, http.StatusCreated, item)
+}
+
+// ConsumePantryItem retira uma quantidade do ingrediente, começando pelos lotes que vencem antes
+func ConsumePantryItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var req ConsumePantryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	var consumption pantry.Consumption
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		consumption, err = pantry.Consume(tx, userID, req.IngredientID, req.Quantity, strings.TrimSpace(req.Unit))
+		return err
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to consume pantry item", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to consume pantry item")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "pantry item consumed", "user_id", userID, "ingredient_id", req.IngredientID,
+		"consumed", consumption.Consumed, "missing", consumption.Missing)
+	response.JSON(w, http.StatusOK, consumption)
+}
+
+// DeletePantryItem remove um lote da despensa (ex.: descartado)
+func DeletePantryItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	result := database.DB.Where("user_id = ?", userID).Delete(&models.PantryItem{}, chi.URLParam(r, "item_id"))
+	if result.Error != nil {
+		log.ErrorCtx(r.Context(), "failed to delete pantry item", "user_id", userID, "error", result.Error)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete pantry item")
+		return
+	}
+	if result.RowsAffected == 0 {
+		response.Error(w, http.StatusNotFound, "Pantry item not found")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "pantry item deleted", "user_id", userID, "pantry_item_id", chi.URLParam(r, "item_id"))
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "Item removido da despensa",
+	})
+}
+
+// ListExpiringPantry lista os lotes vencidos ou que vencem nos próximos dias (?days=, padrão 3)
+func ListExpiringPantry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	days, ok := parseDaysParam(w, r, defaultExpiringDays)
+	if !ok {
+		return
+	}
+
+	today := startOfDay(time.Now())
+	until := today.AddDate(0, 0, days).Format(models.DateLayout)
+
+	var items []models.PantryItem
+	if err := database.DB.Where("user_id = ? AND expires_on IS NOT NULL AND expires_on <= ?", userID, until).
+		Preload("Ingredient").
+		Order("expires_on ASC, id ASC").
+		Find(&items).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list expiring pantry items", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list pantry")
+		return
+	}
+
+	expiring := make([]ExpiringPantryItem, 0, len(items))
+	for _, item := range items {
+		expiresOn, _ := time.Parse(models.DateLayout, *item.ExpiresOn)
+		daysLeft := int(expiresOn.Sub(today).Hours() / 24)
+		expiring = append(expiring, ExpiringPantryItem{PantryItem: item, DaysLeft: daysLeft, Expired: daysLeft < 0})
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"days":  days,
+		"items": expiring,
+	})
+}
+
+// SuggestPantryRecipes ordena receitas que aproveitam os ingredientes que vencem nos próximos dias (?days=, padrão 7)
+// Ingredientes já vencidos não entram na sugestão
+func SuggestPantryRecipes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	days, ok := parseDaysParam(w, r, defaultSuggestDays)
+	if !ok {
+		return
+	}
+
+	today := startOfDay(time.Now())
+	matches, err := pantry.RankRecipes(database.DB, userID, today, today.AddDate(0, 0, days), 20)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to rank pantry recipes", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to suggest recipes")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"days":    days,
+		"recipes": matches,
+	})
+}
+
+// CookRecipe registra o preparo de uma receita, retirando da despensa as quantidades dos ingredientes
+// Ingredientes ausentes ou em unidades incompatíveis aparecem em missing, sem impedir o preparo
+func CookRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var recipe models.Recipe
+	if err := database.DB.Preload("Ingredients").First(&recipe, chi.URLParam(r, "id")).Error; err != nil || !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	// O corpo é opcional
+	var req CookRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if req.Servings == 0 {
+		req.Servings = recipe.Servings
+	}
+
+	var consumed []pantry.Consumption
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		consumed, err = pantry.ConsumeRecipe(tx, userID, recipe.Ingredients, servingsFactor(req.Servings, recipe.Servings))
+		return err
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to consume pantry for recipe", "recipe_id", recipe.ID, "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update pantry")
+		return
+	}
+
+	// Conta como "fiz esta receita" nas estatísticas; falha aqui não desfaz a baixa na despensa
+	if _, err := recordRecipeEvent(r, &recipe, models.RecipeEventCook); err != nil {
+		log.ErrorCtx(r.Context(), "failed to record cook event", "recipe_id", recipe.ID, "user_id", userID, "error", err)
+	}
+
+	log.InfoCtx(r.Context(), "recipe cooked", "recipe_id", recipe.ID, "user_id", userID, "servings", req.Servings)
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"recipe_id": recipe.ID,
+		"servings":  req.Servings,
+		"pantry":    consumed,
+	})
+}
+
+// parseDaysParam lê ?days= (1 a 30), usando o padrão quando ausente
+func parseDaysParam(w http.ResponseWriter, r *http.Request, fallback int) (int, bool) {
+	value := r.URL.Query().Get("days")
+	if value == "" {
+		return fallback, true
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 || days > maxExpiringDays {
+		response.ValidationError(w, "days deve ser um número entre 1 e 30.")
+		return 0, false
+	}
+	return days, true
+}
+
+// startOfDay retorna a data sem horário, no mesmo formato das datas de validade
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
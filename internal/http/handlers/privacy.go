@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/pagination"
+	"github.com/davidsonmarra/receitas-app/pkg/privacy"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// DataErasureRequest representa a solicitação de eliminação dos dados pessoais
+type DataErasureRequest struct {
+	Password     string `json:"password" validate:"required"`
+	Confirmation string `json:"confirmation" validate:"required,eq=ELIMINAR"`
+}
+
+// RequestDataExport inicia a exportação assíncrona dos dados pessoais (LGPD)
+func RequestDataExport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	export, err := privacy.RequestExport(userID)
+	if err != nil {
+		if errors.Is(err, privacy.ErrExportInProgress) {
+			response.ErrorWithCode(w, http.StatusConflict, "Já existe uma exportação em andamento", "EXPORT_IN_PROGRESS")
+			return
+		}
+		log.ErrorCtx(r.Context(), "failed to request data export", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao solicitar exportação")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "data export requested", "export_id", export.ID, "user_id", userID)
+
+	response.JSON(w, http.StatusAccepted, map[string]interface{}{
+		"export_id": export.ID,
+		"status":    export.Status,
+		"check_url": fmt.Sprintf("/users/me/data-export/%d", export.ID),
+	})
+}
+
+// GetDataExport consulta o status de uma exportação
+func GetDataExport(w http.ResponseWriter, r *http.Request) {
+	userID, exportID, ok := parseDataExportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	export, err := privacy.GetExport(userID, exportID)
+	if err != nil {
+		writeDataExportError(w, r, err)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"export": export,
+	}
+	if export.Status == models.DataExportStatusReady {
+		resp["download_url"] = fmt.Sprintf("/users/me/data-export/%d/download", export.ID)
+	}
+
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// DownloadDataExport entrega o arquivo da exportação (apenas uma vez)
+func DownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	userID, exportID, ok := parseDataExportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	archive, err := privacy.ConsumeExport(userID, exportID)
+	if err != nil {
+		writeDataExportError(w, r, err)
+		return
+	}
+
+	log.InfoCtx(r.Context(), "data export downloaded", "export_id", exportID, "user_id", userID)
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="meus-dados-%d.zip"`, exportID))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
+
+// parseDataExportRequest extrai o usuário autenticado e o ID da exportação
+func parseDataExportRequest(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return 0, 0, false
+	}
+
+	exportID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		response.ValidationError(w, "ID da exportação inválido")
+		return 0, 0, false
+	}
+
+	return userID, uint(exportID), true
+}
+
+// writeDataExportError converte erros de exportação em respostas HTTP
+func writeDataExportError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, privacy.ErrExportNotFound):
+		response.Error(w, http.StatusNotFound, "Exportação não encontrada")
+	case errors.Is(err, privacy.ErrExportNotReady):
+		response.ErrorWithCode(w, http.StatusConflict, "Exportação ainda não está pronta", "EXPORT_NOT_READY")
+	case errors.Is(err, privacy.ErrExportAlreadyDownloaded):
+		response.ErrorWithCode(w, http.StatusGone, "Exportação já foi baixada. Solicite uma nova.", "EXPORT_ALREADY_DOWNLOADED")
+	case errors.Is(err, privacy.ErrExportExpired):
+		response.ErrorWithCode(w, http.StatusGone, "Exportação expirada. Solicite uma nova.", "EXPORT_EXPIRED")
+	default:
+		log.ErrorCtx(r.Context(), "failed to access data export", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao buscar exportação")
+	}
+}
+
+// RequestDataErasure elimina os dados pessoais do usuário (LGPD, art. 18, VI)
+// Diferente de DELETE /users/me, remove também as receitas do usuário e registra a eliminação
+func RequestDataErasure(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req DataErasureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if !auth.CheckPassword(user.Password, req.Password) {
+		response.Error(w, http.StatusUnauthorized, "Senha incorreta")
+		return
+	}
+
+	record, err := privacy.EraseUser(r.Context(), user)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to erase user data", "user_id", user.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao eliminar dados")
+		return
+	}
+
+	blacklistCurrentAccessToken(r)
+
+	log.InfoCtx(r.Context(), "user data erased", "user_id", user.ID, "erasure_id", record.ID, "policy_version", record.PolicyVersion)
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message":        "Dados pessoais eliminados com sucesso",
+		"erasure_id":     record.ID,
+		"policy_version": record.PolicyVersion,
+		"completed_at":   record.CompletedAt,
+	})
+}
+
+// AdminListDataErasures lista os registros de eliminação para auditoria
+// Filtro opcional ?email= verifica se um endereço já teve seus dados eliminados
+func AdminListDataErasures(w http.ResponseWriter, r *http.Request) {
+	params := pagination.ExtractParams(r)
+	offset := pagination.CalculateOffset(params)
+
+	// Session permite reutilizar a query filtrada no Count e no Find
+	query := database.DB.Model(&models.DataErasure{}).Session(&gorm.Session{})
+	if email := strings.TrimSpace(r.URL.Query().Get("email")); email != "" {
+		query = query.Where("email_hash = ?", auth.HashString(strings.ToLower(email)))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.ErrorCtx(r.Context(), "admin failed to count data erasures", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao buscar eliminações")
+		return
+	}
+
+	var records []models.DataErasure
+	if err := query.Order("completed_at DESC").
+		Limit(params.Limit).
+		Offset(offset).
+		Find(&records).Error; err != nil {
+		log.ErrorCtx(r.Context(), "admin failed to list data erasures", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao buscar eliminações")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, pagination.BuildResponse(records, params, total))
+}
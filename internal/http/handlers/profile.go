@@ -0,0 +1,532 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/mailer"
+	"github.com/davidsonmarra/receitas-app/pkg/privacy"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/storage"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+const (
+	avatarFolder        = "avatars" // pasta no Cloudinary
+	emailChangeTokenTTL = 24 * time.Hour
+)
+
+// ProfileResponse representa o perfil do usuário autenticado
+// Inclui campos privados que não aparecem quando o usuário é exibido publicamente
+type ProfileResponse struct {
+	models.User
+	PendingEmail string `json:"pending_email,omitempty"`
+}
+
+// UpdateProfileRequest representa os dados permitidos para atualização do perfil
+type UpdateProfileRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=3,max=100"`
+	Bio  *string `json:"bio" validate:"omitempty,max=500"`
+}
+
+// ChangePasswordRequest representa a troca de senha
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=128"`
+}
+
+// ChangeEmailRequest representa a solicitação de troca de e-mail
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// VerifyEmailChangeRequest representa a confirmação da troca de e-mail
+type VerifyEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// DeleteAccountRequest representa a exclusão da própria conta
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// loadCurrentUser busca o usuário autenticado, escrevendo a resposta de erro se falhar
+func loadCurrentUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return nil, false
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(w, http.StatusNotFound, "Usuário não encontrado")
+			return nil, false
+		}
+		log.ErrorCtx(r.Context(), "failed to find user", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao buscar usuário")
+		return nil, false
+	}
+
+	return &user, true
+}
+
+// newProfileResponse monta a resposta de perfil
+func newProfileResponse(user *models.User) ProfileResponse {
+	return ProfileResponse{
+		User:         *user,
+		PendingEmail: user.PendingEmail,
+	}
+}
+
+// GetMe retorna o perfil do usuário autenticado
+func GetMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, newProfileResponse(user))
+}
+
+// UpdateMe atualiza nome e bio do usuário autenticado
+func UpdateMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	// Aplicar apenas os campos que foram enviados
+	if req.Name != nil {
+		user.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Bio != nil {
+		user.Bio = strings.TrimSpace(*req.Bio)
+	}
+
+	if err := database.DB.Save(user).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update profile", "user_id", user.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao atualizar perfil")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "profile updated", "user_id", user.ID)
+	response.JSON(w, http.StatusOK, newProfileResponse(user))
+}
+
+// ChangePassword troca a senha exigindo a senha atual
+// Revoga todas as sessões (refresh tokens) e emite novos tokens para o dispositivo atual
+func ChangePassword(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if !auth.CheckPassword(user.Password, req.CurrentPassword) {
+		response.Error(w, http.StatusUnauthorized, "Senha atual incorreta")
+		return
+	}
+
+	if req.NewPassword == req.CurrentPassword {
+		response.ValidationError(w, "A nova senha deve ser diferente da atual.")
+		return
+	}
+
+	if err := auth.ValidatePasswordPolicy(req.NewPassword, user.Name, user.Email); err != nil {
+		response.ValidationError(w, validation.FormatErrors([]string{err.Error()}))
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to hash password", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao processar senha")
+		return
+	}
+
+	if err := database.DB.Model(user).Update("password", hashedPassword).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update password", "user_id", user.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao atualizar senha")
+		return
+	}
+
+	// Encerrar todas as outras sessões
+	if err := auth.RevokeAllUserTokens(user.ID); err != nil {
+		log.ErrorCtx(r.Context(), "failed to revoke sessions after password change", "user_id", user.ID, "error", err)
+	}
+
+	// Invalidar o access token usado nesta requisição (um novo é emitido abaixo)
+	blacklistCurrentAccessToken(r)
+
+	authResponse, err := newAuthResponse(r, *user)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate tokens", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao gerar token")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "password changed", "user_id", user.ID)
+	response.JSON(w, http.StatusOK, authResponse)
+}
+
+// RequestEmailChange inicia a troca de e-mail enviando um token ao novo endereço
+// O e-mail só é alterado após a verificação (VerifyEmailChange)
+func RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if !auth.CheckPassword(user.Password, req.Password) {
+		response.Error(w, http.StatusUnauthorized, "Senha incorreta")
+		return
+	}
+
+	newEmail := strings.ToLower(strings.TrimSpace(req.NewEmail))
+	if strings.EqualFold(newEmail, user.Email) {
+		response.ValidationError(w, "O novo e-mail deve ser diferente do atual.")
+		return
+	}
+
+	if emailInUse(newEmail) {
+		response.ValidationError(w, "E-mail já cadastrado.")
+		return
+	}
+
+	token, err := auth.GenerateRandomToken()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate email change token", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao gerar token")
+		return
+	}
+
+	expiresAt := time.Now().Add(emailChangeTokenTTL)
+	if err := database.DB.Model(user).Updates(map[string]interface{}{
+		"pending_email":           newEmail,
+		"email_change_token_hash": auth.HashString(token),
+		"email_change_expires_at": expiresAt,
+	}).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to save pending email", "user_id", user.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao solicitar troca de e-mail")
+		return
+	}
+
+	if err := mailer.Send(r.Context(), mailer.Message{
+		To:      newEmail,
+		Subject: "Confirme seu novo e-mail",
+		Body: fmt.Sprintf("Olá, %s!\n\nUse o código abaixo para confirmar a troca de e-mail da sua conta:\n\n%s\n\nO código expira em 24 horas. Se você não solicitou esta alteração, ignore esta mensagem.",
+			user.Name, token),
+	}); err != nil {
+		log.ErrorCtx(r.Context(), "failed to send email change verification", "user_id", user.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao enviar e-mail de verificação")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "email change requested", "user_id", user.ID)
+	response.JSON(w, http.StatusAccepted, map[string]string{
+		"message":       "Enviamos um código de verificação para o novo e-mail",
+		"pending_email": newEmail,
+	})
+}
+
+// VerifyEmailChange confirma a troca de e-mail com o token recebido
+func VerifyEmailChange(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req VerifyEmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if user.PendingEmail == "" || user.EmailChangeTokenHash != auth.HashString(strings.TrimSpace(req.Token)) {
+		response.ErrorWithCode(w, http.StatusBadRequest, "Código de verificação inválido", "EMAIL_TOKEN_INVALID")
+		return
+	}
+
+	if user.EmailChangeExpiresAt == nil || time.Now().After(*user.EmailChangeExpiresAt) {
+		response.ErrorWithCode(w, http.StatusBadRequest, "Código de verificação expirado", "EMAIL_TOKEN_EXPIRED")
+		return
+	}
+
+	// O e-mail pode ter sido registrado por outra conta desde a solicitação
+	if emailInUse(user.PendingEmail) {
+		response.ValidationError(w, "E-mail já cadastrado.")
+		return
+	}
+
+	oldEmail := user.Email
+	if err := database.DB.Model(user).Updates(map[string]interface{}{
+		"email":                   user.PendingEmail,
+		"pending_email":           "",
+		"email_change_token_hash": "",
+		"email_change_expires_at": nil,
+	}).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to change email", "user_id", user.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao alterar e-mail")
+		return
+	}
+
+	// Avisar o endereço antigo (best effort)
+	if err := mailer.Send(r.Context(), mailer.Message{
+		To:      oldEmail,
+		Subject: "Seu e-mail foi alterado",
+		Body:    fmt.Sprintf("Olá, %s!\n\nO e-mail da sua conta foi alterado para %s. Se não foi você, entre em contato com o suporte.", user.Name, user.Email),
+	}); err != nil {
+		log.ErrorCtx(r.Context(), "failed to notify old email", "user_id", user.ID, "error", err)
+	}
+
+	log.InfoCtx(r.Context(), "email changed", "user_id", user.ID)
+	response.JSON(w, http.StatusOK, newProfileResponse(user))
+}
+
+// DeleteMe exclui a própria conta (soft delete) e anonimiza dados pessoais
+// As avaliações são mantidas sem atribuição: o usuário fica anônimo e os comentários são removidos
+func DeleteMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if !auth.CheckPassword(user.Password, req.Password) {
+		response.Error(w, http.StatusUnauthorized, "Senha incorreta")
+		return
+	}
+
+	avatarPublicID := user.AvatarPublicID
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := privacy.AnonymizeUser(tx, user); err != nil {
+			return err
+		}
+		return tx.Delete(user).Error
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete account", "user_id", user.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao excluir conta")
+		return
+	}
+
+	// Encerrar sessões e integrações
+	if err := auth.RevokeAllUserTokens(user.ID); err != nil {
+		log.ErrorCtx(r.Context(), "failed to revoke sessions after account deletion", "user_id", user.ID, "error", err)
+	}
+	if err := auth.RevokeAllUserAPIKeys(user.ID); err != nil {
+		log.ErrorCtx(r.Context(), "failed to revoke api keys after account deletion", "user_id", user.ID, "error", err)
+	}
+	blacklistCurrentAccessToken(r)
+
+	// Remover avatar (best effort)
+	if avatarPublicID != "" {
+		if imageService, err := storage.ServiceFactory(); err == nil {
+			imageService.DeleteImage(r.Context(), avatarPublicID)
+		}
+	}
+
+	log.InfoCtx(r.Context(), "account deleted", "user_id", user.ID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Conta excluída com sucesso"})
+}
+
+// emailInUse verifica se um e-mail já pertence a alguma conta
+func emailInUse(email string) bool {
+	var count int64
+	database.DB.Model(&models.User{}).Where("LOWER(email) = ?", strings.ToLower(email)).Count(&count)
+	return count > 0
+}
+
+// blacklistCurrentAccessToken invalida o access token JWT da requisição, se houver
+func blacklistCurrentAccessToken(r *http.Request) {
+	tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if tokenString == "" || auth.IsAPIKey(tokenString) {
+		return
+	}
+
+	if claims, err := auth.ValidateToken(tokenString); err == nil {
+		auth.AddToBlacklist(tokenString, claims.ExpiresAt.Time)
+	}
+}
+
+// GenerateAvatarUploadURL gera assinatura para upload direto do avatar ao Cloudinary
+func GenerateAvatarUploadURL(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	imageService, err := storage.ServiceFactory()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to initialize image service", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao configurar serviço de imagens")
+		return
+	}
+
+	publicID := fmt.Sprintf("%s%d", avatarPublicIDPrefix(user.ID), time.Now().Unix())
+
+	uploadSig, err := imageService.GenerateUploadSignature(publicID, avatarFolder)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate avatar upload signature", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao gerar URL de upload")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "avatar upload signature generated", "user_id", user.ID, "public_id", publicID)
+	response.JSON(w, http.StatusOK, uploadSig)
+}
+
+// ConfirmAvatarUpload confirma o upload direto e salva o avatar no perfil
+func ConfirmAvatarUpload(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req ConfirmImageUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Dados inválidos")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		response.ValidationError(w, "Dados de confirmação incompletos")
+		return
+	}
+
+	// O public_id precisa ter sido gerado para este usuário
+	if !strings.HasPrefix(path.Base(req.PublicID), avatarPublicIDPrefix(user.ID)) {
+		response.Error(w, http.StatusForbidden, "Imagem não pertence ao usuário")
+		return
+	}
+
+	imageService, serviceErr := storage.ServiceFactory()
+
+	// Se já tinha avatar antigo, tentar deletar (best effort)
+	if user.AvatarPublicID != "" && user.AvatarPublicID != req.PublicID && serviceErr == nil {
+		imageService.DeleteImage(r.Context(), user.AvatarPublicID)
+	}
+
+	user.AvatarURL = req.SecureURL
+	user.AvatarPublicID = req.PublicID
+
+	if err := database.DB.Save(user).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update avatar", "user_id", user.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao atualizar avatar")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "avatar confirmed", "user_id", user.ID, "public_id", req.PublicID)
+	response.JSON(w, http.StatusOK, newProfileResponse(user))
+}
+
+// DeleteAvatar remove o avatar do usuário autenticado
+func DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	user, ok := loadCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if user.AvatarPublicID == "" {
+		response.Error(w, http.StatusNotFound, "Você não possui avatar")
+		return
+	}
+
+	imageService, err := storage.ServiceFactory()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to initialize image service", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao configurar serviço de imagens")
+		return
+	}
+
+	if err := imageService.DeleteImage(r.Context(), user.AvatarPublicID); err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete avatar", "public_id", user.AvatarPublicID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao deletar imagem")
+		return
+	}
+
+	user.AvatarURL = ""
+	user.AvatarPublicID = ""
+
+	if err := database.DB.Save(user).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update user", "user_id", user.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao atualizar perfil")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "avatar deleted", "user_id", user.ID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Avatar removido com sucesso"})
+}
+
+// avatarPublicIDPrefix retorna o prefixo de public_id reservado para o usuário
+func avatarPublicIDPrefix(userID uint) string {
+	return fmt.Sprintf("avatar_%d_", userID)
+}
@@ -47,6 +47,12 @@ func CreateOrUpdateRating(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Respeitar status e visibilidade da receita
+	if !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Receita não encontrada")
+		return
+	}
+
 	// Decodificar request
 	var req CreateOrUpdateRatingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -203,6 +209,12 @@ func ListRecipeRatings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Respeitar status e visibilidade da receita
+	if !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Receita não encontrada")
+		return
+	}
+
 	// Extrair parâmetros de paginação
 	params := pagination.ExtractParams(r)
 
@@ -278,6 +290,12 @@ func GetRatingStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Respeitar status e visibilidade da receita
+	if !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Receita não encontrada")
+		return
+	}
+
 	// Calcular estatísticas
 	stats := RatingStatsResponse{
 		Distribution: make(map[string]int64),
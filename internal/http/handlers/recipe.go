@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -10,7 +11,10 @@ import (
 	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
 	"github.com/davidsonmarra/receitas-app/internal/models"
 	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/dietary"
 	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/markdown"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
 	"github.com/davidsonmarra/receitas-app/pkg/pagination"
 	"github.com/davidsonmarra/receitas-app/pkg/response"
 	"github.com/davidsonmarra/receitas-app/pkg/validation"
@@ -32,6 +36,10 @@ func CreateRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !sanitizeInstructions(w, &recipe.Instructions) {
+		return
+	}
+
 	// Obter userID do contexto (adicionado pelo middleware RequireAuth)
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
@@ -42,6 +50,26 @@ func CreateRecipe(w http.ResponseWriter, r *http.Request) {
 	// Atribuir criador à receita
 	recipe.UserID = &userID
 
+	// Toda receita nasce como rascunho; a publicação exige receita completa
+	recipe.Status = models.RecipeStatusDraft
+	recipe.PublishAt = nil
+	recipe.PublishedAt = nil
+
+	// Forks só são criados via POST /recipes/{id}/fork
+	recipe.ForkedFromID = nil
+
+	// Classificação alimentar, nutrição e custo por porção são derivados dos ingredientes, nunca enviados pelo cliente
+	dietary.Classification{}.ApplyTo(&recipe)
+	nutrition.Macros{}.ApplyTo(&recipe)
+	recipe.CostPerServing = nil
+
+	// Receitas não listadas nascem com link de compartilhamento
+	if err := applyRecipeVisibility(&recipe, recipe.Visibility); err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate share token", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create recipe")
+		return
+	}
+
 	if err := database.DB.Create(&recipe).Error; err != nil {
 		log.ErrorCtx(r.Context(), "failed to create recipe", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to create recipe")
@@ -53,6 +81,7 @@ func CreateRecipe(w http.ResponseWriter, r *http.Request) {
 }
 
 // ListRecipes lista todas as receitas com paginação
+// Filtros: ?tags=1,2&tag_match=all|any, ?diet=vegan, ?exclude_allergens=nuts e ?max_cost=10. A resposta inclui as facetas (contagem de tags) do resultado filtrado
 func ListRecipes(w http.ResponseWriter, r *http.Request) {
 	// Extrair parâmetros de paginação
 	params := pagination.ExtractParams(r)
@@ -63,9 +92,21 @@ func ListRecipes(w http.ResponseWriter, r *http.Request) {
 		sortBy = "newest"
 	}
 
+	// Extrair filtros (tags)
+	filters, err := parseRecipeFilters(r)
+	if err != nil {
+		response.ValidationError(w, err.Error())
+		return
+	}
+
+	// Receitas listáveis (publicadas e públicas) que atendem aos filtros
+	listed := func() *gorm.DB {
+		return filters.apply(database.DB.Model(&models.Recipe{}).Scopes(models.PubliclyListed))
+	}
+
 	// Count total de receitas
 	var total int64
-	if err := database.DB.Model(&models.Recipe{}).Count(&total).Error; err != nil {
+	if err := listed().Count(&total).Error; err != nil {
 		log.ErrorCtx(r.Context(), "failed to count recipes", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to count recipes")
 		return
@@ -75,7 +116,7 @@ func ListRecipes(w http.ResponseWriter, r *http.Request) {
 	var recipes []models.Recipe
 	offset := pagination.CalculateOffset(params)
 	
-	query := database.DB.Limit(params.Limit).Offset(offset)
+	query := listed().Preload("Tags").Limit(params.Limit).Offset(offset)
 
 	// Aplicar ordenação
 	if sortBy == "rating" {
@@ -84,9 +125,15 @@ func ListRecipes(w http.ResponseWriter, r *http.Request) {
 		query = query.
 			Joins("LEFT JOIN (SELECT recipe_id, AVG(score) as avg_score, COUNT(*) as rating_count FROM ratings WHERE deleted_at IS NULL GROUP BY recipe_id) r ON r.recipe_id = recipes.id").
 			Order("COALESCE(r.avg_score, 0) DESC, r.rating_count DESC, recipes.created_at DESC")
+	} else if sortBy == "cost" {
+		// Mais baratas primeiro (custo por porção); receitas sem custo estimado vão para o fim
+		query = query.Order("recipes.cost_per_serving IS NULL, recipes.cost_per_serving ASC, recipes.created_at DESC")
+	} else if sortBy == "trending" {
+		// Em alta: visualizações, preparos e avaliações recentes com decaimento no tempo (recalculado pelo job de tendências)
+		query = query.Order("recipes.trending_score DESC, recipes.created_at DESC")
 	} else {
 		// Ordenação padrão por data de criação
-		query = query.Order("created_at DESC")
+		query = query.Order("recipes.created_at DESC")
 	}
 
 	if err := query.Find(&recipes).Error; err != nil {
@@ -100,15 +147,46 @@ func ListRecipes(w http.ResponseWriter, r *http.Request) {
 		recipes[i].AverageRating, recipes[i].RatingCount = calculateRatingStats(database.DB, recipes[i].ID)
 	}
 
+	// Quantidade de registros de "fiz esta receita"
+	loadCookCounts(database.DB, recipes)
+
+	// Quanto uma porção cobre das metas diárias do usuário autenticado (se definidas)
+	if userID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		goal, err := loadNutritionGoal(userID)
+		if err != nil {
+			log.ErrorCtx(r.Context(), "failed to load nutrition goals", "user_id", userID, "error", err)
+		}
+		if goal != nil {
+			for i := range recipes {
+				recipes[i].GoalCoverage = nutrition.Coverage(&recipes[i], goal)
+			}
+		}
+	}
+
+	// Contagem de tags do resultado completo (não apenas da página)
+	facets, err := loadTagFacets(listed())
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to load recipe facets", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list recipes")
+		return
+	}
+
 	// Montar resposta paginada
 	paginatedResponse := pagination.BuildResponse(recipes, params, total)
-	response.JSON(w, http.StatusOK, paginatedResponse)
+	response.JSON(w, http.StatusOK, RecipeListResponse{Response: paginatedResponse, Facets: facets})
 }
 
 // GetRecipe busca uma receita por ID
+// Com ?render=html, inclui o modo de preparo renderizado em HTML sanitizado (instructions_html)
 func GetRecipe(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	render := r.URL.Query().Get("render")
+	if render != "" && render != "html" {
+		response.ValidationError(w, "Parâmetro render inválido. Use render=html.")
+		return
+	}
+
 	var recipe models.Recipe
 	if err := database.DB.
 		Preload("User").
@@ -116,14 +194,45 @@ func GetRecipe(w http.ResponseWriter, r *http.Request) {
 			return db.Order("\"order\" ASC, id ASC")
 		}).
 		Preload("Ingredients.Ingredient").
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("\"order\" ASC, id ASC")
+		}).
+		Preload("Steps.Ingredients").
+		Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("type ASC, name ASC")
+		}).
 		First(&recipe, id).Error; err != nil {
 		response.Error(w, http.StatusNotFound, "Recipe not found")
 		return
 	}
 
+	// Respeitar status e visibilidade (receitas não listadas exigem ?share_token=)
+	if !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
 	// Calcular estatísticas de avaliação
 	recipe.AverageRating, recipe.RatingCount = calculateRatingStats(database.DB, recipe.ID)
 
+	// Alérgenos presentes nos ingredientes
+	ingredients := make([]models.Ingredient, len(recipe.Ingredients))
+	for i, ri := range recipe.Ingredients {
+		ingredients[i] = ri.Ingredient
+	}
+	recipe.Allergens = dietary.Allergens(ingredients)
+
+	// Atribuição (se for fork) e quantidade de forks públicos
+	recipe.ForkChain = loadForkChain(r, &recipe)
+	recipe.ForkCount = countPublicForks(recipe.ID)
+
+	// Quantidade de registros de "fiz esta receita"
+	database.DB.Model(&models.CookLog{}).Where("recipe_id = ?", recipe.ID).Count(&recipe.CookCount)
+
+	if render == "html" {
+		recipe.InstructionsHTML = markdown.ToHTML(recipe.Instructions)
+	}
+
 	response.JSON(w, http.StatusOK, recipe)
 }
 
@@ -175,6 +284,10 @@ func UpdateRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !sanitizeInstructions(w, updateReq.Instructions) {
+		return
+	}
+
 	// Aplicar apenas os campos que foram enviados
 	if updateReq.Title != nil {
 		recipe.Title = *updateReq.Title
@@ -195,8 +308,8 @@ func UpdateRecipe(w http.ResponseWriter, r *http.Request) {
 		recipe.Difficulty = *updateReq.Difficulty
 	}
 
-	// Salvar no banco
-	if err := database.DB.Save(&recipe).Error; err != nil {
+	// Salvar no banco, registrando a revisão
+	if err := saveRecipeWithRevision(&recipe, userID); err != nil {
 		log.ErrorCtx(r.Context(), "failed to update recipe", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to update recipe")
 		return
@@ -240,6 +353,42 @@ func DeleteRecipe(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, map[string]string{"message": "Recipe deleted"})
 }
 
+// sanitizeInstructions valida o modo de preparo contra a lista de construções Markdown permitidas
+// e o substitui pela versão normalizada. Responde com erro de validação e retorna false se recusado.
+func sanitizeInstructions(w http.ResponseWriter, instructions *string) bool {
+	if instructions == nil || *instructions == "" {
+		return true
+	}
+
+	sanitized, err := markdown.Sanitize(*instructions)
+	if err != nil {
+		response.ValidationError(w, fmt.Sprintf("Modo de preparo inválido, %s.", err.Error()))
+		return false
+	}
+
+	*instructions = sanitized
+	return true
+}
+
+// canViewRecipe verifica se o usuário da requisição (opcionalmente autenticado) pode ver a receita
+// Dono e admins sempre veem. Para os demais, a receita precisa estar publicada e ser pública,
+// ou não listada com o token de compartilhamento válido em ?share_token=
+func canViewRecipe(r *http.Request, recipe *models.Recipe) bool {
+	if recipe.IsPublished() {
+		switch recipe.Visibility {
+		case models.RecipeVisibilityPublic:
+			return true
+		case models.RecipeVisibilityUnlisted:
+			if validShareToken(recipe, r.URL.Query().Get("share_token")) {
+				return true
+			}
+		}
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	return ok && canModifyRecipe(recipe, userID)
+}
+
 // canModifyRecipe verifica se o usuário pode modificar a receita
 func canModifyRecipe(recipe *models.Recipe, userID uint) bool {
 	// Verificar se usuário é admin (admin pode modificar qualquer receita)
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/analytics"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// Período (em dias) do relatório de engajamento do autor
+const (
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 90
+)
+
+// RecipeEventRequest representa um evento de engajamento enviado pelo cliente
+type RecipeEventRequest struct {
+	Type string `json:"type" validate:"required,oneof=view cook"`
+}
+
+// RecordRecipeEvent registra uma visualização ou um preparo ("fiz esta receita")
+// Eventos repetidos do mesmo usuário (ou IP, se anônimo) na mesma janela contam uma vez
+func RecordRecipeEvent(w http.ResponseWriter, r *http.Request) {
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, chi.URLParam(r, "id")).Error; err != nil || !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	var req RecipeEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	recorded, err := recordRecipeEvent(r, &recipe, req.Type)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to record recipe event", "recipe_id", recipe.ID, "type", req.Type, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to record event")
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, map[string]interface{}{
+		"recorded": recorded,
+	})
+}
+
+// recordRecipeEvent registra o evento para o usuário autenticado ou, se anônimo, para o IP
+// O IP vem da conexão (ou de proxy confiável), para que X-Forwarded-For forjado não burle a deduplicação
+// O engajamento do próprio autor não é contado
+func recordRecipeEvent(r *http.Request, recipe *models.Recipe, eventType string) (bool, error) {
+	actor := analytics.IPActor(getTrustedClientIP(r))
+	if userID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		if recipe.UserID != nil && *recipe.UserID == userID {
+			return false, nil
+		}
+		actor = analytics.UserActor(userID)
+	}
+	return analytics.Record(database.DB, recipe.ID, eventType, actor, time.Now())
+}
+
+// GetMyRecipeAnalytics retorna visualizações, preparos e avaliações das receitas do usuário por dia
+// ?days=N (1 a 90, padrão 30), incluindo hoje
+func GetMyRecipeAnalytics(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	days := defaultAnalyticsDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > maxAnalyticsDays {
+			response.ValidationError(w, "days deve ser um número entre 1 e 90.")
+			return
+		}
+		days = value
+	}
+
+	report, err := analytics.AuthorAnalytics(database.DB, userID, days, time.Now())
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to build recipe analytics", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to load analytics")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, report)
+}
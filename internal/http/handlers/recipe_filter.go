@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/dietary"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
+	"github.com/davidsonmarra/receitas-app/pkg/pagination"
+)
+
+// Modos de combinação do filtro de tags
+const (
+	tagMatchAll = "all" // AND: a receita precisa ter todas as tags
+	tagMatchAny = "any" // OR: basta uma das tags
+)
+
+// recipeFilters representa os filtros de GET /recipes
+type recipeFilters struct {
+	TagIDs           []uint
+	TagMatch         string
+	Diets            []string // Todas as dietas precisam ser atendidas
+	ExcludeAllergens []string // Nenhum ingrediente pode conter os alérgenos
+	Nutrition        []nutritionBound
+	MaxCost          *float64 // Orçamento: custo máximo por porção (R$)
+}
+
+// nutritionBound limita um macronutriente por porção (?max_calories=, ?min_protein=...)
+type nutritionBound struct {
+	Column string
+	Min    bool // true: valor mínimo; false: valor máximo
+	Value  float64
+}
+
+// TagFacet representa a contagem de uma tag no conjunto de receitas filtrado
+type TagFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Type  string `json:"-"`
+	Count int64  `json:"count"`
+}
+
+// RecipeListResponse é a resposta paginada de GET /recipes com as facetas do resultado
+type RecipeListResponse struct {
+	pagination.Response
+	Facets map[string][]TagFacet `json:"facets"`
+}
+
+// parseRecipeFilters extrai os filtros da query string
+// ?tags=1,2,3 filtra por IDs de tag; ?tag_match=all (padrão) ou any
+// ?diet=vegan,gluten_free filtra pela classificação; ?exclude_allergens=nuts,egg remove receitas com os alérgenos
+// ?max_calories=500&min_protein=20 filtra pelos valores por porção (calories, protein, carbs, fat e fiber)
+// ?max_cost=10 filtra pelo custo estimado por porção (R$)
+func parseRecipeFilters(r *http.Request) (recipeFilters, error) {
+	query := r.URL.Query()
+	filters := recipeFilters{TagMatch: tagMatchAll}
+
+	if raw := query.Get("tags"); raw != "" {
+		seen := make(map[uint]bool)
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+			if err != nil || id == 0 {
+				return filters, errors.New("Parâmetro tags inválido. Use IDs separados por vírgula.")
+			}
+			if !seen[uint(id)] {
+				seen[uint(id)] = true
+				filters.TagIDs = append(filters.TagIDs, uint(id))
+			}
+		}
+	}
+
+	if match := query.Get("tag_match"); match != "" {
+		if match != tagMatchAll && match != tagMatchAny {
+			return filters, errors.New("Parâmetro tag_match inválido. Use all ou any.")
+		}
+		filters.TagMatch = match
+	}
+
+	diets, allergens, err := parseDietParams(query)
+	if err != nil {
+		return filters, err
+	}
+	filters.Diets = diets
+	filters.ExcludeAllergens = allergens
+
+	for _, macro := range []string{nutrition.MacroCalories, nutrition.MacroProtein, nutrition.MacroCarbs, nutrition.MacroFat, nutrition.MacroFiber} {
+		for _, min := range []bool{true, false} {
+			param := "max_" + macro
+			if min {
+				param = "min_" + macro
+			}
+			raw := query.Get(param)
+			if raw == "" {
+				continue
+			}
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil || value < 0 {
+				return filters, fmt.Errorf("Parâmetro %s inválido. Use um número maior ou igual a zero.", param)
+			}
+			filters.Nutrition = append(filters.Nutrition, nutritionBound{Column: nutrition.MacroColumns[macro], Min: min, Value: value})
+		}
+	}
+
+	if raw := query.Get("max_cost"); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value <= 0 {
+			return filters, errors.New("Parâmetro max_cost inválido. Use um valor maior que zero.")
+		}
+		filters.MaxCost = &value
+	}
+
+	return filters, nil
+}
+
+// parseDietParams extrai ?diet=vegan,gluten_free e ?exclude_allergens=nuts,egg
+func parseDietParams(query url.Values) (diets, allergens []string, err error) {
+	for _, diet := range splitFilterList(query.Get("diet")) {
+		if _, ok := dietary.DietColumns[diet]; !ok {
+			return nil, nil, errors.New("Parâmetro diet inválido. Use vegan, vegetarian, gluten_free ou lactose_free.")
+		}
+		diets = append(diets, diet)
+	}
+
+	for _, allergen := range splitFilterList(query.Get("exclude_allergens")) {
+		if _, ok := models.AllergenColumns[allergen]; !ok {
+			return nil, nil, errors.New("Parâmetro exclude_allergens inválido. Use gluten, lactose, nuts, egg, shellfish ou soy.")
+		}
+		allergens = append(allergens, allergen)
+	}
+	return diets, allergens, nil
+}
+
+// splitFilterList separa uma lista da query string ("a,b") ignorando espaços e itens vazios
+func splitFilterList(raw string) []string {
+	var items []string
+	for _, part := range strings.Split(raw, ",") {
+		if item := strings.ToLower(strings.TrimSpace(part)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// apply restringe a consulta de receitas aos filtros informados
+func (f recipeFilters) apply(db *gorm.DB) *gorm.DB {
+	if len(f.TagIDs) > 0 {
+		if f.TagMatch == tagMatchAny {
+			db = db.Where("recipes.id IN (?)", database.DB.Table("recipe_tags").
+				Select("recipe_id").
+				Where("tag_id IN ?", f.TagIDs))
+		} else {
+			db = db.Where("recipes.id IN (?)", database.DB.Table("recipe_tags").
+				Select("recipe_id").
+				Where("tag_id IN ?", f.TagIDs).
+				Group("recipe_id").
+				Having("COUNT(DISTINCT tag_id) = ?", len(f.TagIDs)))
+		}
+	}
+
+	for _, diet := range f.Diets {
+		db = db.Where("recipes."+dietary.DietColumns[diet]+" = ?", true)
+	}
+
+	if len(f.ExcludeAllergens) > 0 {
+		conditions := make([]string, len(f.ExcludeAllergens))
+		for i, allergen := range f.ExcludeAllergens {
+			conditions[i] = "ingredients." + models.AllergenColumns[allergen] + " = ?"
+		}
+		args := make([]interface{}, len(conditions))
+		for i := range args {
+			args[i] = true
+		}
+
+		db = db.Where("recipes.id NOT IN (?)", database.DB.Table("recipe_ingredients").
+			Select("recipe_ingredients.recipe_id").
+			Joins("JOIN ingredients ON ingredients.id = recipe_ingredients.ingredient_id").
+			Where(strings.Join(conditions, " OR "), args...))
+	}
+
+	// Receitas sem ingredientes não têm informação nutricional e ficam fora dos filtros de nutrição
+	if len(f.Nutrition) > 0 {
+		db = db.Where("recipes.calories_per_serving > 0")
+	}
+	for _, bound := range f.Nutrition {
+		if bound.Min {
+			db = db.Where("recipes."+bound.Column+" >= ?", bound.Value)
+		} else {
+			db = db.Where("recipes."+bound.Column+" <= ?", bound.Value)
+		}
+	}
+
+	// Receitas sem custo estimado (algum ingrediente sem preço) ficam fora do filtro de orçamento
+	if f.MaxCost != nil {
+		db = db.Where("recipes.cost_per_serving IS NOT NULL AND recipes.cost_per_serving <= ?", *f.MaxCost)
+	}
+
+	return db
+}
+
+// loadTagFacets conta as tags aprovadas das receitas do conjunto filtrado, agrupadas por tipo
+// recipes deve ser uma consulta sobre o model Recipe, sem paginação
+func loadTagFacets(recipes *gorm.DB) (map[string][]TagFacet, error) {
+	var rows []TagFacet
+	if err := database.DB.Table("recipe_tags").
+		Select("tags.id, tags.name, tags.slug, tags.type, COUNT(*) AS count").
+		Joins("JOIN tags ON tags.id = recipe_tags.tag_id").
+		Where("tags.status = ?", models.TagStatusApproved).
+		Where("recipe_tags.recipe_id IN (?)", recipes.Select("recipes.id")).
+		Group("tags.id, tags.name, tags.slug, tags.type").
+		Order("count DESC, tags.name ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	facets := make(map[string][]TagFacet, len(models.TagTypes))
+	for _, tagType := range models.TagTypes {
+		facets[tagType] = []TagFacet{}
+	}
+	for _, row := range rows {
+		facets[row.Type] = append(facets[row.Type], row)
+	}
+
+	return facets, nil
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
+	"github.com/davidsonmarra/receitas-app/pkg/pagination"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+)
+
+// maxForkChainDepth limita quantos níveis da cadeia de atribuição são carregados
+const maxForkChainDepth = 20
+
+// ForkRecipe copia uma receita para o usuário autenticado ("remix")
+// Copia campos, tags, ingredientes e passos (sem imagens); a cópia nasce como rascunho público.
+// Terceiros só copiam receitas publicadas e públicas; o dono copia as próprias mantendo a visibilidade.
+func ForkRecipe(w http.ResponseWriter, r *http.Request) {
+	recipeID := chi.URLParam(r, "id")
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var original models.Recipe
+	if err := database.DB.First(&original, recipeID).Error; err != nil || !canViewRecipe(r, &original) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	// Um link de compartilhamento dá acesso à leitura, não o direito de republicar o conteúdo
+	isOwner := original.UserID != nil && *original.UserID == userID
+	if !isOwner && !original.IsPubliclyListed() {
+		response.Error(w, http.StatusForbidden, "Only published public recipes can be forked")
+		return
+	}
+
+	fork := models.Recipe{
+		Title:            original.Title,
+		Description:      original.Description,
+		Instructions:     original.Instructions,
+		PrepTime:         original.PrepTime,
+		Servings:         original.Servings,
+		Difficulty:       original.Difficulty,
+		UserID:           &userID,
+		Status:           models.RecipeStatusDraft,
+		ForkedFromID:     &original.ID,
+		ForkedFromTitle:  original.Title,
+		ForkedFromUserID: original.UserID,
+		IsVegan:          original.IsVegan,
+		IsVegetarian:     original.IsVegetarian,
+		IsGlutenFree:     original.IsGlutenFree,
+		IsLactoseFree:    original.IsLactoseFree,
+	}
+	nutrition.PerServing(&original).ApplyTo(&fork)
+	fork.CostPerServing = original.CostPerServing
+
+	visibility := models.RecipeVisibilityPublic
+	if isOwner {
+		visibility = original.Visibility
+	}
+	if err := applyRecipeVisibility(&fork, visibility); err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate share token", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to fork recipe")
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&fork).Error; err != nil {
+			return err
+		}
+
+		var tags []models.Tag
+		if err := tx.Model(&original).Association("Tags").Find(&tags); err != nil {
+			return err
+		}
+		if len(tags) > 0 {
+			if err := tx.Model(&fork).Association("Tags").Append(tags); err != nil {
+				return err
+			}
+		}
+
+		var ingredients []models.RecipeIngredient
+		if err := tx.Where("recipe_id = ?", original.ID).Order("\"order\" ASC, id ASC").Find(&ingredients).Error; err != nil {
+			return err
+		}
+
+		// Mapeia ingrediente original -> cópia, para refazer as referências dos passos
+		copies := make(map[uint]models.RecipeIngredient, len(ingredients))
+		for _, ingredient := range ingredients {
+			copied := models.RecipeIngredient{
+				RecipeID:     fork.ID,
+				IngredientID: ingredient.IngredientID,
+				Quantity:     ingredient.Quantity,
+				Unit:         ingredient.Unit,
+				Notes:        ingredient.Notes,
+				Order:        ingredient.Order,
+			}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+			copies[ingredient.ID] = copied
+		}
+
+		var steps []models.RecipeStep
+		if err := tx.Preload("Ingredients").Where("recipe_id = ?", original.ID).Order("\"order\" ASC, id ASC").Find(&steps).Error; err != nil {
+			return err
+		}
+
+		for _, step := range steps {
+			copied := models.RecipeStep{
+				RecipeID:        fork.ID,
+				Order:           step.Order,
+				Text:            step.Text,
+				DurationSeconds: step.DurationSeconds,
+			}
+			if err := tx.Omit("Ingredients").Create(&copied).Error; err != nil {
+				return err
+			}
+
+			var stepIngredients []models.RecipeIngredient
+			for _, ingredient := range step.Ingredients {
+				if c, ok := copies[ingredient.ID]; ok {
+					stepIngredients = append(stepIngredients, c)
+				}
+			}
+			if len(stepIngredients) > 0 {
+				if err := tx.Model(&copied).Association("Ingredients").Append(stepIngredients); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to fork recipe", "recipe_id", original.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to fork recipe")
+		return
+	}
+
+	database.DB.
+		Preload("Ingredients", func(db *gorm.DB) *gorm.DB {
+			return db.Order("\"order\" ASC, id ASC")
+		}).
+		Preload("Ingredients.Ingredient").
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("\"order\" ASC, id ASC")
+		}).
+		Preload("Steps.Ingredients").
+		Preload("Tags").
+		First(&fork, fork.ID)
+	fork.ForkChain = loadForkChain(r, &fork)
+
+	log.InfoCtx(r.Context(), "recipe forked", "recipe_id", original.ID, "fork_id", fork.ID, "user_id", userID)
+	response.JSON(w, http.StatusCreated, fork)
+}
+
+// ListRecipeForks lista os forks públicos de uma receita
+func ListRecipeForks(w http.ResponseWriter, r *http.Request) {
+	recipeID := chi.URLParam(r, "id")
+
+	// Buscar receita (respeitando status e visibilidade)
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, recipeID).Error; err != nil || !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	params := pagination.ExtractParams(r)
+
+	query := database.DB.Model(&models.Recipe{}).Scopes(models.PubliclyListed).Where("forked_from_id = ?", recipe.ID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to count recipe forks", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list forks")
+		return
+	}
+
+	var forks []models.Recipe
+	if err := query.Preload("User").
+		Order("created_at DESC").
+		Limit(params.Limit).
+		Offset(pagination.CalculateOffset(params)).
+		Find(&forks).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list recipe forks", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list forks")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, pagination.BuildResponse(forks, params, total))
+}
+
+// countPublicForks conta os forks públicos de uma receita
+func countPublicForks(recipeID uint) int64 {
+	var count int64
+	database.DB.Model(&models.Recipe{}).Scopes(models.PubliclyListed).Where("forked_from_id = ?", recipeID).Count(&count)
+	return count
+}
+
+// loadForkChain monta a cadeia de atribuição de um fork, da original mais próxima à mais antiga
+// Título e autor vêm do registro da cópia, então a atribuição sobrevive à exclusão da original.
+// Originais que quem consulta não pode ver (privadas, não listadas, rascunhos) aparecem sem título e autor.
+func loadForkChain(r *http.Request, recipe *models.Recipe) []models.RecipeAttribution {
+	var chain []models.RecipeAttribution
+
+	current := recipe
+	for depth := 0; current.ForkedFromID != nil && depth < maxForkChainDepth; depth++ {
+		attribution := models.RecipeAttribution{RecipeID: *current.ForkedFromID}
+
+		// Originais excluídas (soft delete) continuam na cadeia, mas indisponíveis
+		var parent models.Recipe
+		found := database.DB.Unscoped().First(&parent, *current.ForkedFromID).Error == nil
+		visible := !found || canViewRecipe(r, &parent)
+		if visible {
+			attribution.Title = current.ForkedFromTitle
+			attribution.AuthorID = current.ForkedFromUserID
+			if current.ForkedFromUserID != nil {
+				var author models.User
+				if err := database.DB.Unscoped().Select("id", "name").First(&author, *current.ForkedFromUserID).Error; err == nil {
+					attribution.AuthorName = author.Name
+				}
+			}
+		}
+
+		if !found {
+			chain = append(chain, attribution)
+			break
+		}
+		attribution.Available = !parent.DeletedAt.Valid && visible
+
+		chain = append(chain, attribution)
+		current = &parent
+	}
+
+	return chain
+}
@@ -204,9 +204,9 @@ func DeleteRecipeImage(w http.ResponseWriter, r *http.Request) {
 func GetRecipeImageVariants(w http.ResponseWriter, r *http.Request) {
 	recipeID := chi.URLParam(r, "id")
 
-	// Buscar receita
+	// Buscar receita (respeitando status e visibilidade)
 	var recipe models.Recipe
-	if err := database.DB.First(&recipe, recipeID).Error; err != nil {
+	if err := database.DB.First(&recipe, recipeID).Error; err != nil || !canViewRecipe(r, &recipe) {
 		response.Error(w, http.StatusNotFound, "Receita não encontrada")
 		return
 	}
@@ -289,9 +289,9 @@ func GetOptimizedRecipeImage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Buscar receita
+	// Buscar receita (respeitando status e visibilidade)
 	var recipe models.Recipe
-	if err := database.DB.First(&recipe, recipeID).Error; err != nil {
+	if err := database.DB.First(&recipe, recipeID).Error; err != nil || !canViewRecipe(r, &recipe) {
 		response.Error(w, http.StatusNotFound, "Receita não encontrada")
 		return
 	}
@@ -3,13 +3,18 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
 
 	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
 	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/cost"
 	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/dietary"
 	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
 	"github.com/davidsonmarra/receitas-app/pkg/response"
 	"github.com/davidsonmarra/receitas-app/pkg/validation"
 )
@@ -77,7 +82,26 @@ func AddRecipeIngredient(w http.ResponseWriter, r *http.Request) {
 		Order:        req.Order,
 	}
 
-	if err := database.DB.Create(&recipeIng).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, recipe.ID); err != nil {
+			return err
+		}
+		if err := tx.Create(&recipeIng).Error; err != nil {
+			return err
+		}
+
+		// Reclassificar a receita (vegana, sem glúten...) e recalcular nutrição e custo por porção
+		if _, err := dietary.Recompute(tx, recipe.ID); err != nil {
+			return err
+		}
+		if err := recomputeRecipeTotals(tx, &recipe); err != nil {
+			return err
+		}
+
+		_, err := recordRecipeRevision(tx, recipe.ID, &userID, models.RecipeRevisionActionUpdate, nil)
+		return err
+	})
+	if err != nil {
 		log.ErrorCtx(r.Context(), "failed to add ingredient to recipe", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to add ingredient")
 		return
@@ -90,13 +114,37 @@ func AddRecipeIngredient(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusCreated, recipeIng)
 }
 
+// recomputeRecipeTotals recalcula e copia para a receita os valores por porção gravados (nutrição e custo)
+// Deve ser chamado na mesma transação sempre que ingredientes, quantidades ou porções mudarem
+func recomputeRecipeTotals(tx *gorm.DB, recipe *models.Recipe) error {
+	if err := touchRecipe(tx, recipe); err != nil {
+		return err
+	}
+
+	perServing, err := nutrition.Recompute(tx, recipe.ID)
+	if err != nil {
+		return err
+	}
+	perServing.ApplyTo(recipe)
+
+	recipe.CostPerServing, err = cost.Recompute(tx, recipe.ID)
+	return err
+}
+
+// touchRecipe marca a receita como atualizada quando partes dela (ingredientes, tags) mudam
+// O job de receitas semelhantes usa updated_at para detectar alterações
+func touchRecipe(db *gorm.DB, recipe *models.Recipe) error {
+	recipe.UpdatedAt = time.Now()
+	return db.Model(&models.Recipe{}).Where("id = ?", recipe.ID).UpdateColumn("updated_at", recipe.UpdatedAt).Error
+}
+
 // ListRecipeIngredients lista ingredientes de uma receita
 func ListRecipeIngredients(w http.ResponseWriter, r *http.Request) {
 	recipeID := chi.URLParam(r, "id")
 
-	// Verificar se receita existe
+	// Buscar receita (respeitando status e visibilidade)
 	var recipe models.Recipe
-	if err := database.DB.First(&recipe, recipeID).Error; err != nil {
+	if err := database.DB.First(&recipe, recipeID).Error; err != nil || !canViewRecipe(r, &recipe) {
 		response.Error(w, http.StatusNotFound, "Recipe not found")
 		return
 	}
@@ -166,7 +214,23 @@ func UpdateRecipeIngredient(w http.ResponseWriter, r *http.Request) {
 		recipeIng.Order = *req.Order
 	}
 
-	if err := database.DB.Save(&recipeIng).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, recipe.ID); err != nil {
+			return err
+		}
+		if err := tx.Save(&recipeIng).Error; err != nil {
+			return err
+		}
+
+		// Quantidades alteradas mudam a nutrição e o custo por porção
+		if err := recomputeRecipeTotals(tx, &recipe); err != nil {
+			return err
+		}
+
+		_, err := recordRecipeRevision(tx, recipe.ID, &userID, models.RecipeRevisionActionUpdate, nil)
+		return err
+	})
+	if err != nil {
 		log.ErrorCtx(r.Context(), "failed to update recipe ingredient", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to update ingredient")
 		return
@@ -194,16 +258,47 @@ func DeleteRecipeIngredient(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := database.DB.Where("recipe_id = ? AND id = ?", recipeID, ingredientID).
-		Delete(&models.RecipeIngredient{})
+	var rowsAffected int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// A versão original precisa ser gravada antes da remoção, com o ingrediente ainda presente
+		if err := ensureBaselineRevision(tx, recipe.ID); err != nil {
+			return err
+		}
+
+		result := tx.Where("recipe_id = ? AND id = ?", recipeID, ingredientID).
+			Delete(&models.RecipeIngredient{})
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		if rowsAffected == 0 {
+			return nil
+		}
+
+		// Remover referências nos passos do modo de preparo
+		if err := tx.Exec("DELETE FROM recipe_step_ingredients WHERE recipe_ingredient_id = ?", ingredientID).Error; err != nil {
+			return err
+		}
+
+		// Reclassificar a receita sem o ingrediente removido
+		if _, err := dietary.Recompute(tx, recipe.ID); err != nil {
+			return err
+		}
+		if err := recomputeRecipeTotals(tx, &recipe); err != nil {
+			return err
+		}
+
+		_, err := recordRecipeRevision(tx, recipe.ID, &userID, models.RecipeRevisionActionUpdate, nil)
+		return err
+	})
 
-	if result.Error != nil {
-		log.ErrorCtx(r.Context(), "failed to delete recipe ingredient", "error", result.Error)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete recipe ingredient", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to delete ingredient")
 		return
 	}
 
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		response.Error(w, http.StatusNotFound, "Recipe ingredient not found")
 		return
 	}
@@ -213,58 +308,61 @@ func DeleteRecipeIngredient(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetRecipeNutrition calcula informação nutricional da receita
+// Além dos macronutrientes, retorna o perfil estendido (sódio, colesterol, minerais, vitaminas)
 func GetRecipeNutrition(w http.ResponseWriter, r *http.Request) {
+	recipe, recipeIngredients, ok := loadRecipeNutrition(w, r)
+	if !ok {
+		return
+	}
+
+	// Valores nutricionais são por 100g; a proporção é baseada na quantidade
+	total := nutrition.SumMacros(recipeIngredients)
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"total":       total,
+		"per_serving": total.Scale(1 / float64(recipe.Servings)),
+		"servings":    recipe.Servings,
+		// Perfil estendido (TACO): traço e não medido não são somados como zero
+		"nutrients": nutrition.Profile(recipeIngredients, recipe.Servings),
+		// Tabela nutricional no modelo da ANVISA (RDC 429/2020)
+		"label": nutrition.BuildLabel(recipeIngredients, recipe.Servings),
+	})
+}
+
+// GetRecipeNutritionLabel renderiza a tabela nutricional da ANVISA como imagem SVG
+func GetRecipeNutritionLabel(w http.ResponseWriter, r *http.Request) {
+	recipe, recipeIngredients, ok := loadRecipeNutrition(w, r)
+	if !ok {
+		return
+	}
+
+	label := nutrition.BuildLabel(recipeIngredients, recipe.Servings)
+
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Write(label.SVG())
+}
+
+// loadRecipeNutrition busca a receita visível e seus ingredientes com o perfil de nutrientes
+func loadRecipeNutrition(w http.ResponseWriter, r *http.Request) (*models.Recipe, []models.RecipeIngredient, bool) {
 	recipeID := chi.URLParam(r, "id")
 
-	// Verificar se receita existe
+	// Buscar receita (respeitando status e visibilidade)
 	var recipe models.Recipe
-	if err := database.DB.First(&recipe, recipeID).Error; err != nil {
+	if err := database.DB.First(&recipe, recipeID).Error; err != nil || !canViewRecipe(r, &recipe) {
 		response.Error(w, http.StatusNotFound, "Recipe not found")
-		return
+		return nil, nil, false
 	}
 
 	var recipeIngredients []models.RecipeIngredient
-	if err := database.DB.Preload("Ingredient").
+	if err := database.DB.Preload("Ingredient.Nutrients").
 		Where("recipe_id = ?", recipeID).
 		Find(&recipeIngredients).Error; err != nil {
 		log.ErrorCtx(r.Context(), "failed to calculate nutrition", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to calculate nutrition")
-		return
+		return nil, nil, false
 	}
 
-	totalCalories := 0.0
-	totalProtein := 0.0
-	totalCarbs := 0.0
-	totalFat := 0.0
-	totalFiber := 0.0
-
-	for _, ri := range recipeIngredients {
-		// Valores nutricionais são por 100g
-		// Calcular proporção baseada na quantidade
-		factor := ri.Quantity / 100.0
-
-		totalCalories += ri.Ingredient.Calories * factor
-		totalProtein += ri.Ingredient.Protein * factor
-		totalCarbs += ri.Ingredient.Carbs * factor
-		totalFat += ri.Ingredient.Fat * factor
-		totalFiber += ri.Ingredient.Fiber * factor
-	}
-
-	response.JSON(w, http.StatusOK, map[string]interface{}{
-		"total": map[string]float64{
-			"calories": totalCalories,
-			"protein":  totalProtein,
-			"carbs":    totalCarbs,
-			"fat":      totalFat,
-			"fiber":    totalFiber,
-		},
-		"per_serving": map[string]float64{
-			"calories": totalCalories / float64(recipe.Servings),
-			"protein":  totalProtein / float64(recipe.Servings),
-			"carbs":    totalCarbs / float64(recipe.Servings),
-			"fat":      totalFat / float64(recipe.Servings),
-			"fiber":    totalFiber / float64(recipe.Servings),
-		},
-		"servings": recipe.Servings,
-	})
+	return &recipe, recipeIngredients, true
 }
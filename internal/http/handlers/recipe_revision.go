@@ -0,0 +1,558 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/dietary"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/pagination"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+)
+
+// RecipeFieldChange representa a alteração de um campo entre duas revisões
+type RecipeFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// RecipeIngredientChange representa a alteração de um ingrediente entre duas revisões
+type RecipeIngredientChange struct {
+	IngredientID uint                             `json:"ingredient_id"`
+	Name         string                           `json:"name"`
+	Change       string                           `json:"change"` // added, removed ou changed
+	From         *models.RecipeIngredientSnapshot `json:"from,omitempty"`
+	To           *models.RecipeIngredientSnapshot `json:"to,omitempty"`
+}
+
+// ListRecipeRevisions lista o histórico de revisões da receita (mais recentes primeiro)
+func ListRecipeRevisions(w http.ResponseWriter, r *http.Request) {
+	recipe, _, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	params := pagination.ExtractParams(r)
+	offset := pagination.CalculateOffset(params)
+
+	var total int64
+	if err := database.DB.Model(&models.RecipeRevision{}).
+		Where("recipe_id = ?", recipe.ID).
+		Count(&total).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to count recipe revisions", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao buscar revisões")
+		return
+	}
+
+	var revisions []models.RecipeRevision
+	if err := database.DB.
+		Where("recipe_id = ?", recipe.ID).
+		Order("number DESC").
+		Limit(params.Limit).
+		Offset(offset).
+		Find(&revisions).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list recipe revisions", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao buscar revisões")
+		return
+	}
+
+	fillRevisionAuthors(revisions)
+
+	response.JSON(w, http.StatusOK, pagination.BuildResponse(revisions, params, total))
+}
+
+// GetRecipeRevision retorna uma revisão com o conteúdo completo da receita naquela versão
+func GetRecipeRevision(w http.ResponseWriter, r *http.Request) {
+	recipe, _, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	revision, snapshot, ok := loadRecipeRevision(w, r, recipe.ID, chi.URLParam(r, "number"))
+	if !ok {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"revision": revision,
+		"snapshot": snapshot,
+	})
+}
+
+// DiffRecipeRevisions compara duas revisões campo a campo
+// ?to= padrão é a revisão mais recente; ?from= padrão é a revisão anterior a "to"
+func DiffRecipeRevisions(w http.ResponseWriter, r *http.Request) {
+	recipe, _, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	toParam := r.URL.Query().Get("to")
+	if toParam == "" {
+		var latest int
+		if err := database.DB.Model(&models.RecipeRevision{}).
+			Where("recipe_id = ?", recipe.ID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&latest).Error; err != nil {
+			log.ErrorCtx(r.Context(), "failed to find latest revision", "recipe_id", recipe.ID, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Erro ao buscar revisões")
+			return
+		}
+		// Receita nunca editada ainda não tem histórico
+		if latest == 0 {
+			response.Error(w, http.StatusNotFound, "Receita ainda não tem revisões")
+			return
+		}
+		toParam = strconv.Itoa(latest)
+	}
+
+	to, toSnapshot, ok := loadRecipeRevision(w, r, recipe.ID, toParam)
+	if !ok {
+		return
+	}
+
+	fromParam := r.URL.Query().Get("from")
+	if fromParam == "" {
+		// A primeira revisão não tem anterior: o diff é com ela mesma (vazio)
+		fromParam = strconv.Itoa(max(to.Number-1, 1))
+	}
+
+	from, fromSnapshot, ok := loadRecipeRevision(w, r, recipe.ID, fromParam)
+	if !ok {
+		return
+	}
+
+	fields, ingredients := diffRecipeSnapshots(fromSnapshot, toSnapshot)
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"recipe_id":   recipe.ID,
+		"from":        from.Number,
+		"to":          to.Number,
+		"fields":      fields,
+		"ingredients": ingredients,
+	})
+}
+
+// RestoreRecipeRevision restaura o conteúdo de uma revisão antiga, gravando-o como nova revisão
+// O histórico não é reescrito: a versão restaurada passa a ser a mais recente
+func RestoreRecipeRevision(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	revision, snapshot, ok := loadRecipeRevision(w, r, recipe.ID, chi.URLParam(r, "number"))
+	if !ok {
+		return
+	}
+
+	// Revisões anteriores à validação de Markdown podem conter construções hoje recusadas
+	instructions := snapshot.Instructions
+	if !sanitizeInstructions(w, &instructions) {
+		return
+	}
+
+	recipe.Title = snapshot.Title
+	recipe.Description = snapshot.Description
+	recipe.Instructions = instructions
+	recipe.PrepTime = snapshot.PrepTime
+	recipe.Servings = snapshot.Servings
+	recipe.Difficulty = snapshot.Difficulty
+
+	var restored *models.RecipeRevision
+	var skipped []models.RecipeIngredientSnapshot
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(recipe).Error; err != nil {
+			return err
+		}
+
+		var err error
+		skipped, err = restoreRecipeIngredients(tx, recipe.ID, snapshot.Ingredients)
+		if err != nil {
+			return err
+		}
+
+		classification, err := dietary.Recompute(tx, recipe.ID)
+		if err != nil {
+			return err
+		}
+		classification.ApplyTo(recipe)
+
+		if err := recomputeRecipeTotals(tx, recipe); err != nil {
+			return err
+		}
+
+		restored, err = recordRecipeRevision(tx, recipe.ID, &userID, models.RecipeRevisionActionRestore, &revision.Number)
+		return err
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to restore recipe revision", "recipe_id", recipe.ID, "revision", revision.Number, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao restaurar revisão")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "recipe revision restored",
+		"recipe_id", recipe.ID,
+		"restored_from", revision.Number,
+		"revision", restored.Number,
+		"user_id", userID)
+
+	resp := map[string]interface{}{
+		"message":  "Revisão restaurada com sucesso",
+		"revision": restored,
+		"recipe":   recipe,
+	}
+	if len(skipped) > 0 {
+		// Ingredientes removidos do catálogo desde a revisão não podem ser restaurados
+		resp["skipped_ingredients"] = skipped
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// saveRecipeWithRevision salva a receita editada e grava a revisão correspondente
+// Na primeira edição, o estado anterior é gravado antes como revisão "original"
+func saveRecipeWithRevision(recipe *models.Recipe, authorID uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, recipe.ID); err != nil {
+			return err
+		}
+		if err := tx.Save(recipe).Error; err != nil {
+			return err
+		}
+
+		// Porções alteradas mudam a nutrição e o custo por porção
+		if err := recomputeRecipeTotals(tx, recipe); err != nil {
+			return err
+		}
+
+		_, err := recordRecipeRevision(tx, recipe.ID, &authorID, models.RecipeRevisionActionUpdate, nil)
+		return err
+	})
+}
+
+// ensureBaselineRevision grava o estado atual como revisão "original" se a receita ainda não tem histórico
+// Receitas criadas antes do histórico (ou nunca editadas) ganham a versão inicial de forma preguiçosa
+func ensureBaselineRevision(tx *gorm.DB, recipeID uint) error {
+	if err := lockRecipeRevisions(tx, recipeID); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&models.RecipeRevision{}).Where("recipe_id = ?", recipeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	recipe, snapshot, err := snapshotRecipe(tx, recipeID)
+	if err != nil {
+		return err
+	}
+
+	_, err = createRecipeRevision(tx, recipeID, snapshot, recipe.UserID, models.RecipeRevisionActionOriginal, nil, recipe.UpdatedAt)
+	return err
+}
+
+// recordRecipeRevision grava o estado atual da receita no banco como nova revisão
+// Edições que não alteram o conteúdo não geram revisão (retorna nil)
+func recordRecipeRevision(tx *gorm.DB, recipeID uint, authorID *uint, action string, restoredFrom *int) (*models.RecipeRevision, error) {
+	if err := lockRecipeRevisions(tx, recipeID); err != nil {
+		return nil, err
+	}
+
+	_, snapshot, err := snapshotRecipe(tx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if action == models.RecipeRevisionActionUpdate {
+		var latest models.RecipeRevision
+		err := tx.Where("recipe_id = ?", recipeID).Order("number DESC").First(&latest).Error
+		if err == nil {
+			encoded, _ := json.Marshal(snapshot)
+			if string(encoded) == latest.Snapshot {
+				return nil, nil
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	return createRecipeRevision(tx, recipeID, snapshot, authorID, action, restoredFrom, time.Now())
+}
+
+// lockRecipeRevisions bloqueia a linha da receita até o fim da transação (SELECT ... FOR UPDATE)
+// Serializa edições simultâneas da mesma receita: sem o bloqueio, duas transações calculam o mesmo
+// número de revisão e a segunda falha no índice único idx_recipe_revisions_recipe_number
+// No SQLite (testes) a cláusula é ignorada; as escritas já são serializadas pelo banco
+func lockRecipeRevisions(tx *gorm.DB, recipeID uint) error {
+	var id uint
+	return tx.Model(&models.Recipe{}).Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", recipeID).
+		Select("id").
+		Scan(&id).Error
+}
+
+// createRecipeRevision grava uma revisão com o próximo número da receita
+// Deve ser chamado com a receita bloqueada por lockRecipeRevisions
+func createRecipeRevision(tx *gorm.DB, recipeID uint, snapshot models.RecipeSnapshot, authorID *uint, action string, restoredFrom *int, createdAt time.Time) (*models.RecipeRevision, error) {
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var maxNumber int
+	if err := tx.Model(&models.RecipeRevision{}).
+		Where("recipe_id = ?", recipeID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&maxNumber).Error; err != nil {
+		return nil, err
+	}
+
+	revision := models.RecipeRevision{
+		RecipeID:     recipeID,
+		Number:       maxNumber + 1,
+		AuthorID:     authorID,
+		Action:       action,
+		RestoredFrom: restoredFrom,
+		Snapshot:     string(encoded),
+		CreatedAt:    createdAt,
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// snapshotRecipe lê a receita e seus ingredientes do banco e monta o snapshot versionado
+func snapshotRecipe(tx *gorm.DB, recipeID uint) (*models.Recipe, models.RecipeSnapshot, error) {
+	var recipe models.Recipe
+	if err := tx.
+		Preload("Ingredients", func(db *gorm.DB) *gorm.DB {
+			return db.Order("\"order\" ASC, id ASC")
+		}).
+		Preload("Ingredients.Ingredient").
+		First(&recipe, recipeID).Error; err != nil {
+		return nil, models.RecipeSnapshot{}, err
+	}
+
+	snapshot := models.RecipeSnapshot{
+		Title:        recipe.Title,
+		Description:  recipe.Description,
+		Instructions: recipe.Instructions,
+		PrepTime:     recipe.PrepTime,
+		Servings:     recipe.Servings,
+		Difficulty:   recipe.Difficulty,
+		Ingredients:  make([]models.RecipeIngredientSnapshot, 0, len(recipe.Ingredients)),
+	}
+	for _, ri := range recipe.Ingredients {
+		snapshot.Ingredients = append(snapshot.Ingredients, models.RecipeIngredientSnapshot{
+			IngredientID: ri.IngredientID,
+			Name:         ri.Ingredient.Name,
+			Quantity:     ri.Quantity,
+			Unit:         ri.Unit,
+			Notes:        ri.Notes,
+			Order:        ri.Order,
+		})
+	}
+
+	return &recipe, snapshot, nil
+}
+
+// restoreRecipeIngredients ajusta os ingredientes da receita para a lista da revisão
+// Linhas existentes do mesmo ingrediente são reaproveitadas, preservando as referências nos passos.
+// Retorna os ingredientes que não existem mais no catálogo e foram ignorados.
+func restoreRecipeIngredients(tx *gorm.DB, recipeID uint, target []models.RecipeIngredientSnapshot) ([]models.RecipeIngredientSnapshot, error) {
+	var current []models.RecipeIngredient
+	if err := tx.Where("recipe_id = ?", recipeID).Order("\"order\" ASC, id ASC").Find(&current).Error; err != nil {
+		return nil, err
+	}
+
+	used := make(map[uint]bool, len(current))
+	var skipped []models.RecipeIngredientSnapshot
+
+	for _, item := range target {
+		var match *models.RecipeIngredient
+		for i := range current {
+			if !used[current[i].ID] && current[i].IngredientID == item.IngredientID {
+				match = &current[i]
+				break
+			}
+		}
+
+		if match != nil {
+			used[match.ID] = true
+			if err := tx.Model(match).Updates(map[string]interface{}{
+				"quantity": item.Quantity,
+				"unit":     item.Unit,
+				"notes":    item.Notes,
+				"order":    item.Order,
+			}).Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var exists int64
+		if err := tx.Model(&models.Ingredient{}).Where("id = ?", item.IngredientID).Count(&exists).Error; err != nil {
+			return nil, err
+		}
+		if exists == 0 {
+			skipped = append(skipped, item)
+			continue
+		}
+
+		if err := tx.Omit("Recipe", "Ingredient").Create(&models.RecipeIngredient{
+			RecipeID:     recipeID,
+			IngredientID: item.IngredientID,
+			Quantity:     item.Quantity,
+			Unit:         item.Unit,
+			Notes:        item.Notes,
+			Order:        item.Order,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var removed []uint
+	for _, ri := range current {
+		if !used[ri.ID] {
+			removed = append(removed, ri.ID)
+		}
+	}
+	if len(removed) > 0 {
+		if err := tx.Exec("DELETE FROM recipe_step_ingredients WHERE recipe_ingredient_id IN ?", removed).Error; err != nil {
+			return nil, err
+		}
+		if err := tx.Where("id IN ?", removed).Delete(&models.RecipeIngredient{}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return skipped, nil
+}
+
+// diffRecipeSnapshots compara duas versões da receita
+// Ingredientes são pareados por ingredient_id, na ordem em que aparecem
+func diffRecipeSnapshots(from, to models.RecipeSnapshot) ([]RecipeFieldChange, []RecipeIngredientChange) {
+	fields := []RecipeFieldChange{}
+	compare := func(field string, a, b interface{}) {
+		if a != b {
+			fields = append(fields, RecipeFieldChange{Field: field, From: a, To: b})
+		}
+	}
+	compare("title", from.Title, to.Title)
+	compare("description", from.Description, to.Description)
+	compare("instructions", from.Instructions, to.Instructions)
+	compare("prep_time", from.PrepTime, to.PrepTime)
+	compare("servings", from.Servings, to.Servings)
+	compare("difficulty", from.Difficulty, to.Difficulty)
+
+	ingredients := []RecipeIngredientChange{}
+	available := make(map[uint][]models.RecipeIngredientSnapshot)
+	for _, item := range from.Ingredients {
+		available[item.IngredientID] = append(available[item.IngredientID], item)
+	}
+
+	matched := make(map[uint]int)
+	for i := range to.Ingredients {
+		item := to.Ingredients[i]
+		candidates := available[item.IngredientID]
+		if len(candidates) == 0 {
+			ingredients = append(ingredients, RecipeIngredientChange{
+				IngredientID: item.IngredientID, Name: item.Name, Change: "added", To: &to.Ingredients[i],
+			})
+			continue
+		}
+
+		previous := candidates[0]
+		available[item.IngredientID] = candidates[1:]
+		matched[item.IngredientID]++
+
+		if previous != item {
+			ingredients = append(ingredients, RecipeIngredientChange{
+				IngredientID: item.IngredientID, Name: item.Name, Change: "changed", From: &previous, To: &to.Ingredients[i],
+			})
+		}
+	}
+
+	// Os primeiros N de cada ingrediente foram pareados; os demais foram removidos
+	seen := make(map[uint]int)
+	for i := range from.Ingredients {
+		item := from.Ingredients[i]
+		seen[item.IngredientID]++
+		if seen[item.IngredientID] > matched[item.IngredientID] {
+			ingredients = append(ingredients, RecipeIngredientChange{
+				IngredientID: item.IngredientID, Name: item.Name, Change: "removed", From: &from.Ingredients[i],
+			})
+		}
+	}
+
+	return fields, ingredients
+}
+
+// loadRecipeRevision busca uma revisão da receita pelo número e decodifica o snapshot
+func loadRecipeRevision(w http.ResponseWriter, r *http.Request, recipeID uint, numberParam string) (*models.RecipeRevision, models.RecipeSnapshot, bool) {
+	number, err := strconv.Atoi(numberParam)
+	if err != nil || number < 1 {
+		response.ValidationError(w, "Número da revisão inválido")
+		return nil, models.RecipeSnapshot{}, false
+	}
+
+	var revision models.RecipeRevision
+	if err := database.DB.Where("recipe_id = ? AND number = ?", recipeID, number).First(&revision).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Revisão não encontrada")
+		return nil, models.RecipeSnapshot{}, false
+	}
+
+	snapshot, err := revision.Data()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to decode recipe revision", "revision_id", revision.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao ler revisão")
+		return nil, models.RecipeSnapshot{}, false
+	}
+
+	revisions := []models.RecipeRevision{revision}
+	fillRevisionAuthors(revisions)
+	return &revisions[0], snapshot, true
+}
+
+// fillRevisionAuthors preenche o nome dos autores das revisões
+// Inclui usuários removidos, que aparecem com o nome anonimizado
+func fillRevisionAuthors(revisions []models.RecipeRevision) {
+	var ids []uint
+	for _, revision := range revisions {
+		if revision.AuthorID != nil {
+			ids = append(ids, *revision.AuthorID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	var users []models.User
+	database.DB.Unscoped().Select("id", "name").Where("id IN ?", ids).Find(&users)
+
+	names := make(map[uint]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.Name
+	}
+	for i := range revisions {
+		if revisions[i].AuthorID != nil {
+			revisions[i].AuthorName = names[*revisions[i].AuthorID]
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/similarity"
+)
+
+// defaultSimilarRecipes é a quantidade padrão de receitas semelhantes retornadas
+const defaultSimilarRecipes = 10
+
+// SimilarRecipe é uma receita vizinha com a pontuação de similaridade
+type SimilarRecipe struct {
+	models.Recipe
+	Similarity float64 `json:"similarity"`
+}
+
+// GetSimilarRecipes lista receitas semelhantes ("mais como esta"), pré-calculadas pelo job de similaridade
+// ?limit=N (1 a 20, padrão 10). Apenas receitas publicadas e públicas são sugeridas
+func GetSimilarRecipes(w http.ResponseWriter, r *http.Request) {
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, chi.URLParam(r, "id")).Error; err != nil || !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	limit := defaultSimilarRecipes
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > similarity.MaxNeighbors {
+			response.ValidationError(w, "Parâmetro limit inválido. Use um valor entre 1 e 20.")
+			return
+		}
+		limit = value
+	}
+
+	// Vizinhos que deixaram de ser listáveis desde a última reconstrução são ignorados
+	var neighbors []models.RecipeSimilarity
+	if err := database.DB.Model(&models.RecipeSimilarity{}).
+		Joins("JOIN recipes ON recipes.id = recipe_similarities.similar_id AND recipes.deleted_at IS NULL").
+		Scopes(models.PubliclyListed).
+		Where("recipe_similarities.recipe_id = ?", recipe.ID).
+		Order("recipe_similarities.score DESC, recipe_similarities.similar_id ASC").
+		Limit(limit).
+		Find(&neighbors).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to load similar recipes", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to load similar recipes")
+		return
+	}
+
+	ids := make([]uint, len(neighbors))
+	for i, neighbor := range neighbors {
+		ids[i] = neighbor.SimilarID
+	}
+
+	var recipes []models.Recipe
+	if len(ids) > 0 {
+		if err := database.DB.Preload("Tags").Where("id IN ?", ids).Find(&recipes).Error; err != nil {
+			log.ErrorCtx(r.Context(), "failed to load similar recipes", "recipe_id", recipe.ID, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to load similar recipes")
+			return
+		}
+	}
+
+	byID := make(map[uint]models.Recipe, len(recipes))
+	for _, similar := range recipes {
+		byID[similar.ID] = similar
+	}
+	data := make([]SimilarRecipe, 0, len(neighbors))
+	for _, neighbor := range neighbors {
+		if similar, ok := byID[neighbor.SimilarID]; ok {
+			data = append(data, SimilarRecipe{Recipe: similar, Similarity: neighbor.Score})
+		}
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"recipe_id": recipe.ID,
+		"data":      data,
+	})
+}
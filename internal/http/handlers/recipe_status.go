@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/pagination"
+	"github.com/davidsonmarra/receitas-app/pkg/publishing"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// UpdateRecipeStatusRequest representa a mudança de status de uma receita
+// Com status=published e publish_at no futuro, a receita permanece rascunho e é publicada no horário agendado
+type UpdateRecipeStatusRequest struct {
+	Status    string     `json:"status" validate:"required,oneof=draft published archived"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// UpdateRecipeStatus publica, agenda, despublica ou arquiva uma receita
+func UpdateRecipeStatus(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateRecipeStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	now := time.Now()
+
+	if req.PublishAt != nil {
+		if req.Status != models.RecipeStatusPublished {
+			response.ValidationError(w, "publish_at só pode ser usado com status published.")
+			return
+		}
+		if !req.PublishAt.After(now) {
+			response.ValidationError(w, "publish_at deve ser uma data futura.")
+			return
+		}
+		if recipe.IsPublished() {
+			response.Error(w, http.StatusConflict, "Receita já publicada")
+			return
+		}
+	}
+
+	// Publicação (imediata ou agendada) exige receita completa
+	if req.Status == models.RecipeStatusPublished {
+		if err := publishing.CheckCompleteness(database.DB, recipe); err != nil {
+			var incomplete *publishing.IncompleteError
+			if errors.As(err, &incomplete) {
+				response.ValidationError(w, fmt.Sprintf("Receita incompleta para publicação, falta: %s.", strings.Join(incomplete.Missing, ", ")))
+				return
+			}
+			log.ErrorCtx(r.Context(), "failed to check recipe completeness", "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to update recipe status")
+			return
+		}
+	}
+
+	var err error
+	switch {
+	case req.Status == models.RecipeStatusPublished && req.PublishAt == nil:
+		err = publishing.Publish(database.DB, recipe, now)
+	case req.Status == models.RecipeStatusPublished:
+		// Agendamento: continua rascunho até o horário
+		err = database.DB.Model(recipe).Updates(map[string]interface{}{
+			"status":     models.RecipeStatusDraft,
+			"publish_at": *req.PublishAt,
+		}).Error
+		recipe.Status = models.RecipeStatusDraft
+		recipe.PublishAt = req.PublishAt
+	default:
+		// Rascunho ou arquivada: cancela qualquer agendamento
+		err = database.DB.Model(recipe).Updates(map[string]interface{}{
+			"status":     req.Status,
+			"publish_at": nil,
+		}).Error
+		recipe.Status = req.Status
+		recipe.PublishAt = nil
+	}
+
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to update recipe status", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update recipe status")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "recipe status updated",
+		"recipe_id", recipe.ID,
+		"user_id", userID,
+		"status", recipe.Status,
+		"publish_at", recipe.PublishAt)
+	response.JSON(w, http.StatusOK, recipe)
+}
+
+// ListMyRecipes lista as receitas do usuário autenticado, incluindo rascunhos e arquivadas
+// Filtro opcional: ?status=draft|published|archived
+func ListMyRecipes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	params := pagination.ExtractParams(r)
+
+	query := database.DB.Model(&models.Recipe{}).Where("user_id = ?", userID)
+	if status := r.URL.Query().Get("status"); status != "" {
+		if !isValidRecipeStatus(status) {
+			response.ValidationError(w, "Status inválido. Use draft, published ou archived.")
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to count user recipes", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to count recipes")
+		return
+	}
+
+	var recipes []models.Recipe
+	if err := query.Order("updated_at DESC").
+		Limit(params.Limit).
+		Offset(pagination.CalculateOffset(params)).
+		Find(&recipes).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list user recipes", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list recipes")
+		return
+	}
+	loadCookCounts(database.DB, recipes)
+
+	response.JSON(w, http.StatusOK, pagination.BuildResponse(recipes, params, total))
+}
+
+// isValidRecipeStatus verifica se o status informado é conhecido
+func isValidRecipeStatus(status string) bool {
+	switch status {
+	case models.RecipeStatusDraft, models.RecipeStatusPublished, models.RecipeStatusArchived:
+		return true
+	}
+	return false
+}
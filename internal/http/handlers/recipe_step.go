@@ -0,0 +1,526 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/storage"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// AddRecipeStepRequest representa os dados para adicionar um passo ao modo de preparo
+type AddRecipeStepRequest struct {
+	Text            string `json:"text" validate:"required,min=3,max=2000"`
+	DurationSeconds *int   `json:"duration_seconds,omitempty" validate:"omitempty,min=1,max=86400"`
+	IngredientIDs   []uint `json:"ingredient_ids,omitempty"` // IDs de recipe_ingredients da mesma receita
+	Order           *int   `json:"order,omitempty" validate:"omitempty,min=1"`
+}
+
+// UpdateRecipeStepRequest representa os dados para atualizar um passo
+// duration_seconds = 0 remove o timer; ingredient_ids substitui a lista (vazia remove todos)
+type UpdateRecipeStepRequest struct {
+	Text            *string `json:"text,omitempty" validate:"omitempty,min=3,max=2000"`
+	DurationSeconds *int    `json:"duration_seconds,omitempty" validate:"omitempty,min=0,max=86400"`
+	IngredientIDs   *[]uint `json:"ingredient_ids,omitempty"`
+	Order           *int    `json:"order,omitempty" validate:"omitempty,min=1"`
+}
+
+// ReorderRecipeStepsRequest representa a nova ordem dos passos
+type ReorderRecipeStepsRequest struct {
+	StepIDs []uint `json:"step_ids" validate:"required,min=1"`
+}
+
+// ListRecipeSteps lista os passos do modo de preparo de uma receita
+func ListRecipeSteps(w http.ResponseWriter, r *http.Request) {
+	recipeID := chi.URLParam(r, "id")
+
+	// Buscar receita (respeitando status e visibilidade)
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, recipeID).Error; err != nil || !canViewRecipe(r, &recipe) {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	var steps []models.RecipeStep
+	if err := database.DB.
+		Preload("Ingredients.Ingredient").
+		Where("recipe_id = ?", recipe.ID).
+		Order("\"order\" ASC, id ASC").
+		Find(&steps).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list recipe steps", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list steps")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, steps)
+}
+
+// AddRecipeStep adiciona um passo ao modo de preparo
+// Sem "order", o passo é adicionado ao final
+func AddRecipeStep(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	var req AddRecipeStepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	ingredients, ok := loadStepIngredients(w, recipe.ID, req.IngredientIDs)
+	if !ok {
+		return
+	}
+
+	step := models.RecipeStep{
+		RecipeID:        recipe.ID,
+		Text:            strings.TrimSpace(req.Text),
+		DurationSeconds: req.DurationSeconds,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if req.Order != nil {
+			step.Order = *req.Order
+		} else {
+			var maxOrder int
+			if err := tx.Model(&models.RecipeStep{}).
+				Where("recipe_id = ?", recipe.ID).
+				Select("COALESCE(MAX(\"order\"), 0)").
+				Scan(&maxOrder).Error; err != nil {
+				return err
+			}
+			step.Order = maxOrder + 1
+		}
+
+		if err := tx.Omit("Ingredients").Create(&step).Error; err != nil {
+			return err
+		}
+
+		if len(ingredients) > 0 {
+			return tx.Model(&step).Association("Ingredients").Append(ingredients)
+		}
+		return nil
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to add step to recipe", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to add step")
+		return
+	}
+
+	database.DB.Preload("Ingredients.Ingredient").First(&step, step.ID)
+
+	log.InfoCtx(r.Context(), "step added to recipe", "recipe_id", recipe.ID, "step_id", step.ID, "user_id", userID)
+	response.JSON(w, http.StatusCreated, step)
+}
+
+// UpdateRecipeStep atualiza texto, timer, ordem ou ingredientes de um passo
+func UpdateRecipeStep(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	step, ok := loadRecipeStep(w, r, recipe.ID)
+	if !ok {
+		return
+	}
+
+	var req UpdateRecipeStepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	var ingredients []models.RecipeIngredient
+	if req.IngredientIDs != nil {
+		ingredients, ok = loadStepIngredients(w, recipe.ID, *req.IngredientIDs)
+		if !ok {
+			return
+		}
+	}
+
+	// Aplicar apenas os campos que foram enviados
+	if req.Text != nil {
+		step.Text = strings.TrimSpace(*req.Text)
+	}
+	if req.DurationSeconds != nil {
+		if *req.DurationSeconds == 0 {
+			step.DurationSeconds = nil
+		} else {
+			step.DurationSeconds = req.DurationSeconds
+		}
+	}
+	if req.Order != nil {
+		step.Order = *req.Order
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Ingredients").Save(step).Error; err != nil {
+			return err
+		}
+
+		if req.IngredientIDs == nil {
+			return nil
+		}
+		if len(ingredients) == 0 {
+			return tx.Model(step).Association("Ingredients").Clear()
+		}
+		return tx.Model(step).Association("Ingredients").Replace(ingredients)
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to update recipe step", "step_id", step.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update step")
+		return
+	}
+
+	database.DB.Preload("Ingredients.Ingredient").First(step, step.ID)
+
+	log.InfoCtx(r.Context(), "recipe step updated", "recipe_id", recipe.ID, "step_id", step.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, step)
+}
+
+// DeleteRecipeStep remove um passo do modo de preparo
+func DeleteRecipeStep(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	step, ok := loadRecipeStep(w, r, recipe.ID)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(step).Association("Ingredients").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(step).Error
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete recipe step", "step_id", step.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete step")
+		return
+	}
+
+	// Remover imagem do passo (best effort)
+	if step.ImagePublicID != "" {
+		if imageService, err := storage.ServiceFactory(); err == nil {
+			imageService.DeleteImage(r.Context(), step.ImagePublicID)
+		}
+	}
+
+	log.InfoCtx(r.Context(), "recipe step deleted", "recipe_id", recipe.ID, "step_id", step.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Step removed from recipe"})
+}
+
+// ReorderRecipeSteps redefine a ordem de todos os passos da receita
+// step_ids deve conter exatamente os passos da receita, na nova ordem
+func ReorderRecipeSteps(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReorderRecipeStepsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	var existingIDs []uint
+	if err := database.DB.Model(&models.RecipeStep{}).
+		Where("recipe_id = ?", recipe.ID).
+		Pluck("id", &existingIDs).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to load recipe steps", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to reorder steps")
+		return
+	}
+
+	if !sameIDSet(existingIDs, req.StepIDs) {
+		response.ValidationError(w, "step_ids deve conter todos os passos da receita, sem repetições.")
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for i, stepID := range req.StepIDs {
+			if err := tx.Model(&models.RecipeStep{}).
+				Where("id = ? AND recipe_id = ?", stepID, recipe.ID).
+				Update("order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to reorder recipe steps", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to reorder steps")
+		return
+	}
+
+	var steps []models.RecipeStep
+	database.DB.
+		Preload("Ingredients.Ingredient").
+		Where("recipe_id = ?", recipe.ID).
+		Order("\"order\" ASC, id ASC").
+		Find(&steps)
+
+	log.InfoCtx(r.Context(), "recipe steps reordered", "recipe_id", recipe.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, steps)
+}
+
+// GenerateStepImageUploadURL gera assinatura para upload direto da imagem de um passo
+func GenerateStepImageUploadURL(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	step, ok := loadRecipeStep(w, r, recipe.ID)
+	if !ok {
+		return
+	}
+
+	imageService, err := storage.ServiceFactory()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to initialize image service", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao configurar serviço de imagens")
+		return
+	}
+
+	publicID := fmt.Sprintf("%s%d", stepImagePublicIDPrefix(step), time.Now().Unix())
+
+	uploadSig, err := imageService.GenerateUploadSignature(publicID, imageFolder)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate step upload signature", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao gerar URL de upload")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "step upload signature generated",
+		"recipe_id", recipe.ID,
+		"step_id", step.ID,
+		"public_id", publicID,
+		"user_id", userID)
+
+	response.JSON(w, http.StatusOK, uploadSig)
+}
+
+// ConfirmStepImageUpload confirma o upload direto e salva a imagem do passo
+func ConfirmStepImageUpload(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	step, ok := loadRecipeStep(w, r, recipe.ID)
+	if !ok {
+		return
+	}
+
+	var req ConfirmImageUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Dados inválidos")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		response.ValidationError(w, "Dados de confirmação incompletos")
+		return
+	}
+
+	// O public_id precisa ter sido gerado para este passo
+	if !strings.HasPrefix(path.Base(req.PublicID), stepImagePublicIDPrefix(step)) {
+		response.Error(w, http.StatusForbidden, "Imagem não pertence a este passo")
+		return
+	}
+
+	imageService, serviceErr := storage.ServiceFactory()
+
+	// Se já tinha imagem antiga, tentar deletar (best effort)
+	if step.ImagePublicID != "" && step.ImagePublicID != req.PublicID && serviceErr == nil {
+		imageService.DeleteImage(r.Context(), step.ImagePublicID)
+	}
+
+	step.ImageURL = req.SecureURL
+	step.ImagePublicID = req.PublicID
+
+	if err := database.DB.Omit("Ingredients").Save(step).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update step with image", "step_id", step.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao atualizar passo")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "step image confirmed",
+		"recipe_id", recipe.ID,
+		"step_id", step.ID,
+		"user_id", userID,
+		"public_id", req.PublicID)
+
+	response.JSON(w, http.StatusOK, step)
+}
+
+// DeleteStepImage remove a imagem de um passo
+func DeleteStepImage(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	step, ok := loadRecipeStep(w, r, recipe.ID)
+	if !ok {
+		return
+	}
+
+	if step.ImagePublicID == "" {
+		response.Error(w, http.StatusNotFound, "Este passo não possui imagem")
+		return
+	}
+
+	imageService, err := storage.ServiceFactory()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to initialize image service", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao configurar serviço de imagens")
+		return
+	}
+
+	if err := imageService.DeleteImage(r.Context(), step.ImagePublicID); err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete step image", "public_id", step.ImagePublicID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao deletar imagem")
+		return
+	}
+
+	step.ImageURL = ""
+	step.ImagePublicID = ""
+
+	if err := database.DB.Omit("Ingredients").Save(step).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update step", "step_id", step.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Erro ao atualizar passo")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "step image deleted", "recipe_id", recipe.ID, "step_id", step.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Imagem removida com sucesso"})
+}
+
+// loadModifiableRecipe busca a receita da URL e verifica se o usuário pode modificá-la
+func loadModifiableRecipe(w http.ResponseWriter, r *http.Request) (*models.Recipe, uint, bool) {
+	recipeID := chi.URLParam(r, "id")
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return nil, 0, false
+	}
+
+	var recipe models.Recipe
+	if err := database.DB.First(&recipe, recipeID).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Recipe not found")
+		return nil, 0, false
+	}
+
+	if !canModifyRecipe(&recipe, userID) {
+		response.Error(w, http.StatusForbidden, "You don't have permission to modify this recipe")
+		return nil, 0, false
+	}
+
+	return &recipe, userID, true
+}
+
+// loadRecipeStep busca o passo da URL garantindo que pertence à receita
+func loadRecipeStep(w http.ResponseWriter, r *http.Request, recipeID uint) (*models.RecipeStep, bool) {
+	var step models.RecipeStep
+	if err := database.DB.Where("recipe_id = ? AND id = ?", recipeID, chi.URLParam(r, "step_id")).
+		First(&step).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Recipe step not found")
+		return nil, false
+	}
+	return &step, true
+}
+
+// loadStepIngredients busca os ingredientes referenciados por um passo
+// Todos precisam pertencer à mesma receita
+func loadStepIngredients(w http.ResponseWriter, recipeID uint, ids []uint) ([]models.RecipeIngredient, bool) {
+	if len(ids) == 0 {
+		return nil, true
+	}
+
+	var ingredients []models.RecipeIngredient
+	if err := database.DB.Where("recipe_id = ? AND id IN ?", recipeID, ids).
+		Find(&ingredients).Error; err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to load ingredients")
+		return nil, false
+	}
+
+	if !sameIDSet(recipeIngredientIDs(ingredients), ids) {
+		response.ValidationError(w, "ingredient_ids deve conter apenas ingredientes desta receita, sem repetições.")
+		return nil, false
+	}
+
+	return ingredients, true
+}
+
+// recipeIngredientIDs extrai os IDs de uma lista de ingredientes da receita
+func recipeIngredientIDs(ingredients []models.RecipeIngredient) []uint {
+	ids := make([]uint, len(ingredients))
+	for i, ri := range ingredients {
+		ids[i] = ri.ID
+	}
+	return ids
+}
+
+// sameIDSet verifica se as duas listas têm exatamente os mesmos IDs, sem repetições
+func sameIDSet(expected, got []uint) bool {
+	if len(expected) != len(got) {
+		return false
+	}
+
+	seen := make(map[uint]bool, len(expected))
+	for _, id := range expected {
+		seen[id] = true
+	}
+	for _, id := range got {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
+// stepImagePublicIDPrefix retorna o prefixo de public_id reservado para o passo
+func stepImagePublicIDPrefix(step *models.RecipeStep) string {
+	return fmt.Sprintf("recipe_%d_step_%d_", step.RecipeID, step.ID)
+}
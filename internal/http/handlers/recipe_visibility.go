@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// UpdateRecipeVisibilityRequest representa a mudança de visibilidade de uma receita
+type UpdateRecipeVisibilityRequest struct {
+	Visibility string `json:"visibility" validate:"required,oneof=private unlisted public"`
+}
+
+// RecipeShareResponse representa a visibilidade e o link de compartilhamento de uma receita
+// O token só é exibido para quem pode modificar a receita
+type RecipeShareResponse struct {
+	RecipeID   uint    `json:"recipe_id"`
+	Visibility string  `json:"visibility"`
+	ShareToken *string `json:"share_token"`
+}
+
+// UpdateRecipeVisibility altera a visibilidade da receita
+// Tornar a receita não listada gera um link de compartilhamento; sair de não listada o revoga
+func UpdateRecipeVisibility(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateRecipeVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if err := applyRecipeVisibility(recipe, req.Visibility); err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate share token", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update recipe visibility")
+		return
+	}
+
+	if !saveRecipeShare(w, r, recipe) {
+		return
+	}
+
+	log.InfoCtx(r.Context(), "recipe visibility updated", "recipe_id", recipe.ID, "user_id", userID, "visibility", recipe.Visibility)
+	response.JSON(w, http.StatusOK, newRecipeShareResponse(recipe))
+}
+
+// GetRecipeShare retorna a visibilidade e o token de compartilhamento da receita
+func GetRecipeShare(w http.ResponseWriter, r *http.Request) {
+	recipe, _, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, newRecipeShareResponse(recipe))
+}
+
+// RegenerateRecipeShareToken gera um novo link de compartilhamento, invalidando o anterior
+func RegenerateRecipeShareToken(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	if recipe.Visibility != models.RecipeVisibilityUnlisted {
+		response.Error(w, http.StatusConflict, "Apenas receitas não listadas têm link de compartilhamento")
+		return
+	}
+
+	token, err := auth.GenerateRandomToken()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate share token", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to regenerate share link")
+		return
+	}
+	recipe.ShareToken = &token
+
+	if !saveRecipeShare(w, r, recipe) {
+		return
+	}
+
+	log.InfoCtx(r.Context(), "recipe share token regenerated", "recipe_id", recipe.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, newRecipeShareResponse(recipe))
+}
+
+// RevokeRecipeShareToken revoga o link de compartilhamento
+// A receita continua não listada, visível apenas para o dono até um novo link ser gerado
+func RevokeRecipeShareToken(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	recipe.ShareToken = nil
+	if !saveRecipeShare(w, r, recipe) {
+		return
+	}
+
+	log.InfoCtx(r.Context(), "recipe share token revoked", "recipe_id", recipe.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, newRecipeShareResponse(recipe))
+}
+
+// applyRecipeVisibility define a visibilidade, gerando ou descartando o token de compartilhamento
+// Visibilidade vazia equivale a pública
+func applyRecipeVisibility(recipe *models.Recipe, visibility string) error {
+	if visibility == "" {
+		visibility = models.RecipeVisibilityPublic
+	}
+	recipe.Visibility = visibility
+
+	if visibility != models.RecipeVisibilityUnlisted {
+		recipe.ShareToken = nil
+		return nil
+	}
+
+	if recipe.ShareToken == nil {
+		token, err := auth.GenerateRandomToken()
+		if err != nil {
+			return err
+		}
+		recipe.ShareToken = &token
+	}
+	return nil
+}
+
+// saveRecipeShare persiste visibilidade e token. Responde com erro e retorna false em caso de falha.
+func saveRecipeShare(w http.ResponseWriter, r *http.Request, recipe *models.Recipe) bool {
+	if err := database.DB.Model(recipe).Updates(map[string]interface{}{
+		"visibility":  recipe.Visibility,
+		"share_token": recipe.ShareToken,
+	}).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to update recipe share settings", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update recipe visibility")
+		return false
+	}
+	return true
+}
+
+// validShareToken compara o token informado com o da receita em tempo constante
+func validShareToken(recipe *models.Recipe, token string) bool {
+	if recipe.ShareToken == nil || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(*recipe.ShareToken), []byte(token)) == 1
+}
+
+func newRecipeShareResponse(recipe *models.Recipe) RecipeShareResponse {
+	return RecipeShareResponse{
+		RecipeID:   recipe.ID,
+		Visibility: recipe.Visibility,
+		ShareToken: recipe.ShareToken,
+	}
+}
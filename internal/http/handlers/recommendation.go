@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/recommendation"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+)
+
+// Quantidade de recomendações retornadas
+const (
+	defaultRecommendations = 20
+	maxRecommendations     = 50
+)
+
+// RecommendedRecipe é uma receita recomendada com a origem da recomendação
+type RecommendedRecipe struct {
+	models.Recipe
+	Recommendation recommendation.Recommendation `json:"recommendation"`
+}
+
+// GetMyRecommendations recomenda receitas a partir do histórico de avaliações do usuário
+// ?limit=N (1 a 50, padrão 20)
+func GetMyRecommendations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	limit := defaultRecommendations
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > maxRecommendations {
+			response.ValidationError(w, "Parâmetro limit inválido. Use um valor entre 1 e 50.")
+			return
+		}
+		limit = value
+	}
+
+	recommendations, err := recommendation.Recommend(database.DB, userID, limit)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to build recommendations", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to load recommendations")
+		return
+	}
+
+	ids := make([]uint, len(recommendations))
+	for i, rec := range recommendations {
+		ids[i] = rec.RecipeID
+	}
+
+	var recipes []models.Recipe
+	if len(ids) > 0 {
+		if err := database.DB.Preload("Tags").Where("id IN ?", ids).Find(&recipes).Error; err != nil {
+			log.ErrorCtx(r.Context(), "failed to load recommended recipes", "user_id", userID, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to load recommendations")
+			return
+		}
+	}
+
+	byID := make(map[uint]models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		byID[recipe.ID] = recipe
+	}
+	data := make([]RecommendedRecipe, 0, len(recommendations))
+	for _, rec := range recommendations {
+		if recipe, ok := byID[rec.RecipeID]; ok {
+			data = append(data, RecommendedRecipe{Recipe: recipe, Recommendation: rec})
+		}
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"data": data,
+	})
+}
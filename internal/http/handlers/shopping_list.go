@@ -0,0 +1,523 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/shopping"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// defaultShoppingListName é usado quando a lista é criada sem nome
+const defaultShoppingListName = "Lista de compras"
+
+// maxShoppingListDays limita o período de um planejamento usado para gerar a lista
+const maxShoppingListDays = 31
+
+// ShoppingListRecipeRequest representa uma receita da lista e as porções desejadas
+type ShoppingListRecipeRequest struct {
+	RecipeID uint `json:"recipe_id" validate:"required"`
+	Servings int  `json:"servings" validate:"omitempty,min=1,max=100"` // Padrão: porções da receita
+}
+
+// CreateShoppingListRequest representa a geração de uma lista de compras
+// A origem é uma lista de receitas ou um período (inclusivo) de um planejamento
+type CreateShoppingListRequest struct {
+	Name       string                      `json:"name" validate:"omitempty,max=100"`
+	Recipes    []ShoppingListRecipeRequest `json:"recipes" validate:"omitempty,max=50,dive"`
+	MealPlanID *uint                       `json:"meal_plan_id"`
+	From       string                      `json:"from"`
+	To         string                      `json:"to"`
+}
+
+// ShoppingListItemRequest representa um item adicionado manualmente
+type ShoppingListItemRequest struct {
+	Name     string   `json:"name" validate:"required,min=1,max=150"`
+	Category string   `json:"category" validate:"max=100"`
+	Quantity *float64 `json:"quantity" validate:"omitempty,gt=0"`
+	Unit     string   `json:"unit" validate:"max=50"`
+}
+
+// CheckShoppingListItemRequest marca ou desmarca um item
+type CheckShoppingListItemRequest struct {
+	Checked *bool `json:"checked" validate:"required"`
+}
+
+// ShoppingListResponse representa a lista com os itens agrupados por corredor
+type ShoppingListResponse struct {
+	ID         uint             `json:"id"`
+	Name       string           `json:"name"`
+	MealPlanID *uint            `json:"meal_plan_id,omitempty"`
+	Shared     bool             `json:"shared"`
+	Groups     []shopping.Group `json:"groups"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// newShoppingListResponse converte o modelo para a resposta da API
+func newShoppingListResponse(list *models.ShoppingList) ShoppingListResponse {
+	return ShoppingListResponse{
+		ID:         list.ID,
+		Name:       list.Name,
+		MealPlanID: list.MealPlanID,
+		Shared:     list.ShareToken != nil,
+		Groups:     shopping.GroupByCategory(list.Items),
+		CreatedAt:  list.CreatedAt,
+		UpdatedAt:  list.UpdatedAt,
+	}
+}
+
+// ListShoppingLists lista as listas de compras do usuário (sem os itens)
+func ListShoppingLists(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var lists []models.ShoppingList
+	if err := database.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&lists).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list shopping lists", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list shopping lists")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"shopping_lists": lists,
+	})
+}
+
+// CreateShoppingList gera uma lista de compras a partir de receitas ou de um período do planejamento
+// Ingredientes iguais são somados (com conversão de unidades) e agrupados por categoria
+func CreateShoppingList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	var req CreateShoppingListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if (len(req.Recipes) > 0) == (req.MealPlanID != nil) {
+		response.ValidationError(w, "Informe recipes ou meal_plan_id (com from e to).")
+		return
+	}
+
+	// Fator de porções por receita (a mesma receita pode aparecer mais de uma vez)
+	var factors map[uint]float64
+	if req.MealPlanID != nil {
+		factors, ok = mealPlanRecipeFactors(w, r, userID, &req)
+	} else {
+		factors, ok = requestedRecipeFactors(w, r, req.Recipes)
+	}
+	if !ok {
+		return
+	}
+
+	sources, err := shoppingSources(factors)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to load recipe ingredients", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create shopping list")
+		return
+	}
+
+	list := models.ShoppingList{
+		UserID:     userID,
+		Name:       strings.TrimSpace(req.Name),
+		MealPlanID: req.MealPlanID,
+		Items:      shopping.Aggregate(sources),
+	}
+	if list.Name == "" {
+		list.Name = defaultShoppingListName
+	}
+
+	if err := database.DB.Create(&list).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to create shopping list", "user_id", userID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create shopping list")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "shopping list created", "shopping_list_id", list.ID, "user_id", userID,
+		"recipes", len(factors), "items", len(list.Items))
+	response.JSON(w, http.StatusCreated, newShoppingListResponse(&list))
+}
+
+// GetShoppingList retorna a lista agrupada por corredor (dono ou ?share_token=)
+// ?format=text retorna texto simples; ?format=checklist, o formato para apps de mensagem
+func GetShoppingList(w http.ResponseWriter, r *http.Request) {
+	list, _, ok := loadShoppingList(w, r)
+	if !ok {
+		return
+	}
+
+	var export string
+	switch r.URL.Query().Get("format") {
+	case "":
+		response.JSON(w, http.StatusOK, newShoppingListResponse(list))
+		return
+	case "text":
+		export = shopping.Text(*list)
+	case "checklist":
+		export = shopping.Checklist(*list)
+	default:
+		response.ValidationError(w, "Formato inválido. Use text ou checklist.")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(export))
+}
+
+// DeleteShoppingList remove a lista e seus itens (apenas o dono)
+func DeleteShoppingList(w http.ResponseWriter, r *http.Request) {
+	list, userID, ok := loadOwnedShoppingList(w, r)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shopping_list_id = ?", list.ID).Delete(&models.ShoppingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(list).Error
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete shopping list", "shopping_list_id", list.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete shopping list")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "shopping list deleted", "shopping_list_id", list.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "Lista removida com sucesso",
+	})
+}
+
+// AddShoppingListItem adiciona um item manual à lista (apenas o dono)
+func AddShoppingListItem(w http.ResponseWriter, r *http.Request) {
+	list, userID, ok := loadOwnedShoppingList(w, r)
+	if !ok {
+		return
+	}
+
+	var req ShoppingListItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	item := models.ShoppingListItem{
+		ShoppingListID: list.ID,
+		Name:           strings.TrimSpace(req.Name),
+		Category:       strings.ToLower(strings.TrimSpace(req.Category)),
+		Quantity:       req.Quantity,
+		Unit:           strings.TrimSpace(req.Unit),
+		Manual:         true,
+	}
+	if item.Category == "" {
+		item.Category = shopping.DefaultCategory
+	}
+
+	if err := database.DB.Create(&item).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to add shopping list item", "shopping_list_id", list.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to add item")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "shopping list item added", "shopping_list_id", list.ID, "item_id", item.ID, "user_id", userID)
+	response.JSON(w, http.StatusCreated, item)
+}
+
+// CheckShoppingListItem marca ou desmarca um item (dono ou ?share_token=)
+func CheckShoppingListItem(w http.ResponseWriter, r *http.Request) {
+	list, _, ok := loadShoppingList(w, r)
+	if !ok {
+		return
+	}
+
+	item, ok := loadShoppingListItem(w, r, list)
+	if !ok {
+		return
+	}
+
+	var req CheckShoppingListItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	item.Checked = *req.Checked
+	if err := database.DB.Model(item).Update("checked", item.Checked).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to check shopping list item", "item_id", item.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update item")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, item)
+}
+
+// DeleteShoppingListItem remove um item da lista (apenas o dono)
+func DeleteShoppingListItem(w http.ResponseWriter, r *http.Request) {
+	list, userID, ok := loadOwnedShoppingList(w, r)
+	if !ok {
+		return
+	}
+
+	item, ok := loadShoppingListItem(w, r, list)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(item).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to delete shopping list item", "item_id", item.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete item")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "shopping list item deleted", "shopping_list_id", list.ID, "item_id", item.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message": "Item removido com sucesso",
+	})
+}
+
+// ShareShoppingList gera um novo link de compartilhamento, invalidando o anterior
+// Quem tem o link pode ver a lista e marcar itens, sem precisar de conta
+func ShareShoppingList(w http.ResponseWriter, r *http.Request) {
+	list, userID, ok := loadOwnedShoppingList(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := auth.GenerateRandomToken()
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to generate share token", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to share shopping list")
+		return
+	}
+
+	if err := database.DB.Model(list).Update("share_token", token).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to save share token", "shopping_list_id", list.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to share shopping list")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "shopping list shared", "shopping_list_id", list.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"shopping_list_id": list.ID,
+		"share_token":      token,
+	})
+}
+
+// RevokeShoppingListShare revoga o link de compartilhamento
+func RevokeShoppingListShare(w http.ResponseWriter, r *http.Request) {
+	list, userID, ok := loadOwnedShoppingList(w, r)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Model(list).Update("share_token", nil).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to revoke share token", "shopping_list_id", list.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to revoke share link")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "shopping list share revoked", "shopping_list_id", list.ID, "user_id", userID)
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"shopping_list_id": list.ID,
+		"share_token":      nil,
+	})
+}
+
+// loadShoppingList carrega a lista da URL com os itens, para o dono ou para quem tem o link (?share_token=)
+// O ID do usuário retornado é zero quando o acesso é apenas pelo link
+func loadShoppingList(w http.ResponseWriter, r *http.Request) (*models.ShoppingList, uint, bool) {
+	var list models.ShoppingList
+	if err := database.DB.Preload("Items").First(&list, chi.URLParam(r, "id")).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Shopping list not found")
+		return nil, 0, false
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(r.Context())
+	if list.UserID != userID && !validShoppingListToken(&list, r.URL.Query().Get("share_token")) {
+		response.Error(w, http.StatusNotFound, "Shopping list not found")
+		return nil, 0, false
+	}
+
+	if list.UserID != userID {
+		userID = 0
+	}
+	return &list, userID, true
+}
+
+// loadOwnedShoppingList carrega a lista exigindo que o usuário seja o dono
+func loadOwnedShoppingList(w http.ResponseWriter, r *http.Request) (*models.ShoppingList, uint, bool) {
+	list, userID, ok := loadShoppingList(w, r)
+	if !ok {
+		return nil, 0, false
+	}
+
+	if userID == 0 {
+		response.Error(w, http.StatusForbidden, "Apenas o dono pode alterar a lista")
+		return nil, 0, false
+	}
+
+	return list, userID, true
+}
+
+// loadShoppingListItem carrega o item da URL, garantindo que pertence à lista
+func loadShoppingListItem(w http.ResponseWriter, r *http.Request, list *models.ShoppingList) (*models.ShoppingListItem, bool) {
+	var item models.ShoppingListItem
+	if err := database.DB.Where("shopping_list_id = ?", list.ID).First(&item, chi.URLParam(r, "item_id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(w, http.StatusNotFound, "Item not found")
+			return nil, false
+		}
+		log.ErrorCtx(r.Context(), "failed to load shopping list item", "shopping_list_id", list.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to load item")
+		return nil, false
+	}
+	return &item, true
+}
+
+// validShoppingListToken compara o token informado com o da lista em tempo constante
+func validShoppingListToken(list *models.ShoppingList, token string) bool {
+	if list.ShareToken == nil || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(*list.ShareToken), []byte(token)) == 1
+}
+
+// requestedRecipeFactors valida as receitas pedidas e calcula o fator de porções de cada uma
+func requestedRecipeFactors(w http.ResponseWriter, r *http.Request, requested []ShoppingListRecipeRequest) (map[uint]float64, bool) {
+	factors := make(map[uint]float64, len(requested))
+	for _, item := range requested {
+		var recipe models.Recipe
+		if err := database.DB.First(&recipe, item.RecipeID).Error; err != nil || !canViewRecipe(r, &recipe) {
+			response.ValidationError(w, "Receita não encontrada: "+strconv.FormatUint(uint64(item.RecipeID), 10)+".")
+			return nil, false
+		}
+
+		servings := item.Servings
+		if servings == 0 {
+			servings = recipe.Servings
+		}
+		factors[recipe.ID] += servingsFactor(servings, recipe.Servings)
+	}
+	return factors, true
+}
+
+// mealPlanRecipeFactors soma as porções planejadas de cada receita no período do planejamento
+// Refeições cuja receita foi removida ou não é visível para quem gera a lista são ignoradas
+func mealPlanRecipeFactors(w http.ResponseWriter, r *http.Request, userID uint, req *CreateShoppingListRequest) (map[uint]float64, bool) {
+	from, errFrom := time.Parse(models.DateLayout, req.From)
+	to, errTo := time.Parse(models.DateLayout, req.To)
+	if errFrom != nil || errTo != nil {
+		response.ValidationError(w, "Informe from e to no formato YYYY-MM-DD.")
+		return nil, false
+	}
+	if to.Before(from) || to.Sub(from) >= maxShoppingListDays*24*time.Hour {
+		response.ValidationError(w, "Período inválido: to deve ser posterior a from, com no máximo 31 dias.")
+		return nil, false
+	}
+
+	var plan models.MealPlan
+	if err := database.DB.First(&plan, *req.MealPlanID).Error; err != nil || !canAccessMealPlan(&plan, userID) {
+		response.Error(w, http.StatusNotFound, "Meal plan not found")
+		return nil, false
+	}
+
+	var slots []models.MealPlanSlot
+	if err := database.DB.Where("meal_plan_id = ? AND date BETWEEN ? AND ?", plan.ID, req.From, req.To).
+		Preload("Recipe").
+		Find(&slots).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to load meal plan slots", "meal_plan_id", plan.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create shopping list")
+		return nil, false
+	}
+	hideUnavailableSlotRecipes(r, slots)
+
+	factors := make(map[uint]float64)
+	for _, slot := range slots {
+		if slot.Recipe == nil {
+			continue
+		}
+		factors[slot.RecipeID] += servingsFactor(slot.Servings, slot.Recipe.Servings)
+	}
+	return factors, true
+}
+
+// shoppingSources carrega os ingredientes das receitas já multiplicados pelo fator de porções
+func shoppingSources(factors map[uint]float64) ([]shopping.Source, error) {
+	if len(factors) == 0 {
+		return nil, nil
+	}
+
+	recipeIDs := make([]uint, 0, len(factors))
+	for id := range factors {
+		recipeIDs = append(recipeIDs, id)
+	}
+
+	var items []models.RecipeIngredient
+	if err := database.DB.Where("recipe_id IN ?", recipeIDs).
+		Preload("Ingredient").
+		Order("recipe_id ASC, \"order\" ASC, id ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	sources := make([]shopping.Source, 0, len(items))
+	for _, item := range items {
+		sources = append(sources, shopping.Source{
+			Ingredient: item.Ingredient,
+			Quantity:   item.Quantity * factors[item.RecipeID],
+			Unit:       item.Unit,
+		})
+	}
+	return sources, nil
+}
+
+// servingsFactor é a proporção entre as porções desejadas e as porções da receita
+func servingsFactor(servings, recipeServings int) float64 {
+	if recipeServings < 1 {
+		recipeServings = 1
+	}
+	return float64(servings) / float64(recipeServings)
+}
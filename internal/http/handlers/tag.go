@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/middleware"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/pagination"
+	"github.com/davidsonmarra/receitas-app/pkg/response"
+	"github.com/davidsonmarra/receitas-app/pkg/validation"
+)
+
+// TagRequest representa os dados para criar ou sugerir uma tag
+type TagRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+	Type string `json:"type" validate:"required,oneof=cuisine meal_type technique occasion"`
+}
+
+// UpdateTagRequest representa os dados permitidos para editar uma tag (admin)
+type UpdateTagRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=2,max=100"`
+	Type *string `json:"type" validate:"omitempty,oneof=cuisine meal_type technique occasion"`
+}
+
+// SetRecipeTagsRequest representa a lista completa de tags de uma receita
+type SetRecipeTagsRequest struct {
+	TagIDs []uint `json:"tag_ids" validate:"max=20"`
+}
+
+// ListTags lista as tags aprovadas, opcionalmente filtradas por tipo (?type=cuisine)
+func ListTags(w http.ResponseWriter, r *http.Request) {
+	query := database.DB.Where("status = ?", models.TagStatusApproved)
+
+	if tagType := r.URL.Query().Get("type"); tagType != "" {
+		if !isValidTagType(tagType) {
+			response.ValidationError(w, "Tipo de tag inválido. Use cuisine, meal_type, technique ou occasion.")
+			return
+		}
+		query = query.Where("type = ?", tagType)
+	}
+
+	var tags []models.Tag
+	if err := query.Order("type ASC, name ASC").Find(&tags).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to list tags", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list tags")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"tags": tags,
+	})
+}
+
+// SuggestTag registra uma sugestão de tag do usuário, pendente de aprovação
+func SuggestTag(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Autenticação necessária")
+		return
+	}
+
+	tag, ok := decodeNewTag(w, r)
+	if !ok {
+		return
+	}
+	tag.Status = models.TagStatusPending
+	tag.SuggestedByID = &userID
+
+	if !createTag(w, r, tag) {
+		return
+	}
+
+	log.InfoCtx(r.Context(), "tag suggested", "tag_id", tag.ID, "user_id", userID, "type", tag.Type)
+	response.JSON(w, http.StatusCreated, tag)
+}
+
+// SetRecipeTags substitui as tags de uma receita (apenas tags aprovadas)
+func SetRecipeTags(w http.ResponseWriter, r *http.Request) {
+	recipe, userID, ok := loadModifiableRecipe(w, r)
+	if !ok {
+		return
+	}
+
+	var req SetRecipeTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	tags := []models.Tag{}
+	if len(req.TagIDs) > 0 {
+		if err := database.DB.Where("id IN ? AND status = ?", req.TagIDs, models.TagStatusApproved).
+			Order("type ASC, name ASC").
+			Find(&tags).Error; err != nil {
+			log.ErrorCtx(r.Context(), "failed to load tags", "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to update recipe tags")
+			return
+		}
+		if !sameIDSet(req.TagIDs, tagIDs(tags)) {
+			response.ValidationError(w, "Tags inexistentes ou ainda não aprovadas.")
+			return
+		}
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(recipe).Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+		return touchRecipe(tx, recipe)
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to update recipe tags", "recipe_id", recipe.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update recipe tags")
+		return
+	}
+
+	log.InfoCtx(r.Context(), "recipe tags updated", "recipe_id", recipe.ID, "user_id", userID, "tags", len(tags))
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"recipe_id": recipe.ID,
+		"tags":      tags,
+	})
+}
+
+// AdminListTags lista tags em qualquer status (filtros opcionais ?status= e ?type=)
+func AdminListTags(w http.ResponseWriter, r *http.Request) {
+	params := pagination.ExtractParams(r)
+
+	query := database.DB.Model(&models.Tag{})
+	if status := r.URL.Query().Get("status"); status != "" {
+		if status != models.TagStatusApproved && status != models.TagStatusPending && status != models.TagStatusRejected {
+			response.ValidationError(w, "Status inválido. Use approved, pending ou rejected.")
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+	if tagType := r.URL.Query().Get("type"); tagType != "" {
+		if !isValidTagType(tagType) {
+			response.ValidationError(w, "Tipo de tag inválido. Use cuisine, meal_type, technique ou occasion.")
+			return
+		}
+		query = query.Where("type = ?", tagType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.ErrorCtx(r.Context(), "admin failed to count tags", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list tags")
+		return
+	}
+
+	var tags []models.Tag
+	if err := query.Order("created_at DESC").
+		Limit(params.Limit).
+		Offset(pagination.CalculateOffset(params)).
+		Find(&tags).Error; err != nil {
+		log.ErrorCtx(r.Context(), "admin failed to list tags", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to list tags")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, pagination.BuildResponse(tags, params, total))
+}
+
+// AdminCreateTag cria uma tag canônica (já aprovada)
+func AdminCreateTag(w http.ResponseWriter, r *http.Request) {
+	tag, ok := decodeNewTag(w, r)
+	if !ok {
+		return
+	}
+	tag.Status = models.TagStatusApproved
+
+	if !createTag(w, r, tag) {
+		return
+	}
+
+	adminID, _ := middleware.GetUserIDFromContext(r.Context())
+	log.InfoCtx(r.Context(), "admin created tag", "admin_id", adminID, "tag_id", tag.ID, "type", tag.Type)
+	response.JSON(w, http.StatusCreated, tag)
+}
+
+// AdminUpdateTag renomeia uma tag ou muda seu tipo
+func AdminUpdateTag(w http.ResponseWriter, r *http.Request) {
+	tag, ok := loadTag(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return
+	}
+
+	if req.Name != nil {
+		tag.Name = strings.TrimSpace(*req.Name)
+		tag.Slug = slugify(tag.Name)
+	}
+	if req.Type != nil {
+		tag.Type = *req.Type
+	}
+
+	if tag.Slug == "" {
+		response.ValidationError(w, "O nome da tag precisa ter letras ou números.")
+		return
+	}
+	if !ensureTagAvailable(w, r, tag) {
+		return
+	}
+
+	if err := database.DB.Save(tag).Error; err != nil {
+		log.ErrorCtx(r.Context(), "admin failed to update tag", "tag_id", tag.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update tag")
+		return
+	}
+
+	adminID, _ := middleware.GetUserIDFromContext(r.Context())
+	log.InfoCtx(r.Context(), "admin updated tag", "admin_id", adminID, "tag_id", tag.ID)
+	response.JSON(w, http.StatusOK, tag)
+}
+
+// AdminApproveTag aprova uma sugestão, tornando-a canônica
+func AdminApproveTag(w http.ResponseWriter, r *http.Request) {
+	moderateTag(w, r, models.TagStatusApproved)
+}
+
+// AdminRejectTag rejeita uma sugestão
+func AdminRejectTag(w http.ResponseWriter, r *http.Request) {
+	moderateTag(w, r, models.TagStatusRejected)
+}
+
+// AdminDeleteTag exclui a tag e a remove de todas as receitas
+func AdminDeleteTag(w http.ResponseWriter, r *http.Request) {
+	tag, ok := loadTag(w, r)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM recipe_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tag).Error
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "admin failed to delete tag", "tag_id", tag.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to delete tag")
+		return
+	}
+
+	adminID, _ := middleware.GetUserIDFromContext(r.Context())
+	log.InfoCtx(r.Context(), "admin deleted tag", "admin_id", adminID, "tag_id", tag.ID, "name", tag.Name)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Tag deleted"})
+}
+
+// moderateTag muda o status de moderação de uma tag
+// Tags em uso não podem ser rejeitadas (remova-as das receitas ou exclua a tag)
+func moderateTag(w http.ResponseWriter, r *http.Request, status string) {
+	tag, ok := loadTag(w, r)
+	if !ok {
+		return
+	}
+
+	if status == models.TagStatusRejected && tag.Status == models.TagStatusApproved {
+		var inUse int64
+		if err := database.DB.Table("recipe_tags").Where("tag_id = ?", tag.ID).Count(&inUse).Error; err != nil {
+			log.ErrorCtx(r.Context(), "failed to count tag usage", "tag_id", tag.ID, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to update tag")
+			return
+		}
+		if inUse > 0 {
+			response.Error(w, http.StatusConflict, "Tag em uso por receitas não pode ser rejeitada")
+			return
+		}
+	}
+
+	if err := database.DB.Model(tag).Update("status", status).Error; err != nil {
+		log.ErrorCtx(r.Context(), "admin failed to moderate tag", "tag_id", tag.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to update tag")
+		return
+	}
+
+	adminID, _ := middleware.GetUserIDFromContext(r.Context())
+	log.InfoCtx(r.Context(), "admin moderated tag", "admin_id", adminID, "tag_id", tag.ID, "status", status)
+	response.JSON(w, http.StatusOK, tag)
+}
+
+// decodeNewTag decodifica e valida os dados de uma nova tag
+func decodeNewTag(w http.ResponseWriter, r *http.Request) (*models.Tag, bool) {
+	var req TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ValidationError(w, "Formato de dados inválido.")
+		return nil, false
+	}
+
+	if errs := validation.ValidateStruct(req); errs != nil {
+		message := validation.FormatErrors(errs)
+		response.ValidationError(w, message)
+		return nil, false
+	}
+
+	tag := &models.Tag{
+		Name: strings.TrimSpace(req.Name),
+		Slug: slugify(req.Name),
+		Type: req.Type,
+	}
+	if tag.Slug == "" {
+		response.ValidationError(w, "O nome da tag precisa ter letras ou números.")
+		return nil, false
+	}
+
+	return tag, true
+}
+
+// createTag grava uma nova tag, recusando duplicatas (mesmo tipo e slug)
+func createTag(w http.ResponseWriter, r *http.Request, tag *models.Tag) bool {
+	if !ensureTagAvailable(w, r, tag) {
+		return false
+	}
+
+	if err := database.DB.Create(tag).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to create tag", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to create tag")
+		return false
+	}
+	return true
+}
+
+// ensureTagAvailable verifica se já existe outra tag com o mesmo tipo e slug
+func ensureTagAvailable(w http.ResponseWriter, r *http.Request, tag *models.Tag) bool {
+	var existing models.Tag
+	err := database.DB.Where("type = ? AND slug = ? AND id <> ?", tag.Type, tag.Slug, tag.ID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true
+	}
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to check tag", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to save tag")
+		return false
+	}
+
+	switch existing.Status {
+	case models.TagStatusPending:
+		response.Error(w, http.StatusConflict, "Tag já sugerida, aguardando aprovação")
+	case models.TagStatusRejected:
+		response.Error(w, http.StatusConflict, "Tag rejeitada pela moderação")
+	default:
+		response.Error(w, http.StatusConflict, "Tag já existe")
+	}
+	return false
+}
+
+// loadTag busca a tag da URL
+func loadTag(w http.ResponseWriter, r *http.Request) (*models.Tag, bool) {
+	var tag models.Tag
+	if err := database.DB.First(&tag, chi.URLParam(r, "tag_id")).Error; err != nil {
+		response.Error(w, http.StatusNotFound, "Tag not found")
+		return nil, false
+	}
+	return &tag, true
+}
+
+// isValidTagType verifica se o tipo informado é uma faceta conhecida
+func isValidTagType(tagType string) bool {
+	for _, t := range models.TagTypes {
+		if t == tagType {
+			return true
+		}
+	}
+	return false
+}
+
+// tagIDs extrai os IDs de uma lista de tags
+func tagIDs(tags []models.Tag) []uint {
+	ids := make([]uint, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	return ids
+}
+
+// accentReplacer remove acentos comuns do português para montar slugs
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// slugify gera o identificador normalizado de uma tag ("Café da Manhã" -> "cafe-da-manha")
+func slugify(name string) string {
+	normalized := accentReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range normalized {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
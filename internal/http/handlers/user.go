@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/davidsonmarra/receitas-app/internal/models"
 	"github.com/davidsonmarra/receitas-app/pkg/auth"
 	"github.com/davidsonmarra/receitas-app/pkg/database"
 	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/mailer"
 	"github.com/davidsonmarra/receitas-app/pkg/response"
 	"github.com/davidsonmarra/receitas-app/pkg/validation"
 	"gorm.io/gorm"
@@ -18,7 +21,7 @@ import (
 type RegisterRequest struct {
 	Name     string `json:"name" validate:"required,min=3,max=100"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required,min=8,max=128"`
 }
 
 // LoginRequest representa os dados de login
@@ -54,6 +57,12 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Validar política de senha (inclui denylist de senhas comuns)
+	if err := auth.ValidatePasswordPolicy(req.Password, req.Name, req.Email); err != nil {
+		response.ValidationError(w, validation.FormatErrors([]string{err.Error()}))
+		return
+	}
+
 	// Verificar se o email já existe
 	var existingUser models.User
 	err := database.DB.Where("email = ?", req.Email).First(&existingUser).Error
@@ -87,38 +96,16 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Gerar access token JWT (incluindo role)
-	accessToken, err := auth.GenerateToken(user.ID, user.Email, user.Role)
-	if err != nil {
-		log.ErrorCtx(r.Context(), "failed to generate access token", "error", err)
-		response.Error(w, http.StatusInternalServerError, "Erro ao gerar token")
-		return
-	}
-
-	// Gerar refresh token
-	refreshToken, err := auth.CreateRefreshToken(auth.RefreshTokenInfo{
-		UserID:            user.ID,
-		Email:             user.Email,
-		Role:              user.Role,
-		DeviceName:        getDeviceName(r),
-		DeviceFingerprint: getDeviceFingerprint(r),
-		IPAddress:         getClientIP(r),
-	})
+	// Gerar access token e refresh token
+	authResponse, err := newAuthResponse(r, user)
 	if err != nil {
-		log.ErrorCtx(r.Context(), "failed to generate refresh token", "error", err)
+		log.ErrorCtx(r.Context(), "failed to generate tokens", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Erro ao gerar token")
 		return
 	}
 
 	log.InfoCtx(r.Context(), "user registered", "id", user.ID, "email", user.Email, "role", user.Role)
 
-	authResponse := AuthResponse{
-		User:         user,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    auth.GetAccessTokenDurationSeconds(),
-		Token:        accessToken, // Compatibilidade
-	}
 	response.JSON(w, http.StatusCreated, authResponse)
 }
 
@@ -157,39 +144,98 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Gerar access token JWT (incluindo role)
-	accessToken, err := auth.GenerateToken(user.ID, user.Email, user.Role)
+	// Atualizar hash legado (bcrypt) ou com custo desatualizado de forma transparente
+	if auth.NeedsRehash(user.Password) {
+		rehashPassword(r, &user, req.Password)
+	}
+
+	// Verificar se o dispositivo é novo antes de registrar a nova sessão
+	knownDevice, err := auth.IsKnownDevice(user.ID, getDeviceID(r), getDeviceFingerprint(r))
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to check known device", "user_id", user.ID, "error", err)
+		knownDevice = true // Na dúvida, não notificar
+	}
+
+	// Gerar access token e refresh token
+	authResponse, err := newAuthResponse(r, user)
 	if err != nil {
-		log.ErrorCtx(r.Context(), "failed to generate access token", "error", err)
+		log.ErrorCtx(r.Context(), "failed to generate tokens", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Erro ao gerar token")
 		return
 	}
 
+	if !knownDevice {
+		notifyNewDeviceLogin(r, &user)
+	}
+
+	log.InfoCtx(r.Context(), "user logged in", "id", user.ID, "email", user.Email, "role", user.Role)
+
+	response.JSON(w, http.StatusOK, authResponse)
+}
+
+// newAuthResponse gera access token e refresh token para o dispositivo da requisição
+func newAuthResponse(r *http.Request, user models.User) (*AuthResponse, error) {
+	// Gerar access token JWT (incluindo role)
+	accessToken, err := auth.GenerateToken(user.ID, user.Email, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
 	// Gerar refresh token
 	refreshToken, err := auth.CreateRefreshToken(auth.RefreshTokenInfo{
 		UserID:            user.ID,
 		Email:             user.Email,
 		Role:              user.Role,
+		DeviceID:          getDeviceID(r),
 		DeviceName:        getDeviceName(r),
 		DeviceFingerprint: getDeviceFingerprint(r),
 		IPAddress:         getClientIP(r),
 	})
 	if err != nil {
-		log.ErrorCtx(r.Context(), "failed to generate refresh token", "error", err)
-		response.Error(w, http.StatusInternalServerError, "Erro ao gerar token")
-		return
+		return nil, err
 	}
 
-	log.InfoCtx(r.Context(), "user logged in", "id", user.ID, "email", user.Email, "role", user.Role)
-
-	authResponse := AuthResponse{
+	return &AuthResponse{
 		User:         user,
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		ExpiresIn:    auth.GetAccessTokenDurationSeconds(),
 		Token:        accessToken, // Compatibilidade
+	}, nil
+}
+
+// notifyNewDeviceLogin avisa o usuário por e-mail sobre um login em dispositivo desconhecido
+// Falhas são apenas logadas: o login não deve falhar por causa da notificação
+func notifyNewDeviceLogin(r *http.Request, user *models.User) {
+	err := mailer.Send(r.Context(), mailer.Message{
+		To:      user.Email,
+		Subject: "Novo acesso à sua conta",
+		Body: fmt.Sprintf("Olá, %s!\n\nDetectamos um login na sua conta em um novo dispositivo:\n\nDispositivo: %s\nIP: %s\nData: %s\n\nSe foi você, nenhuma ação é necessária. Caso contrário, troque sua senha e desconecte o dispositivo na lista de dispositivos conectados.",
+			user.Name, getDeviceName(r), getClientIP(r), time.Now().Format("02/01/2006 15:04")),
+	})
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to send new device notification", "user_id", user.ID, "error", err)
+		return
 	}
-	response.JSON(w, http.StatusOK, authResponse)
+
+	log.InfoCtx(r.Context(), "new device login notified", "user_id", user.ID)
+}
+
+// rehashPassword regera o hash da senha com o algoritmo e custo atuais
+// Falhas são apenas logadas: o login não deve falhar por causa do upgrade
+func rehashPassword(r *http.Request, user *models.User, password string) {
+	newHash, err := auth.HashPassword(password)
+	if err != nil {
+		log.ErrorCtx(r.Context(), "failed to rehash password", "user_id", user.ID, "error", err)
+		return
+	}
+
+	if err := database.DB.Model(user).UpdateColumn("password", newHash).Error; err != nil {
+		log.ErrorCtx(r.Context(), "failed to save rehashed password", "user_id", user.ID, "error", err)
+		return
+	}
+
+	log.InfoCtx(r.Context(), "password hash upgraded", "user_id", user.ID)
 }
 
 // LogoutRequest representa a requisição de logout (opcional)
@@ -2,10 +2,12 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
 	"github.com/davidsonmarra/receitas-app/pkg/response"
 )
 
@@ -13,11 +15,20 @@ import (
 type contextKey string
 
 const (
-	UserIDKey    contextKey = "user_id"
-	UserEmailKey contextKey = "user_email"
+	UserIDKey     contextKey = "user_id"
+	UserEmailKey  contextKey = "user_email"
+	AuthMethodKey contextKey = "auth_method"
+	APIKeyIDKey   contextKey = "api_key_id"
 )
 
-// RequireAuth é um middleware que valida o token JWT
+// Métodos de autenticação
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+// RequireAuth é um middleware que valida o token JWT ou uma chave de API
+// Chaves de API (prefixo "rk_") só acessam rotas cobertas pelos seus escopos
 func RequireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Extrair token do header Authorization
@@ -36,6 +47,12 @@ func RequireAuth(next http.Handler) http.Handler {
 			return
 		}
 
+		// Chaves de API seguem um fluxo próprio (escopos em vez de claims)
+		if auth.IsAPIKey(tokenString) {
+			authenticateAPIKey(w, r, next, tokenString)
+			return
+		}
+
 		// Verificar se o token está na blacklist
 		if auth.IsBlacklisted(tokenString) {
 			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
@@ -68,12 +85,101 @@ func RequireAuth(next http.Handler) http.Handler {
 		// Adicionar informações do usuário ao contexto
 		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 		ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
+		ctx = context.WithValue(ctx, AuthMethodKey, AuthMethodJWT)
 
 		// Continuar para o próximo handler
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// OptionalAuth identifica o usuário quando há credenciais válidas, sem exigi-las
+// Usado em rotas públicas cujo conteúdo varia para o dono (ex: rascunhos de receitas).
+// Credenciais ausentes, inválidas ou expiradas seguem como requisição anônima.
+func OptionalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if tokenString == "" || tokenString == r.Header.Get("Authorization") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		if auth.IsAPIKey(tokenString) {
+			apiKey, err := auth.ValidateAPIKey(tokenString)
+			if err == nil && apiKey.HasScope(RequiredScope(r)) {
+				ctx = context.WithValue(ctx, UserIDKey, apiKey.UserID)
+				ctx = context.WithValue(ctx, UserEmailKey, apiKey.User.Email)
+				ctx = context.WithValue(ctx, AuthMethodKey, AuthMethodAPIKey)
+				ctx = context.WithValue(ctx, APIKeyIDKey, apiKey.ID)
+			}
+		} else if !auth.IsBlacklisted(tokenString) {
+			claims, err := auth.ValidateToken(tokenString)
+			if err == nil && claims.TokenType == auth.TokenTypeAccess {
+				ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
+				ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
+				ctx = context.WithValue(ctx, AuthMethodKey, AuthMethodJWT)
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticateAPIKey valida uma chave de API e verifica o escopo exigido pela rota
+func authenticateAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, key string) {
+	apiKey, err := auth.ValidateAPIKey(key)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		switch err {
+		case auth.ErrAPIKeyExpired:
+			response.ErrorWithCode(w, http.StatusUnauthorized, "Chave de API expirada", "API_KEY_EXPIRED")
+		case auth.ErrAPIKeyRevoked:
+			response.ErrorWithCode(w, http.StatusUnauthorized, "Chave de API revogada", "API_KEY_REVOKED")
+		default:
+			response.ErrorWithCode(w, http.StatusUnauthorized, "Chave de API inválida", "API_KEY_INVALID")
+		}
+		return
+	}
+
+	// Fail secure: rotas sem escopo correspondente não aceitam chaves de API
+	scope := RequiredScope(r)
+	if !auth.IsValidScope(scope) || !apiKey.HasScope(scope) {
+		log.WarnCtx(r.Context(), "api key missing required scope",
+			"api_key_id", apiKey.ID,
+			"user_id", apiKey.UserID,
+			"required_scope", scope,
+			"path", r.URL.Path,
+			"method", r.Method)
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, scope))
+		response.ErrorWithCode(w, http.StatusForbidden, "Chave de API sem permissão para este recurso", "INSUFFICIENT_SCOPE")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, apiKey.UserID)
+	ctx = context.WithValue(ctx, UserEmailKey, apiKey.User.Email)
+	ctx = context.WithValue(ctx, AuthMethodKey, AuthMethodAPIKey)
+	ctx = context.WithValue(ctx, APIKeyIDKey, apiKey.ID)
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RequiredScope deriva o escopo exigido pela requisição
+// Formato: "<recurso>:<read|write>", onde recurso é o primeiro segmento do path
+// Ex: GET /recipes/1 -> recipes:read, POST /recipes/1/ratings -> recipes:write
+func RequiredScope(r *http.Request) string {
+	resource := strings.Trim(r.URL.Path, "/")
+	if i := strings.Index(resource, "/"); i >= 0 {
+		resource = resource[:i]
+	}
+
+	action := "write"
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		action = "read"
+	}
+
+	return resource + ":" + action
+}
+
 // GetUserIDFromContext extrai o ID do usuário do contexto
 func GetUserIDFromContext(ctx context.Context) (uint, bool) {
 	userID, ok := ctx.Value(UserIDKey).(uint)
@@ -85,3 +191,9 @@ func GetUserEmailFromContext(ctx context.Context) (string, bool) {
 	email, ok := ctx.Value(UserEmailKey).(string)
 	return email, ok
 }
+
+// IsAPIKeyRequest verifica se a requisição foi autenticada por chave de API
+func IsAPIKeyRequest(ctx context.Context) bool {
+	method, _ := ctx.Value(AuthMethodKey).(string)
+	return method == AuthMethodAPIKey
+}
@@ -42,6 +42,71 @@ func Setup() *chi.Mux {
 
 		// POST /users/logout - requer autenticação
 		r.With(customMiddleware.RequireAuth).Post("/logout", handlers.Logout)
+
+		// Perfil do usuário autenticado (self-service)
+		r.Route("/me", func(r chi.Router) {
+			r.Use(customMiddleware.RequireAuth)
+
+			// GET /users/me - ver perfil
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.GetMe)
+
+			// PATCH /users/me - atualizar nome e bio
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Patch("/", handlers.UpdateMe)
+
+			// DELETE /users/me - excluir conta
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/", handlers.DeleteMe)
+
+			// GET /users/me/recipes - minhas receitas, incluindo rascunhos (filtro opcional ?status=)
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes", handlers.ListMyRecipes)
+
+			// GET /users/me/recipes/analytics - visualizações, preparos e avaliações das minhas receitas por dia (?days=30)
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes/analytics", handlers.GetMyRecipeAnalytics)
+
+			// GET /users/me/cook-logs - meu histórico de "fiz esta receita"
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/cook-logs", handlers.ListMyCookLogs)
+
+			// GET /users/me/nutrition-goals - ver metas diárias de nutrição
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/nutrition-goals", handlers.GetNutritionGoal)
+
+			// PUT /users/me/nutrition-goals - definir metas (preset e/ou valores)
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Put("/nutrition-goals", handlers.SetNutritionGoal)
+
+			// DELETE /users/me/nutrition-goals - remover metas
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/nutrition-goals", handlers.DeleteNutritionGoal)
+
+			// GET /users/me/recommendations - recomendações personalizadas (?limit=20)
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recommendations", handlers.GetMyRecommendations)
+
+			// POST /users/me/password - trocar senha
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/password", handlers.ChangePassword)
+
+			// POST /users/me/email - solicitar troca de e-mail
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/email", handlers.RequestEmailChange)
+
+			// POST /users/me/email/verify - confirmar troca de e-mail
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/email/verify", handlers.VerifyEmailChange)
+
+			// POST /users/me/avatar/upload-url - gerar URL para upload direto do avatar
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/avatar/upload-url", handlers.GenerateAvatarUploadURL)
+
+			// POST /users/me/avatar/confirm - confirmar upload do avatar
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/avatar/confirm", handlers.ConfirmAvatarUpload)
+
+			// DELETE /users/me/avatar - remover avatar
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/avatar", handlers.DeleteAvatar)
+
+			// POST /users/me/data-export - solicitar exportação dos dados pessoais (LGPD)
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/data-export", handlers.RequestDataExport)
+
+			// GET /users/me/data-export/{id} - consultar status da exportação
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/data-export/{id}", handlers.GetDataExport)
+
+			// GET /users/me/data-export/{id}/download - baixar exportação (uma única vez)
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/data-export/{id}/download", handlers.DownloadDataExport)
+
+			// POST /users/me/erasure - eliminar dados pessoais (LGPD)
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/erasure", handlers.RequestDataErasure)
+		})
 	})
 
 	// Rotas de autenticação (refresh tokens)
@@ -59,24 +124,47 @@ func Setup() *chi.Mux {
 
 			// GET /auth/devices - listar dispositivos ativos
 			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/devices", handlers.ListDevices)
+
+			// PATCH /auth/devices/{id} - renomear dispositivo
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Patch("/devices/{id}", handlers.RenameDevice)
+
+			// DELETE /auth/devices/{id} - desconectar dispositivo (revogar sessão)
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/devices/{id}", handlers.RevokeDevice)
+
+			// Chaves de API (não acessíveis via chave de API: não há escopo "auth")
+			r.Route("/api-keys", func(r chi.Router) {
+				// POST /auth/api-keys - criar chave
+				r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/", handlers.CreateAPIKey)
+
+				// GET /auth/api-keys - listar chaves
+				r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListAPIKeys)
+
+				// DELETE /auth/api-keys/{id} - revogar chave
+				r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}", handlers.RevokeAPIKey)
+			})
 		})
 	})
 
 	// Rotas de receitas com rate limiting específico
 	r.Route("/recipes", func(r chi.Router) {
 		// Rotas públicas (sem autenticação)
-		// GET /recipes - rate limit de leitura
-		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListRecipes)
+		// Rascunhos e receitas privadas só aparecem para o dono e admins (identificados via OptionalAuth)
+		// Receitas não listadas exigem ?share_token= em todas as rotas de leitura
+		// GET /recipes - rate limit de leitura (autenticado, inclui a cobertura das metas de nutrição)
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListRecipes)
 
 		// GET /recipes/{id} - rate limit de leitura
-		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}", handlers.GetRecipe)
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}", handlers.GetRecipe)
+
+		// GET /recipes/{id}/forks - listar forks públicos da receita
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}/forks", handlers.ListRecipeForks)
 
 		// Rotas de imagens (públicas para leitura)
 		// GET /recipes/{id}/image/variants - obter URLs otimizadas
-		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}/image/variants", handlers.GetRecipeImageVariants)
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}/image/variants", handlers.GetRecipeImageVariants)
 
 		// GET /recipes/{id}/image/optimized - obter URL otimizada customizada
-		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}/image/optimized", handlers.GetOptimizedRecipeImage)
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}/image/optimized", handlers.GetOptimizedRecipeImage)
 
 		// Rotas protegidas (requer autenticação)
 		// POST /recipes - requer auth + rate limit de escrita
@@ -88,6 +176,30 @@ func Setup() *chi.Mux {
 		// DELETE /recipes/{id} - requer auth + rate limit de escrita
 		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}", handlers.DeleteRecipe)
 
+		// PATCH /recipes/{id}/status - publicar, agendar, despublicar ou arquivar
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Patch("/{id}/status", handlers.UpdateRecipeStatus)
+
+		// PATCH /recipes/{id}/visibility - tornar privada, não listada ou pública
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Patch("/{id}/visibility", handlers.UpdateRecipeVisibility)
+
+		// GET /recipes/{id}/share - ver link de compartilhamento (receitas não listadas)
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}/share", handlers.GetRecipeShare)
+
+		// POST /recipes/{id}/share - gerar novo link de compartilhamento (invalida o anterior)
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/share", handlers.RegenerateRecipeShareToken)
+
+		// DELETE /recipes/{id}/share - revogar link de compartilhamento
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}/share", handlers.RevokeRecipeShareToken)
+
+		// POST /recipes/{id}/fork - copiar receita para o usuário autenticado (remix)
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/fork", handlers.ForkRecipe)
+
+		// PUT /recipes/{id}/tags - definir as tags da receita (apenas tags aprovadas)
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Put("/{id}/tags", handlers.SetRecipeTags)
+
+		// POST /recipes/{id}/cook - preparar receita, retirando os ingredientes da despensa
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/cook", handlers.CookRecipe)
+
 		// POST /recipes/{id}/image/upload-url - gerar URL para upload direto
 		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/image/upload-url", handlers.GenerateUploadURL)
 
@@ -98,6 +210,18 @@ func Setup() *chi.Mux {
 		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}/image", handlers.DeleteRecipeImage)
 	})
 
+	// GET /nutrition-goals/presets - presets de metas diárias (público)
+	r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/nutrition-goals/presets", handlers.ListNutritionGoalPresets)
+
+	// Rotas de tags (taxonomia das receitas)
+	r.Route("/tags", func(r chi.Router) {
+		// GET /tags - listar tags aprovadas (filtro opcional ?type=)
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListTags)
+
+		// POST /tags/suggestions - sugerir nova tag (pendente de aprovação)
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/suggestions", handlers.SuggestTag)
+	})
+
 	// Rotas de ingredientes (públicas para leitura)
 	r.Route("/ingredients", func(r chi.Router) {
 		// GET /ingredients - listar com filtros e paginação
@@ -108,12 +232,15 @@ func Setup() *chi.Mux {
 
 		// GET /ingredients/{id} - ver ingrediente
 		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}", handlers.GetIngredient)
+
+		// GET /ingredients/{id}/substitutes - substitutos curados e por similaridade (?diet=vegan&exclude_allergens=lactose)
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}/substitutes", handlers.ListIngredientSubstitutes)
 	})
 
 	// Rotas de ingredientes nas receitas
 	r.Route("/recipes/{id}/ingredients", func(r chi.Router) {
 		// GET /recipes/{id}/ingredients - listar ingredientes da receita (público)
-		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListRecipeIngredients)
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListRecipeIngredients)
 
 		// Rotas protegidas (requer auth)
 		r.With(customMiddleware.RequireAuth).Post("/", handlers.AddRecipeIngredient)
@@ -121,17 +248,94 @@ func Setup() *chi.Mux {
 		r.With(customMiddleware.RequireAuth).Delete("/{ingredient_id}", handlers.DeleteRecipeIngredient)
 	})
 
+	// Rotas do modo de preparo estruturado (passos)
+	r.Route("/recipes/{id}/steps", func(r chi.Router) {
+		// GET /recipes/{id}/steps - listar passos da receita (público)
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListRecipeSteps)
+
+		// Rotas protegidas (requer auth)
+		r.With(customMiddleware.RequireAuth).Post("/", handlers.AddRecipeStep)
+		// PUT /recipes/{id}/steps/reorder - redefinir a ordem de todos os passos
+		r.With(customMiddleware.RequireAuth).Put("/reorder", handlers.ReorderRecipeSteps)
+		r.With(customMiddleware.RequireAuth).Put("/{step_id}", handlers.UpdateRecipeStep)
+		r.With(customMiddleware.RequireAuth).Delete("/{step_id}", handlers.DeleteRecipeStep)
+
+		// POST /recipes/{id}/steps/{step_id}/image/upload-url - gerar URL para upload direto
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{step_id}/image/upload-url", handlers.GenerateStepImageUploadURL)
+
+		// POST /recipes/{id}/steps/{step_id}/image/confirm - confirmar upload
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{step_id}/image/confirm", handlers.ConfirmStepImageUpload)
+
+		// DELETE /recipes/{id}/steps/{step_id}/image - remover imagem do passo
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{step_id}/image", handlers.DeleteStepImage)
+	})
+
+	// Rotas do histórico de revisões (dono da receita ou admin)
+	r.Route("/recipes/{id}/revisions", func(r chi.Router) {
+		r.Use(customMiddleware.RequireAuth)
+
+		// GET /recipes/{id}/revisions - listar revisões (mais recentes primeiro)
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListRecipeRevisions)
+
+		// GET /recipes/{id}/revisions/diff?from=1&to=3 - diferença campo a campo entre revisões
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/diff", handlers.DiffRecipeRevisions)
+
+		// GET /recipes/{id}/revisions/{number} - conteúdo completo de uma revisão
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{number}", handlers.GetRecipeRevision)
+
+		// POST /recipes/{id}/revisions/{number}/restore - restaurar revisão como nova revisão
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{number}/restore", handlers.RestoreRecipeRevision)
+	})
+
 	// Rota de cálculo nutricional
-	r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes/{id}/nutrition", handlers.GetRecipeNutrition)
+	r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes/{id}/nutrition", handlers.GetRecipeNutrition)
+
+	// GET /recipes/{id}/nutrition/label.svg - tabela nutricional (ANVISA) como imagem
+	r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes/{id}/nutrition/label.svg", handlers.GetRecipeNutritionLabel)
+
+	// GET /recipes/{id}/cost - custo estimado total e por porção (?region=SP)
+	r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes/{id}/cost", handlers.GetRecipeCost)
+
+	// GET /recipes/{id}/substitutions - trocas de ingredientes para atender a uma dieta ou alergia
+	r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes/{id}/substitutions", handlers.GetRecipeSubstitutions)
+
+	// GET /recipes/{id}/similar - receitas semelhantes pré-calculadas (?limit=10)
+	r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes/{id}/similar", handlers.GetSimilarRecipes)
+
+	// POST /recipes/{id}/events - registrar visualização ou preparo (deduplicado por usuário ou IP; limite de leitura por ser frequente)
+	r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Post("/recipes/{id}/events", handlers.RecordRecipeEvent)
+
+	// GET /recipes/{id}/cook-logs - galeria de "fiz esta receita" (?with_photo=true)
+	r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes/{id}/cook-logs", handlers.ListRecipeCookLogs)
+
+	// POST /recipes/{id}/cook-logs - registrar "fiz esta receita" com observações e ajustes
+	r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/recipes/{id}/cook-logs", handlers.CreateCookLog)
+
+	// Rotas de registros de "fiz esta receita" (apenas o autor do registro)
+	r.Route("/cook-logs", func(r chi.Router) {
+		r.Use(customMiddleware.RequireAuth)
+
+		// DELETE /cook-logs/{id} - remover registro e foto
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}", handlers.DeleteCookLog)
+
+		// POST /cook-logs/{id}/photo/upload-url - gerar URL para upload direto da foto
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/photo/upload-url", handlers.GenerateCookLogPhotoUploadURL)
+
+		// POST /cook-logs/{id}/photo/confirm - confirmar upload da foto
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/photo/confirm", handlers.ConfirmCookLogPhotoUpload)
+
+		// DELETE /cook-logs/{id}/photo - remover foto
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}/photo", handlers.DeleteCookLogPhoto)
+	})
 
 	// Rotas de avaliações de receitas
 	r.Route("/recipes/{id}/ratings", func(r chi.Router) {
 		// Rotas públicas (sem autenticação)
 		// GET /recipes/{id}/ratings - listar avaliações da receita
-		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListRecipeRatings)
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListRecipeRatings)
 
 		// GET /recipes/{id}/ratings/stats - obter estatísticas de avaliações
-		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/stats", handlers.GetRatingStats)
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/stats", handlers.GetRatingStats)
 
 		// Rotas protegidas (requer autenticação)
 		// GET /recipes/{id}/ratings/me - obter minha avaliação
@@ -153,6 +357,101 @@ func Setup() *chi.Mux {
 	r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitRead(rateLimitConfig)).
 		Get("/analyze-food/{job_id}", handlers.GetAnalysisResult)
 
+	// Rotas do planejamento de refeições (dono e membros da casa)
+	r.Route("/meal-plans", func(r chi.Router) {
+		r.Use(customMiddleware.RequireAuth)
+
+		// GET /meal-plans - listar planos próprios e compartilhados
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListMealPlans)
+
+		// POST /meal-plans - criar plano
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/", handlers.CreateMealPlan)
+
+		// GET /meal-plans/{id} - ver plano com refeições (?from= e ?to= opcionais)
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}", handlers.GetMealPlan)
+
+		// PUT /meal-plans/{id} - renomear plano (apenas o dono)
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Put("/{id}", handlers.UpdateMealPlan)
+
+		// DELETE /meal-plans/{id} - remover plano (apenas o dono)
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}", handlers.DeleteMealPlan)
+
+		// POST /meal-plans/{id}/slots - planejar receita em uma refeição
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/slots", handlers.AddMealPlanSlot)
+
+		// PUT /meal-plans/{id}/slots/{slot_id} - alterar refeição planejada
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Put("/{id}/slots/{slot_id}", handlers.UpdateMealPlanSlot)
+
+		// DELETE /meal-plans/{id}/slots/{slot_id} - remover refeição planejada
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}/slots/{slot_id}", handlers.DeleteMealPlanSlot)
+
+		// POST /meal-plans/{id}/copy-week - copiar as refeições de uma semana para outra
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/copy-week", handlers.CopyMealPlanWeek)
+
+		// GET /meal-plans/{id}/nutrition - totais por dia e da semana (?week=YYYY-MM-DD)
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}/nutrition", handlers.GetMealPlanNutrition)
+
+		// POST /meal-plans/{id}/members - compartilhar com membro da casa (apenas o dono)
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/members", handlers.AddMealPlanMember)
+
+		// DELETE /meal-plans/{id}/members/{user_id} - remover membro (dono) ou sair do plano (membro)
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}/members/{user_id}", handlers.RemoveMealPlanMember)
+	})
+
+	// Rotas de listas de compras
+	// Quem tem o link de compartilhamento (?share_token=) pode ver a lista e marcar itens sem autenticação
+	r.Route("/shopping-lists", func(r chi.Router) {
+		// GET /shopping-lists - listar listas do usuário
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListShoppingLists)
+
+		// POST /shopping-lists - gerar lista a partir de receitas ou de um planejamento
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/", handlers.CreateShoppingList)
+
+		// GET /shopping-lists/{id} - ver lista por corredor (?format=text ou ?format=checklist para exportar)
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}", handlers.GetShoppingList)
+
+		// DELETE /shopping-lists/{id} - remover lista
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}", handlers.DeleteShoppingList)
+
+		// POST /shopping-lists/{id}/items - adicionar item manual
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/items", handlers.AddShoppingListItem)
+
+		// PATCH /shopping-lists/{id}/items/{item_id} - marcar ou desmarcar item
+		r.With(customMiddleware.OptionalAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Patch("/{id}/items/{item_id}", handlers.CheckShoppingListItem)
+
+		// DELETE /shopping-lists/{id}/items/{item_id} - remover item
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}/items/{item_id}", handlers.DeleteShoppingListItem)
+
+		// POST /shopping-lists/{id}/share - gerar novo link de compartilhamento (invalida o anterior)
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/share", handlers.ShareShoppingList)
+
+		// DELETE /shopping-lists/{id}/share - revogar link de compartilhamento
+		r.With(customMiddleware.RequireAuth, customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}/share", handlers.RevokeShoppingListShare)
+	})
+
+	// Rotas da despensa (estoque do usuário com validade)
+	r.Route("/pantry", func(r chi.Router) {
+		r.Use(customMiddleware.RequireAuth)
+
+		// GET /pantry - listar itens, dos que vencem antes aos sem validade
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.ListPantry)
+
+		// POST /pantry - adicionar lote (ingrediente, quantidade, unidade e validade opcional)
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/", handlers.AddPantryItem)
+
+		// POST /pantry/consume - consumir quantidade de um ingrediente (lotes que vencem antes primeiro)
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/consume", handlers.ConsumePantryItem)
+
+		// GET /pantry/expiring - itens vencidos ou que vencem nos próximos dias (?days=, padrão 3)
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/expiring", handlers.ListExpiringPantry)
+
+		// GET /pantry/recipes - receitas que aproveitam itens perto do vencimento (?days=, padrão 7)
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/recipes", handlers.SuggestPantryRecipes)
+
+		// DELETE /pantry/{item_id} - remover lote
+		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{item_id}", handlers.DeletePantryItem)
+	})
+
 	// Rotas administrativas (requer admin)
 	r.Route("/admin", func(r chi.Router) {
 		// Middleware: RequireAuth + RequireAdmin (defense in depth)
@@ -183,11 +482,51 @@ func Setup() *chi.Mux {
 
 			// DELETE /admin/ingredients/{id} - deletar ingrediente
 			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}", handlers.DeleteIngredient)
+
+			// GET /admin/ingredients/{id}/prices - histórico de preços (?region=SP)
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/{id}/prices", handlers.ListIngredientPrices)
+
+			// POST /admin/ingredients/{id}/prices - cadastrar preço por região e data de vigência
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/prices", handlers.CreateIngredientPrice)
+
+			// DELETE /admin/ingredients/{id}/prices/{price_id} - remover preço
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}/prices/{price_id}", handlers.DeleteIngredientPrice)
+
+			// POST /admin/ingredients/{id}/substitutes - cadastrar substituição curada
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{id}/substitutes", handlers.CreateIngredientSubstitution)
+
+			// DELETE /admin/ingredients/{id}/substitutes/{substitution_id} - remover substituição curada
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{id}/substitutes/{substitution_id}", handlers.DeleteIngredientSubstitution)
+		})
+
+		// Rotas de tags admin (curadoria e moderação de sugestões)
+		r.Route("/tags", func(r chi.Router) {
+			// GET /admin/tags - listar tags (filtros ?status= e ?type=)
+			r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/", handlers.AdminListTags)
+
+			// POST /admin/tags - criar tag canônica
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/", handlers.AdminCreateTag)
+
+			// PUT /admin/tags/{tag_id} - renomear ou mudar tipo
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Put("/{tag_id}", handlers.AdminUpdateTag)
+
+			// POST /admin/tags/{tag_id}/approve - aprovar sugestão
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{tag_id}/approve", handlers.AdminApproveTag)
+
+			// POST /admin/tags/{tag_id}/reject - rejeitar sugestão
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Post("/{tag_id}/reject", handlers.AdminRejectTag)
+
+			// DELETE /admin/tags/{tag_id} - excluir tag (remove das receitas)
+			r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/{tag_id}", handlers.AdminDeleteTag)
 		})
 
 		// Rotas de avaliações admin (moderação)
 		// DELETE /admin/ratings/{rating_id} - deletar qualquer avaliação
 		r.With(customMiddleware.RateLimitWrite(rateLimitConfig)).Delete("/ratings/{rating_id}", handlers.AdminDeleteRating)
+
+		// Registros de eliminação de dados (LGPD)
+		// GET /admin/data-erasures - listar eliminações (filtro opcional ?email=)
+		r.With(customMiddleware.RateLimitRead(rateLimitConfig)).Get("/data-erasures", handlers.AdminListDataErasures)
 	})
 
 	return r
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKey representa uma chave de acesso pessoal para integrações
+// A chave em texto puro só é exibida na criação; o banco guarda apenas o hash
+type APIKey struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	UserID     uint           `gorm:"not null;index:idx_api_keys_user_id" json:"user_id"`
+	User       *User          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Name       string         `gorm:"not null;size:100" json:"name"`
+	Prefix     string         `gorm:"not null;size:16" json:"prefix"`        // Primeiros caracteres da chave, para identificação
+	KeyHash    string         `gorm:"uniqueIndex;not null;size:64" json:"-"` // SHA256 hash, nunca retornar
+	Scopes     string         `gorm:"not null;size:500" json:"-"`            // Escopos separados por vírgula
+	ExpiresAt  *time.Time     `gorm:"index" json:"expires_at"`               // NULL = não expira
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	RevokedAt  *time.Time     `json:"revoked_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList retorna os escopos da chave como lista
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// HasScope verifica se a chave possui o escopo informado
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired verifica se a chave está expirada
+func (k *APIKey) IsExpired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+// IsRevoked verifica se a chave foi revogada
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// IsValid verifica se a chave é válida (não expirada e não revogada)
+func (k *APIKey) IsValid() bool {
+	return !k.IsExpired() && !k.IsRevoked()
+}
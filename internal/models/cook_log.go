@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// CookLog é o registro de "fiz esta receita" de um usuário, com foto, observações e ajustes
+// Aparece na galeria pública da receita e no histórico de preparos do usuário
+type CookLog struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	RecipeID      uint      `gorm:"not null;index" json:"recipe_id"`
+	Recipe        *Recipe   `gorm:"foreignKey:RecipeID" json:"recipe,omitempty"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	User          *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Notes         string    `gorm:"type:text" json:"notes,omitempty" validate:"omitempty,max=2000"`
+	Adjustments   string    `gorm:"type:text" json:"adjustments,omitempty" validate:"omitempty,max=1000"` // Ex.: "troquei o leite por leite de coco"
+	PhotoURL      string    `gorm:"size:500" json:"photo_url,omitempty"`                                  // URL da foto no Cloudinary
+	PhotoPublicID string    `gorm:"size:200" json:"-"`                                                    // ID público da foto no Cloudinary (para deletar)
+	CookedAt      time.Time `gorm:"not null;index" json:"cooked_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (CookLog) TableName() string {
+	return "cook_logs"
+}
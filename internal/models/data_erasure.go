@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// DataErasure registra uma eliminação de dados pessoais para fins de conformidade (LGPD)
+// Não contém dados pessoais: apenas o ID interno, o hash do e-mail e o resumo do que foi removido
+type DataErasure struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	EmailHash     string    `gorm:"not null;size:64;index" json:"email_hash"` // SHA256 do e-mail original
+	PolicyVersion string    `gorm:"not null;size:20" json:"policy_version"`
+	Summary       string    `gorm:"type:text" json:"summary"` // JSON com a contagem de registros por tabela
+	RequestedAt   time.Time `gorm:"not null" json:"requested_at"`
+	CompletedAt   time.Time `gorm:"not null" json:"completed_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (DataErasure) TableName() string {
+	return "data_erasures"
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// Status de uma exportação de dados pessoais
+const (
+	DataExportStatusProcessing = "processing"
+	DataExportStatusReady      = "ready"
+	DataExportStatusFailed     = "failed"
+	DataExportStatusDownloaded = "downloaded"
+	DataExportStatusExpired    = "expired"
+)
+
+// DataExport representa uma exportação dos dados pessoais do usuário (LGPD, art. 18)
+// O arquivo é gerado de forma assíncrona e só pode ser baixado uma vez
+type DataExport struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	UserID       uint       `gorm:"not null;index:idx_data_exports_user_id" json:"user_id"`
+	User         *User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Status       string     `gorm:"not null;size:20;index" json:"status"`
+	Archive      []byte     `json:"-"` // Arquivo ZIP; removido após o download ou expiração
+	SizeBytes    int64      `json:"size_bytes"`
+	Error        string     `gorm:"size:500" json:"error,omitempty"`
+	ExpiresAt    *time.Time `gorm:"index" json:"expires_at"` // Definido quando o arquivo fica pronto
+	DownloadedAt *time.Time `json:"downloaded_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+// IsExpired verifica se o prazo para download já passou
+func (e *DataExport) IsExpired() bool {
+	return e.ExpiresAt != nil && time.Now().After(*e.ExpiresAt)
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Status de uma análise de alimentos persistida
+const (
+	FoodAnalysisStatusCompleted = "completed"
+	FoodAnalysisStatusFailed    = "failed"
+)
+
+// FoodAnalysis registra o resultado de uma análise de alimentos por imagem
+// A fila de jobs é em memória; este registro é o histórico do usuário (inclusive para exportação LGPD)
+type FoodAnalysis struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	JobID     string    `gorm:"uniqueIndex;not null;size:36" json:"job_id"`
+	UserID    uint      `gorm:"not null;index:idx_food_analyses_user_id" json:"user_id"`
+	User      *User     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Status    string    `gorm:"not null;size:20" json:"status"`
+	Result    string    `gorm:"type:text" json:"result,omitempty"` // JSON do resultado da análise
+	Error     string    `gorm:"size:500" json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (FoodAnalysis) TableName() string {
+	return "food_analyses"
+}
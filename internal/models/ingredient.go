@@ -5,20 +5,76 @@ import "time"
 // Ingredient representa um ingrediente no sistema
 // Contém informações nutricionais baseadas em 100g do alimento
 type Ingredient struct {
-	ID        uint      `gorm:"primarykey" json:"id"`
-	Name      string    `gorm:"uniqueIndex;not null;size:200" json:"name" validate:"required"`
-	Calories  float64   `gorm:"not null" json:"calories" validate:"gte=0"`
-	Protein   float64   `gorm:"default:0" json:"protein" validate:"gte=0"`
-	Carbs     float64   `gorm:"default:0" json:"carbs" validate:"gte=0"`
-	Fat       float64   `gorm:"default:0" json:"fat" validate:"gte=0"`
-	Fiber     float64   `gorm:"default:0" json:"fiber,omitempty" validate:"gte=0"`
-	Category  string    `gorm:"size:100;index" json:"category"`
-	Unit      string    `gorm:"size:50;default:'g'" json:"unit"`
-	Source    string    `gorm:"size:50" json:"source"` // "taco", "manual"
+	ID       uint    `gorm:"primarykey" json:"id"`
+	Name     string  `gorm:"uniqueIndex;not null;size:200" json:"name" validate:"required"`
+	Calories float64 `gorm:"not null" json:"calories" validate:"gte=0"`
+	Protein  float64 `gorm:"default:0" json:"protein" validate:"gte=0"`
+	Carbs    float64 `gorm:"default:0" json:"carbs" validate:"gte=0"`
+	Fat      float64 `gorm:"default:0" json:"fat" validate:"gte=0"`
+	Fiber    float64 `gorm:"default:0" json:"fiber,omitempty" validate:"gte=0"`
+	Category string  `gorm:"size:100;index" json:"category"`
+	Unit     string  `gorm:"size:50;default:'g'" json:"unit"`
+	Source   string  `gorm:"size:50" json:"source"` // "taco", "manual"
+
+	// Restrições alimentares (classificação das receitas e alertas de alérgenos)
+	ContainsGluten    bool `gorm:"not null;default:false" json:"contains_gluten"`
+	ContainsLactose   bool `gorm:"not null;default:false" json:"contains_lactose"`
+	ContainsNuts      bool `gorm:"not null;default:false" json:"contains_nuts"` // Castanhas, nozes, amendoim
+	ContainsEgg       bool `gorm:"not null;default:false" json:"contains_egg"`
+	ContainsShellfish bool `gorm:"not null;default:false" json:"contains_shellfish"` // Crustáceos e moluscos
+	ContainsSoy       bool `gorm:"not null;default:false" json:"contains_soy"`
+	ContainsMeat      bool `gorm:"not null;default:false" json:"contains_meat"` // Carnes, aves e peixes (não vegetariano)
+	AnimalOrigin      bool `gorm:"not null;default:false" json:"animal_origin"` // Qualquer produto animal (não vegano)
+
+	// Perfil estendido de nutrientes (TACO): sódio, colesterol, minerais, vitaminas...
+	Nutrients []IngredientNutrient `gorm:"foreignKey:IngredientID" json:"nutrients,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Alérgenos reconhecidos nos ingredientes
+const (
+	AllergenGluten    = "gluten"
+	AllergenLactose   = "lactose"
+	AllergenNuts      = "nuts"
+	AllergenEgg       = "egg"
+	AllergenShellfish = "shellfish"
+	AllergenSoy       = "soy"
+)
+
+// Allergens lista os alérgenos na ordem de exibição
+var Allergens = []string{AllergenGluten, AllergenLactose, AllergenNuts, AllergenEgg, AllergenShellfish, AllergenSoy}
+
+// AllergenColumns mapeia cada alérgeno para a coluna correspondente em ingredients
+var AllergenColumns = map[string]string{
+	AllergenGluten:    "contains_gluten",
+	AllergenLactose:   "contains_lactose",
+	AllergenNuts:      "contains_nuts",
+	AllergenEgg:       "contains_egg",
+	AllergenShellfish: "contains_shellfish",
+	AllergenSoy:       "contains_soy",
+}
+
+// HasAllergen indica se o ingrediente contém o alérgeno informado
+func (i *Ingredient) HasAllergen(allergen string) bool {
+	switch allergen {
+	case AllergenGluten:
+		return i.ContainsGluten
+	case AllergenLactose:
+		return i.ContainsLactose
+	case AllergenNuts:
+		return i.ContainsNuts
+	case AllergenEgg:
+		return i.ContainsEgg
+	case AllergenShellfish:
+		return i.ContainsShellfish
+	case AllergenSoy:
+		return i.ContainsSoy
+	}
+	return false
+}
+
 // TableName especifica o nome da tabela no banco de dados
 func (Ingredient) TableName() string {
 	return "ingredients"
@@ -0,0 +1,91 @@
+package models
+
+// Status de uma medição de nutriente (TACO)
+// Traço e "não medido" não são zero: não devem ser somados como se fossem
+const (
+	NutrientStatusMeasured    = "measured"     // Valor numérico analisado
+	NutrientStatusTrace       = "trace"        // "Tr": presente, mas abaixo do limite de quantificação
+	NutrientStatusNotMeasured = "not_measured" // "NA", "*" ou vazio: não analisado
+)
+
+// Nutrient descreve um nutriente do perfil estendido
+type Nutrient struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+}
+
+// Chaves dos nutrientes do perfil estendido (valores por 100g)
+const (
+	NutrientMoisture           = "moisture"
+	NutrientCholesterol        = "cholesterol"
+	NutrientSaturatedFat       = "saturated_fat"
+	NutrientMonounsaturatedFat = "monounsaturated_fat"
+	NutrientPolyunsaturatedFat = "polyunsaturated_fat"
+	NutrientTransFat           = "trans_fat"
+	NutrientTotalSugars        = "total_sugars"
+	NutrientAddedSugars        = "added_sugars"
+	NutrientAsh                = "ash"
+	NutrientCalcium            = "calcium"
+	NutrientMagnesium          = "magnesium"
+	NutrientManganese          = "manganese"
+	NutrientPhosphorus         = "phosphorus"
+	NutrientIron               = "iron"
+	NutrientSodium             = "sodium"
+	NutrientPotassium          = "potassium"
+	NutrientCopper             = "copper"
+	NutrientZinc               = "zinc"
+	NutrientRetinol            = "retinol"
+	NutrientVitaminA           = "vitamin_a"
+	NutrientThiamine           = "thiamine"
+	NutrientRiboflavin         = "riboflavin"
+	NutrientPyridoxine         = "pyridoxine"
+	NutrientNiacin             = "niacin"
+	NutrientVitaminC           = "vitamin_c"
+)
+
+// Nutrients lista os nutrientes do perfil estendido, na ordem de exibição
+// Calorias, proteínas, carboidratos, gorduras e fibras ficam nos campos de Ingredient
+// Açúcares e gorduras trans não constam da TACO: são informados pelos admins (rotulagem ANVISA)
+var Nutrients = []Nutrient{
+	{NutrientSodium, "Sódio", "mg"},
+	{NutrientCholesterol, "Colesterol", "mg"},
+	{NutrientSaturatedFat, "Gorduras saturadas", "g"},
+	{NutrientMonounsaturatedFat, "Gorduras monoinsaturadas", "g"},
+	{NutrientPolyunsaturatedFat, "Gorduras poli-insaturadas", "g"},
+	{NutrientTransFat, "Gorduras trans", "g"},
+	{NutrientTotalSugars, "Açúcares totais", "g"},
+	{NutrientAddedSugars, "Açúcares adicionados", "g"},
+	{NutrientCalcium, "Cálcio", "mg"},
+	{NutrientIron, "Ferro", "mg"},
+	{NutrientMagnesium, "Magnésio", "mg"},
+	{NutrientManganese, "Manganês", "mg"},
+	{NutrientPhosphorus, "Fósforo", "mg"},
+	{NutrientPotassium, "Potássio", "mg"},
+	{NutrientCopper, "Cobre", "mg"},
+	{NutrientZinc, "Zinco", "mg"},
+	{NutrientRetinol, "Retinol", "mcg"},
+	{NutrientVitaminA, "Vitamina A (RAE)", "mcg"},
+	{NutrientThiamine, "Tiamina (B1)", "mg"},
+	{NutrientRiboflavin, "Riboflavina (B2)", "mg"},
+	{NutrientPyridoxine, "Piridoxina (B6)", "mg"},
+	{NutrientNiacin, "Niacina (B3)", "mg"},
+	{NutrientVitaminC, "Vitamina C", "mg"},
+	{NutrientMoisture, "Umidade", "%"},
+	{NutrientAsh, "Cinzas", "g"},
+}
+
+// IngredientNutrient representa o valor de um nutriente em 100g do ingrediente
+// Amount é NULL quando o status é traço ou não medido
+type IngredientNutrient struct {
+	ID           uint     `gorm:"primarykey" json:"-"`
+	IngredientID uint     `gorm:"not null;uniqueIndex:idx_ingredient_nutrient" json:"-"`
+	Nutrient     string   `gorm:"not null;size:40;uniqueIndex:idx_ingredient_nutrient" json:"nutrient"`
+	Amount       *float64 `json:"amount"`
+	Status       string   `gorm:"not null;size:20;default:measured" json:"status"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (IngredientNutrient) TableName() string {
+	return "ingredient_nutrients"
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// DefaultPriceRegion é a região de referência (nacional), usada quando não há preço na região pedida
+const DefaultPriceRegion = "BR"
+
+// IngredientPrice é o preço de um ingrediente em uma região a partir de uma data
+// O histórico é mantido: vale o preço com a data de vigência mais recente até o dia consultado
+type IngredientPrice struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	IngredientID  uint       `gorm:"not null;index:idx_ingredient_prices_lookup" json:"ingredient_id"`
+	Region        string     `gorm:"not null;size:2;index:idx_ingredient_prices_lookup" json:"region"` // UF (SP, RJ...) ou BR
+	Price         float64    `gorm:"not null" json:"price"`                                            // R$ pela quantidade informada
+	Quantity      float64    `gorm:"not null" json:"quantity"`                                         // Ex.: 1 (kg), 12 (un)
+	Unit          string     `gorm:"not null;size:50" json:"unit"`
+	UnitPrice     float64    `gorm:"not null" json:"unit_price"` // R$ por unidade base
+	BaseUnit      string     `gorm:"not null;size:50" json:"base_unit"`
+	EffectiveFrom string     `gorm:"not null;size:10;index:idx_ingredient_prices_lookup" json:"effective_from"` // YYYY-MM-DD
+	AppliedAt     *time.Time `json:"-"`                                                                         // Quando o custo das receitas foi recalculado com este preço (NULL = pendente)
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (IngredientPrice) TableName() string {
+	return "ingredient_prices"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IngredientSubstitution é uma substituição curada pelos admins: ingrediente original → substituto
+// Ratio é a quantidade do substituto para cada unidade do original (ex.: manteiga → óleo, 0.8)
+type IngredientSubstitution struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	IngredientID uint       `gorm:"not null;uniqueIndex:idx_ingredient_substitutions_pair" json:"ingredient_id"`
+	SubstituteID uint       `gorm:"not null;uniqueIndex:idx_ingredient_substitutions_pair;index" json:"substitute_id"`
+	Substitute   Ingredient `gorm:"foreignKey:SubstituteID" json:"substitute"`
+	Ratio        float64    `gorm:"not null;default:1" json:"ratio"`
+	Notes        string     `gorm:"size:500" json:"notes,omitempty"` // Ex.: "Em bolos; a massa fica mais úmida"
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (IngredientSubstitution) TableName() string {
+	return "ingredient_substitutions"
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Refeições de um dia no planejamento
+const (
+	MealTypeBreakfast = "breakfast" // Café da manhã
+	MealTypeLunch     = "lunch"     // Almoço
+	MealTypeDinner    = "dinner"    // Jantar
+	MealTypeSnack     = "snack"     // Lanche
+)
+
+// MealTypes lista as refeições na ordem do dia
+var MealTypes = []string{MealTypeBreakfast, MealTypeLunch, MealTypeDinner, MealTypeSnack}
+
+// DateLayout é o formato das datas sem horário nem fuso (ex.: dias dos slots)
+const DateLayout = "2006-01-02"
+
+// MealPlan representa um planejamento de refeições
+// O dono gerencia o plano e os membros; membros da casa veem e editam as refeições
+type MealPlan struct {
+	ID        uint             `gorm:"primarykey" json:"id"`
+	UserID    uint             `gorm:"not null;index" json:"user_id"`
+	Name      string           `gorm:"not null;size:100" json:"name"`
+	Members   []MealPlanMember `gorm:"foreignKey:MealPlanID" json:"members,omitempty"`
+	Slots     []MealPlanSlot   `gorm:"foreignKey:MealPlanID" json:"slots,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (MealPlan) TableName() string {
+	return "meal_plans"
+}
+
+// MealPlanSlot é uma receita planejada para uma refeição de um dia
+type MealPlanSlot struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	MealPlanID uint      `gorm:"not null;index:idx_meal_plan_slots_plan_date" json:"meal_plan_id"`
+	Date       string    `gorm:"not null;size:10;index:idx_meal_plan_slots_plan_date" json:"date"` // YYYY-MM-DD
+	MealType   string    `gorm:"not null;size:20" json:"meal_type"`
+	RecipeID   uint      `gorm:"not null;index" json:"recipe_id"`
+	Recipe     *Recipe   `gorm:"foreignKey:RecipeID" json:"recipe,omitempty"`
+	Servings   int       `gorm:"not null;default:1" json:"servings"` // Porções da receita nesta refeição
+	Notes      string    `gorm:"size:500" json:"notes,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (MealPlanSlot) TableName() string {
+	return "meal_plan_slots"
+}
+
+// MealPlanMember dá a outro usuário (membro da casa) acesso ao plano
+type MealPlanMember struct {
+	ID         uint      `gorm:"primarykey" json:"-"`
+	MealPlanID uint      `gorm:"not null;uniqueIndex:idx_meal_plan_member" json:"-"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_meal_plan_member;index" json:"user_id"`
+	User       *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (MealPlanMember) TableName() string {
+	return "meal_plan_members"
+}
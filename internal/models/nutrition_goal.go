@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// NutritionGoal são as metas diárias de nutrição do usuário
+// Metas com valor zero são consideradas não definidas
+type NutritionGoal struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	UserID    uint      `gorm:"not null;uniqueIndex" json:"-"`
+	Preset    string    `gorm:"size:30" json:"preset,omitempty"` // Preset escolhido; vazio quando as metas foram personalizadas
+	Calories  float64   `gorm:"not null;default:0" json:"calories"`
+	Protein   float64   `gorm:"not null;default:0" json:"protein"`
+	Carbs     float64   `gorm:"not null;default:0" json:"carbs"`
+	Fat       float64   `gorm:"not null;default:0" json:"fat"`
+	Fiber     float64   `gorm:"not null;default:0" json:"fiber"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (NutritionGoal) TableName() string {
+	return "nutrition_goals"
+}
+
+// GoalCoverage é o percentual da meta diária coberto por uma porção
+// Campos nulos correspondem a metas não definidas
+type GoalCoverage struct {
+	Calories *int `json:"calories,omitempty"`
+	Protein  *int `json:"protein,omitempty"`
+	Carbs    *int `json:"carbs,omitempty"`
+	Fat      *int `json:"fat,omitempty"`
+	Fiber    *int `json:"fiber,omitempty"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// PantryItem representa um lote de ingrediente na despensa do usuário
+// O mesmo ingrediente pode ter vários lotes (validades diferentes); o consumo usa primeiro o que vence antes
+type PantryItem struct {
+	ID           uint        `gorm:"primarykey" json:"id"`
+	UserID       uint        `gorm:"not null;index" json:"-"`
+	IngredientID uint        `gorm:"not null;index" json:"ingredient_id"`
+	Ingredient   *Ingredient `gorm:"foreignKey:IngredientID" json:"ingredient,omitempty"`
+	Quantity     float64     `gorm:"not null" json:"quantity"`
+	Unit         string      `gorm:"not null;size:50" json:"unit"`              // Unidade base (g, ml, un) ou a unidade informada
+	ExpiresOn    *string     `gorm:"size:10;index" json:"expires_on,omitempty"` // YYYY-MM-DD
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (PantryItem) TableName() string {
+	return "pantry_items"
+}
@@ -6,28 +6,85 @@ import (
 	"gorm.io/gorm"
 )
 
+// Status de publicação de uma receita
+const (
+	RecipeStatusDraft     = "draft"     // Visível apenas para o dono e admins
+	RecipeStatusPublished = "published" // Visível para todos
+	RecipeStatusArchived  = "archived"  // Retirada de circulação, visível apenas para o dono e admins
+)
+
+// Visibilidade de uma receita publicada
+const (
+	RecipeVisibilityPrivate  = "private"  // Apenas o dono e admins
+	RecipeVisibilityUnlisted = "unlisted" // Qualquer pessoa com o link de compartilhamento, fora das listagens
+	RecipeVisibilityPublic   = "public"   // Todos, incluindo listagens
+)
+
 // Recipe representa uma receita no sistema
 type Recipe struct {
-	ID            uint               `gorm:"primarykey" json:"id"`
-	Title         string             `gorm:"not null;size:200" json:"title" validate:"required,min=3,max=200"`
-	Description   string             `gorm:"type:text" json:"description"`
-	Instructions  string             `gorm:"type:text" json:"instructions,omitempty" validate:"omitempty,min=10,max=10000"` // Modo de preparo em Markdown
-	PrepTime      int                `gorm:"not null" json:"prep_time" validate:"required,min=1"`                           // minutos
-	Servings      int                `gorm:"not null;default:1" json:"servings" validate:"required,min=1"`
-	Difficulty    string             `gorm:"size:50" json:"difficulty" validate:"omitempty,oneof=fácil média difícil"`
-	ImageURL      string             `gorm:"size:500" json:"image_url,omitempty"`       // URL da imagem no Cloudinary
-	ImagePublicID string             `gorm:"size:200" json:"image_public_id,omitempty"` // ID público da imagem no Cloudinary (para deletar)
-	UserID        *uint              `gorm:"index" json:"user_id,omitempty"`            // NULL = receita geral, NOT NULL = receita do usuário
-	User          *User              `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Ingredients   []RecipeIngredient `gorm:"foreignKey:RecipeID" json:"ingredients,omitempty"`
-	AverageRating float64            `gorm:"-" json:"average_rating,omitempty"` // Calculado, não salvo no DB
-	RatingCount   int64              `gorm:"-" json:"rating_count,omitempty"`   // Calculado, não salvo no DB
-	CreatedAt     time.Time          `gorm:"index" json:"created_at"`           // Índice para ordenação rápida
-	UpdatedAt     time.Time          `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt     `gorm:"index" json:"-"`
+	ID                 uint                `gorm:"primarykey" json:"id"`
+	Title              string              `gorm:"not null;size:200" json:"title" validate:"required,min=3,max=200"`
+	Description        string              `gorm:"type:text" json:"description"`
+	Instructions       string              `gorm:"type:text" json:"instructions,omitempty" validate:"omitempty,min=10,max=10000"` // Modo de preparo em Markdown
+	InstructionsHTML   string              `gorm:"-" json:"instructions_html,omitempty"`                                          // HTML sanitizado, apenas com ?render=html
+	PrepTime           int                 `gorm:"not null" json:"prep_time" validate:"required,min=1"`                           // minutos
+	Servings           int                 `gorm:"not null;default:1" json:"servings" validate:"required,min=1"`
+	Difficulty         string              `gorm:"size:50" json:"difficulty" validate:"omitempty,oneof=fácil média difícil"`
+	ImageURL           string              `gorm:"size:500" json:"image_url,omitempty"`       // URL da imagem no Cloudinary
+	ImagePublicID      string              `gorm:"size:200" json:"image_public_id,omitempty"` // ID público da imagem no Cloudinary (para deletar)
+	UserID             *uint               `gorm:"index" json:"user_id,omitempty"`            // NULL = receita geral, NOT NULL = receita do usuário
+	User               *User               `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Ingredients        []RecipeIngredient  `gorm:"foreignKey:RecipeID" json:"ingredients,omitempty"`
+	Steps              []RecipeStep        `gorm:"foreignKey:RecipeID" json:"steps,omitempty"`  // Modo de preparo estruturado
+	Tags               []Tag               `gorm:"many2many:recipe_tags" json:"tags,omitempty"` // Apenas tags aprovadas
+	Status             string              `gorm:"size:20;not null;default:published;index" json:"status"`
+	PublishAt          *time.Time          `gorm:"index" json:"publish_at,omitempty"` // Publicação agendada (apenas rascunhos)
+	PublishedAt        *time.Time          `json:"published_at,omitempty"`
+	Visibility         string              `gorm:"size:20;not null;default:public;index" json:"visibility" validate:"omitempty,oneof=private unlisted public"`
+	ShareToken         *string             `gorm:"size:64;uniqueIndex" json:"-"`                         // Token do link de compartilhamento (apenas unlisted)
+	ForkedFromID       *uint               `gorm:"index" json:"forked_from_id,omitempty"`                // Receita copiada (fork); mantido mesmo se a original for excluída
+	ForkedFromTitle    string              `gorm:"size:200" json:"-"`                                    // Título da original no momento da cópia (atribuição)
+	ForkedFromUserID   *uint               `json:"-"`                                                    // Autor da original no momento da cópia (atribuição)
+	IsVegan            bool                `gorm:"not null;default:false;index" json:"is_vegan"`         // Derivado dos ingredientes: sem produtos de origem animal
+	IsVegetarian       bool                `gorm:"not null;default:false;index" json:"is_vegetarian"`    // Derivado: sem carnes, aves, peixes ou frutos do mar
+	IsGlutenFree       bool                `gorm:"not null;default:false;index" json:"is_gluten_free"`   // Derivado: sem ingredientes com glúten
+	IsLactoseFree      bool                `gorm:"not null;default:false;index" json:"is_lactose_free"`  // Derivado: sem ingredientes com lactose
+	CaloriesPerServing float64             `gorm:"not null;default:0;index" json:"calories_per_serving"` // Pré-calculado dos ingredientes (kcal por porção)
+	ProteinPerServing  float64             `gorm:"not null;default:0;index" json:"protein_per_serving"`  // Pré-calculado (g por porção)
+	CarbsPerServing    float64             `gorm:"not null;default:0;index" json:"carbs_per_serving"`    // Pré-calculado (g por porção)
+	FatPerServing      float64             `gorm:"not null;default:0;index" json:"fat_per_serving"`      // Pré-calculado (g por porção)
+	FiberPerServing    float64             `gorm:"not null;default:0;index" json:"fiber_per_serving"`    // Pré-calculado (g por porção)
+	CostPerServing     *float64            `gorm:"index" json:"cost_per_serving,omitempty"`              // Pré-calculado (R$ por porção, região BR); nulo se falta preço
+	TrendingScore      float64             `gorm:"not null;default:0;index" json:"-"`                    // Pré-calculado: engajamento recente com decaimento no tempo (sort_by=trending)
+	GoalCoverage       *GoalCoverage       `gorm:"-" json:"goal_coverage,omitempty"`                     // Calculado: % da meta diária do usuário por porção
+	Allergens          []string            `gorm:"-" json:"allergens,omitempty"`                         // Calculado: alérgenos presentes nos ingredientes
+	ForkChain          []RecipeAttribution `gorm:"-" json:"fork_chain,omitempty"`                        // Calculado: originais, da mais próxima à mais antiga
+	ForkCount          int64               `gorm:"-" json:"fork_count,omitempty"`                        // Calculado: forks públicos
+	CookCount          int64               `gorm:"-" json:"cook_count,omitempty"`                        // Calculado: registros de "fiz esta receita"
+	AverageRating      float64             `gorm:"-" json:"average_rating,omitempty"`                    // Calculado, não salvo no DB
+	RatingCount        int64               `gorm:"-" json:"rating_count,omitempty"`                      // Calculado, não salvo no DB
+	CreatedAt          time.Time           `gorm:"index" json:"created_at"`                              // Índice para ordenação rápida
+	UpdatedAt          time.Time           `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt      `gorm:"index" json:"-"`
 }
 
 // TableName especifica o nome da tabela no banco de dados
 func (Recipe) TableName() string {
 	return "recipes"
 }
+
+// PubliclyListed restringe a consulta às receitas que podem aparecer em listagens públicas
+// (publicadas e com visibilidade pública). Uso: db.Scopes(models.PubliclyListed)
+func PubliclyListed(db *gorm.DB) *gorm.DB {
+	return db.Where("recipes.status = ? AND recipes.visibility = ?", RecipeStatusPublished, RecipeVisibilityPublic)
+}
+
+// IsPublished indica se a receita foi publicada (quem a vê depende da visibilidade)
+func (r *Recipe) IsPublished() bool {
+	return r.Status == RecipeStatusPublished
+}
+
+// IsPubliclyListed indica se a receita aparece nas listagens públicas (mesmo critério de PubliclyListed)
+func (r *Recipe) IsPubliclyListed() bool {
+	return r.IsPublished() && r.Visibility == RecipeVisibilityPublic
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Tipos de evento de engajamento de uma receita
+const (
+	RecipeEventView = "view" // Receita aberta
+	RecipeEventCook = "cook" // "Fiz esta receita"
+)
+
+// RecipeEvent registra um evento de engajamento para deduplicação: o mesmo visitante conta uma vez por janela
+// Os eventos são descartados depois que a janela passa; os totais ficam em RecipeDailyStat
+type RecipeEvent struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	RecipeID    uint      `gorm:"not null;uniqueIndex:idx_recipe_events_dedup,priority:1" json:"recipe_id"`
+	Type        string    `gorm:"size:10;not null;uniqueIndex:idx_recipe_events_dedup,priority:2" json:"type"`
+	Actor       string    `gorm:"size:64;not null;uniqueIndex:idx_recipe_events_dedup,priority:3" json:"-"`          // SHA-256 do usuário ou do IP (nunca o IP em claro)
+	WindowStart time.Time `gorm:"not null;uniqueIndex:idx_recipe_events_dedup,priority:4;index" json:"window_start"` // Início da janela de deduplicação
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (RecipeEvent) TableName() string {
+	return "recipe_events"
+}
+
+// RecipeDailyStat é o total diário (UTC) de visualizações e preparos deduplicados de uma receita
+type RecipeDailyStat struct {
+	RecipeID uint   `gorm:"primaryKey;autoIncrement:false" json:"recipe_id"`
+	Day      string `gorm:"primaryKey;size:10" json:"day"` // YYYY-MM-DD
+	Views    int    `gorm:"not null;default:0" json:"views"`
+	Cooks    int    `gorm:"not null;default:0" json:"cooks"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (RecipeDailyStat) TableName() string {
+	return "recipe_daily_stats"
+}
@@ -0,0 +1,11 @@
+package models
+
+// RecipeAttribution identifica a receita original de um fork (cadeia de atribuição)
+// Título e autor vêm do momento da cópia, preservando a atribuição mesmo que a original seja excluída
+type RecipeAttribution struct {
+	RecipeID   uint   `json:"recipe_id"`
+	Title      string `json:"title,omitempty"`
+	AuthorID   *uint  `json:"author_id,omitempty"`
+	AuthorName string `json:"author_name,omitempty"`
+	Available  bool   `json:"available"` // A original ainda existe e é visível para quem consulta
+}
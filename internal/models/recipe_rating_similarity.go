@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RecipeRatingSimilarity é a similaridade item-item entre duas receitas calculada a partir das avaliações
+// (filtragem colaborativa: receitas avaliadas de forma parecida pelos mesmos usuários)
+// A tabela é reconstruída offline (cmd/build-recommendations ou job agendado)
+type RecipeRatingSimilarity struct {
+	RecipeID     uint      `gorm:"primaryKey;autoIncrement:false" json:"recipe_id"`
+	SimilarID    uint      `gorm:"primaryKey;autoIncrement:false;index" json:"similar_id"`
+	Score        float64   `gorm:"not null" json:"score"`         // Cosseno ajustado com redução para poucos avaliadores (0 a 1)
+	CommonRaters int       `gorm:"not null" json:"common_raters"` // Usuários que avaliaram as duas receitas
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (RecipeRatingSimilarity) TableName() string {
+	return "recipe_rating_similarities"
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Ações que geram uma revisão de receita
+const (
+	RecipeRevisionActionOriginal = "original" // Estado anterior à primeira edição registrada
+	RecipeRevisionActionUpdate   = "update"
+	RecipeRevisionActionRestore  = "restore"
+)
+
+// RecipeRevision representa uma versão de uma receita (histórico de edições)
+// Cada edição grava o estado resultante; restaurar uma versão gera uma nova revisão
+type RecipeRevision struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	RecipeID     uint      `gorm:"not null;uniqueIndex:idx_recipe_revisions_recipe_number" json:"recipe_id"`
+	Recipe       *Recipe   `gorm:"foreignKey:RecipeID" json:"-"`
+	Number       int       `gorm:"not null;uniqueIndex:idx_recipe_revisions_recipe_number" json:"number"` // Sequencial por receita
+	AuthorID     *uint     `gorm:"index" json:"author_id,omitempty"`                                      // Quem fez a alteração (NULL = desconhecido)
+	AuthorName   string    `gorm:"-" json:"author_name,omitempty"`                                        // Calculado, não salvo no DB
+	Action       string    `gorm:"not null;size:20" json:"action"`
+	RestoredFrom *int      `json:"restored_from,omitempty"` // Número da revisão restaurada
+	Snapshot     string    `gorm:"type:text;not null" json:"-"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (RecipeRevision) TableName() string {
+	return "recipe_revisions"
+}
+
+// RecipeSnapshot representa o conteúdo versionado de uma receita
+type RecipeSnapshot struct {
+	Title        string                     `json:"title"`
+	Description  string                     `json:"description"`
+	Instructions string                     `json:"instructions"`
+	PrepTime     int                        `json:"prep_time"`
+	Servings     int                        `json:"servings"`
+	Difficulty   string                     `json:"difficulty"`
+	Ingredients  []RecipeIngredientSnapshot `json:"ingredients"`
+}
+
+// RecipeIngredientSnapshot representa um ingrediente da receita em uma revisão
+type RecipeIngredientSnapshot struct {
+	IngredientID uint    `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	Notes        string  `json:"notes,omitempty"`
+	Order        int     `json:"order"`
+}
+
+// Data decodifica o snapshot armazenado na revisão
+func (r *RecipeRevision) Data() (RecipeSnapshot, error) {
+	var snapshot RecipeSnapshot
+	err := json.Unmarshal([]byte(r.Snapshot), &snapshot)
+	return snapshot, err
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RecipeSimilarity é um vizinho pré-calculado de uma receita ("mais como esta")
+// A tabela é reconstruída pelo job de similaridade sempre que receitas mudam
+type RecipeSimilarity struct {
+	RecipeID  uint      `gorm:"primaryKey;autoIncrement:false" json:"recipe_id"`
+	SimilarID uint      `gorm:"primaryKey;autoIncrement:false;index" json:"similar_id"`
+	Score     float64   `gorm:"not null" json:"score"` // 0 a 1
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (RecipeSimilarity) TableName() string {
+	return "recipe_similarities"
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// RecipeStep representa um passo do modo de preparo estruturado
+// Permite modo de preparo passo a passo com timer, imagem e ingredientes usados em cada etapa
+type RecipeStep struct {
+	ID              uint               `gorm:"primarykey" json:"id"`
+	RecipeID        uint               `gorm:"not null;index" json:"recipe_id"`
+	Recipe          *Recipe            `gorm:"foreignKey:RecipeID" json:"-"`
+	Order           int                `gorm:"not null;default:0" json:"order"`
+	Text            string             `gorm:"type:text;not null" json:"text"`
+	DurationSeconds *int               `json:"duration_seconds,omitempty"` // Timer opcional do passo
+	ImageURL        string             `gorm:"size:500" json:"image_url,omitempty"`
+	ImagePublicID   string             `gorm:"size:200" json:"image_public_id,omitempty"`
+	Ingredients     []RecipeIngredient `gorm:"many2many:recipe_step_ingredients;constraint:OnDelete:CASCADE" json:"ingredients,omitempty"` // Ingredientes da receita usados neste passo
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (RecipeStep) TableName() string {
+	return "recipe_steps"
+}
@@ -13,6 +13,7 @@ type RefreshToken struct {
 	UserID            uint           `gorm:"not null;index:idx_refresh_tokens_user_id" json:"user_id"`
 	User              *User          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
 	TokenHash         string         `gorm:"uniqueIndex;not null;size:64" json:"-"` // SHA256 hash, nunca retornar
+	DeviceID          string         `gorm:"size:100;index:idx_refresh_tokens_device_id" json:"-"` // ID estável enviado pelo cliente (X-Device-ID)
 	DeviceName        string         `gorm:"size:255" json:"device_name"`
 	DeviceFingerprint string         `gorm:"size:255" json:"-"` // Nunca retornar por segurança
 	IPAddress         string         `gorm:"size:45" json:"ip_address"`
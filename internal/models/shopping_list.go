@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ShoppingList representa uma lista de compras gerada a partir de receitas ou de um planejamento
+type ShoppingList struct {
+	ID         uint               `gorm:"primarykey" json:"id"`
+	UserID     uint               `gorm:"not null;index" json:"user_id"`
+	Name       string             `gorm:"not null;size:100" json:"name"`
+	MealPlanID *uint              `gorm:"index" json:"meal_plan_id,omitempty"` // Planejamento de origem (informativo)
+	ShareToken *string            `gorm:"size:64;uniqueIndex" json:"-"`        // Token do link de compartilhamento
+	Items      []ShoppingListItem `gorm:"foreignKey:ShoppingListID" json:"items,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (ShoppingList) TableName() string {
+	return "shopping_lists"
+}
+
+// ShoppingListItem é uma linha da lista de compras
+// Itens gerados somam o mesmo ingrediente de várias receitas; itens manuais não têm ingrediente
+type ShoppingListItem struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	ShoppingListID uint      `gorm:"not null;index" json:"-"`
+	IngredientID   *uint     `gorm:"index" json:"ingredient_id,omitempty"`
+	Name           string    `gorm:"not null;size:150" json:"name"`
+	Category       string    `gorm:"size:100" json:"category"` // Corredor do supermercado (Ingredient.Category)
+	Quantity       *float64  `json:"quantity,omitempty"`
+	Unit           string    `gorm:"size:50" json:"unit,omitempty"`
+	Checked        bool      `gorm:"not null;default:false" json:"checked"`
+	Manual         bool      `gorm:"not null;default:false" json:"manual"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (ShoppingListItem) TableName() string {
+	return "shopping_list_items"
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Tipos de tag (facetas de classificação das receitas)
+const (
+	TagTypeCuisine   = "cuisine"   // Culinária (italiana, nordestina...)
+	TagTypeMealType  = "meal_type" // Refeição (café da manhã, almoço, jantar, sobremesa)
+	TagTypeTechnique = "technique" // Técnica (assado, grelhado, sem forno...)
+	TagTypeOccasion  = "occasion"  // Ocasião (festa junina, natal...)
+)
+
+// TagTypes lista os tipos de tag na ordem de exibição das facetas
+var TagTypes = []string{TagTypeCuisine, TagTypeMealType, TagTypeTechnique, TagTypeOccasion}
+
+// Status de moderação de uma tag
+const (
+	TagStatusApproved = "approved" // Canônica: pode ser usada nas receitas
+	TagStatusPending  = "pending"  // Sugerida por usuário, aguardando moderação
+	TagStatusRejected = "rejected"
+)
+
+// Tag representa uma tag de classificação de receitas
+// Tags canônicas são criadas por admins; sugestões de usuários aguardam aprovação
+type Tag struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	Name          string    `gorm:"not null;size:100" json:"name"`
+	Slug          string    `gorm:"not null;size:120;uniqueIndex:idx_tags_type_slug" json:"slug"`
+	Type          string    `gorm:"not null;size:30;uniqueIndex:idx_tags_type_slug" json:"type"`
+	Status        string    `gorm:"not null;size:20;default:approved;index" json:"status"`
+	SuggestedByID *uint     `gorm:"index" json:"suggested_by_id,omitempty"` // NULL = criada por admin
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName especifica o nome da tabela no banco de dados
+func (Tag) TableName() string {
+	return "tags"
+}
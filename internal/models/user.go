@@ -8,14 +8,20 @@ import (
 
 // User representa um usuário no sistema
 type User struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	Name      string         `gorm:"not null;size:100" json:"name" validate:"required,min=3,max=100"`
-	Email     string         `gorm:"uniqueIndex;not null;size:255" json:"email" validate:"required,email"`
-	Password  string         `gorm:"not null" json:"-" validate:"required,min=6"`
-	Role      string         `gorm:"default:'user';size:20" json:"role"` // 'user' ou 'admin'
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                   uint           `gorm:"primarykey" json:"id"`
+	Name                 string         `gorm:"not null;size:100" json:"name" validate:"required,min=3,max=100"`
+	Email                string         `gorm:"uniqueIndex;not null;size:255" json:"email" validate:"required,email"`
+	Password             string         `gorm:"not null" json:"-" validate:"required,min=6"`
+	Role                 string         `gorm:"default:'user';size:20" json:"role"` // 'user' ou 'admin'
+	Bio                  string         `gorm:"size:500" json:"bio,omitempty" validate:"omitempty,max=500"`
+	AvatarURL            string         `gorm:"size:500" json:"avatar_url,omitempty"` // URL do avatar no Cloudinary
+	AvatarPublicID       string         `gorm:"size:200" json:"-"`                    // ID público do avatar no Cloudinary (para deletar)
+	PendingEmail         string         `gorm:"size:255" json:"-"`                    // Novo e-mail aguardando verificação
+	EmailChangeTokenHash string         `gorm:"size:64;index" json:"-"`               // SHA256 do token de verificação
+	EmailChangeExpiresAt *time.Time     `json:"-"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName especifica o nome da tabela no banco de dados
@@ -0,0 +1,29 @@
+-- Criação da tabela de chaves de API
+-- Chaves de acesso pessoais para integrações e scripts de automação
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes VARCHAR(500) NOT NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+-- Índices para otimizar queries
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_expires_at ON api_keys(expires_at);
+CREATE INDEX IF NOT EXISTS idx_api_keys_deleted_at ON api_keys(deleted_at);
+
+-- Comentários para documentação
+COMMENT ON TABLE api_keys IS 'Chaves de acesso pessoais para integrações';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA256 hash da chave - nunca armazenar chave em texto puro';
+COMMENT ON COLUMN api_keys.prefix IS 'Primeiros caracteres da chave para identificação na listagem';
+COMMENT ON COLUMN api_keys.scopes IS 'Escopos separados por vírgula (ex: recipes:read,recipes:write)';
+COMMENT ON COLUMN api_keys.expires_at IS 'Data de expiração opcional (NULL = não expira)';
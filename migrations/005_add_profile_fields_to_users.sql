@@ -0,0 +1,14 @@
+-- Campos de perfil e troca de e-mail com verificação
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bio VARCHAR(500);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(500);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_public_id VARCHAR(200);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_change_token_hash VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_change_expires_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_email_change_token_hash ON users(email_change_token_hash);
+
+-- Comentários para documentação
+COMMENT ON COLUMN users.pending_email IS 'Novo e-mail aguardando verificação';
+COMMENT ON COLUMN users.email_change_token_hash IS 'SHA256 do token de verificação - nunca armazenar token em texto puro';
@@ -0,0 +1,52 @@
+-- Tabelas de suporte à LGPD
+-- Histórico de análises de alimentos, exportações de dados pessoais e registros de eliminação
+
+CREATE TABLE IF NOT EXISTS food_analyses (
+    id SERIAL PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL UNIQUE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    result TEXT,
+    error VARCHAR(500),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    archive BYTEA,
+    size_bytes BIGINT DEFAULT 0,
+    error VARCHAR(500),
+    expires_at TIMESTAMP,
+    downloaded_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Sem chave estrangeira: o registro deve sobreviver a qualquer remoção do usuário
+CREATE TABLE IF NOT EXISTS data_erasures (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    email_hash VARCHAR(64) NOT NULL,
+    policy_version VARCHAR(20) NOT NULL,
+    summary TEXT,
+    requested_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP NOT NULL
+);
+
+-- Índices para otimizar queries
+CREATE INDEX IF NOT EXISTS idx_food_analyses_user_id ON food_analyses(user_id);
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports(user_id);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports(status);
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports(expires_at);
+CREATE INDEX IF NOT EXISTS idx_data_erasures_user_id ON data_erasures(user_id);
+CREATE INDEX IF NOT EXISTS idx_data_erasures_email_hash ON data_erasures(email_hash);
+
+-- Comentários para documentação
+COMMENT ON TABLE food_analyses IS 'Histórico de análises de alimentos por imagem';
+COMMENT ON TABLE data_exports IS 'Exportações de dados pessoais (LGPD art. 18) - download único';
+COMMENT ON COLUMN data_exports.archive IS 'Arquivo ZIP - descartado após download ou expiração';
+COMMENT ON TABLE data_erasures IS 'Registro de conformidade das eliminações de dados pessoais - sem dados pessoais';
+COMMENT ON COLUMN data_erasures.email_hash IS 'SHA256 do e-mail original em minúsculas';
@@ -0,0 +1,9 @@
+-- ID estável do dispositivo (header X-Device-ID) nas sessões
+-- Substitui o fingerprint (User-Agent) como identificação do dispositivo; o fingerprint passa a ser apenas sinal de risco
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_id VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_device_id ON refresh_tokens(device_id);
+
+-- Comentários para documentação
+COMMENT ON COLUMN refresh_tokens.device_id IS 'ID estável gerado pelo cliente na instalação (NULL = cliente antigo, identificado pelo fingerprint)';
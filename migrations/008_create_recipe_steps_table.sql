@@ -0,0 +1,28 @@
+-- Modo de preparo estruturado: passos ordenados com timer, imagem e ingredientes usados
+-- O campo recipes.instructions é mantido; receitas existentes são convertidas com `go run cmd/migrate-steps/main.go`
+
+CREATE TABLE IF NOT EXISTS recipe_steps (
+    id BIGSERIAL PRIMARY KEY,
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    "order" INTEGER NOT NULL DEFAULT 0,
+    text TEXT NOT NULL,
+    duration_seconds INTEGER,
+    image_url VARCHAR(500),
+    image_public_id VARCHAR(200),
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_steps_recipe_id ON recipe_steps(recipe_id);
+
+-- Ingredientes da receita usados em cada passo
+CREATE TABLE IF NOT EXISTS recipe_step_ingredients (
+    recipe_step_id BIGINT NOT NULL REFERENCES recipe_steps(id) ON DELETE CASCADE,
+    recipe_ingredient_id BIGINT NOT NULL REFERENCES recipe_ingredients(id) ON DELETE CASCADE,
+    PRIMARY KEY (recipe_step_id, recipe_ingredient_id)
+);
+
+-- Comentários para documentação
+COMMENT ON TABLE recipe_steps IS 'Passos do modo de preparo (modo cozinha passo a passo)';
+COMMENT ON COLUMN recipe_steps.duration_seconds IS 'Timer opcional do passo, em segundos';
+COMMENT ON TABLE recipe_step_ingredients IS 'Ingredientes da receita (recipe_ingredients) usados em cada passo';
@@ -0,0 +1,22 @@
+-- Histórico de revisões das receitas
+-- Cada edição grava o estado resultante (campos + ingredientes) como snapshot JSON
+
+CREATE TABLE IF NOT EXISTS recipe_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    author_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    restored_from INTEGER,
+    snapshot TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_revisions_recipe_number ON recipe_revisions(recipe_id, number);
+CREATE INDEX IF NOT EXISTS idx_recipe_revisions_author_id ON recipe_revisions(author_id);
+CREATE INDEX IF NOT EXISTS idx_recipe_revisions_created_at ON recipe_revisions(created_at);
+
+-- Comentários para documentação
+COMMENT ON TABLE recipe_revisions IS 'Versões das receitas, para diff e restauração';
+COMMENT ON COLUMN recipe_revisions.action IS 'original (estado antes da primeira edição registrada), update ou restore';
+COMMENT ON COLUMN recipe_revisions.snapshot IS 'JSON com título, descrição, modo de preparo, tempo, porções, dificuldade e ingredientes';
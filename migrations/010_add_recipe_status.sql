@@ -0,0 +1,17 @@
+-- Status de publicação das receitas (rascunho, publicada, arquivada)
+-- Receitas existentes continuam publicadas; novas receitas nascem como rascunho
+
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE;
+
+-- Receitas já existentes foram publicadas na criação
+UPDATE recipes SET published_at = created_at WHERE status = 'published' AND published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_status ON recipes(status);
+CREATE INDEX IF NOT EXISTS idx_recipes_publish_at ON recipes(publish_at);
+
+-- Comentários para documentação
+COMMENT ON COLUMN recipes.status IS 'draft (apenas dono e admins), published (pública) ou archived (apenas dono e admins)';
+COMMENT ON COLUMN recipes.publish_at IS 'Publicação agendada de um rascunho, aplicada pelo job de publicação';
+COMMENT ON COLUMN recipes.published_at IS 'Data da primeira publicação';
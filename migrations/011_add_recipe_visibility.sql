@@ -0,0 +1,12 @@
+-- Visibilidade das receitas (privada, não listada com link de compartilhamento, pública)
+-- Receitas existentes continuam públicas
+
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) NOT NULL DEFAULT 'public';
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS share_token VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_recipes_visibility ON recipes(visibility);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipes_share_token ON recipes(share_token);
+
+-- Comentários para documentação
+COMMENT ON COLUMN recipes.visibility IS 'private (apenas dono e admins), unlisted (link de compartilhamento) ou public';
+COMMENT ON COLUMN recipes.share_token IS 'Token do link de compartilhamento de receitas não listadas (NULL = sem link)';
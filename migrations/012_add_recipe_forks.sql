@@ -0,0 +1,13 @@
+-- Forks de receitas ("remix") com cadeia de atribuição
+-- Sem chave estrangeira em forked_from_id: a atribuição é mantida mesmo se a original for excluída
+
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS forked_from_id BIGINT;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS forked_from_title VARCHAR(200);
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS forked_from_user_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_forked_from_id ON recipes(forked_from_id);
+
+-- Comentários para documentação
+COMMENT ON COLUMN recipes.forked_from_id IS 'Receita original da qual esta foi copiada (NULL = não é fork)';
+COMMENT ON COLUMN recipes.forked_from_title IS 'Título da original no momento da cópia (atribuição)';
+COMMENT ON COLUMN recipes.forked_from_user_id IS 'Autor da original no momento da cópia (atribuição)';
@@ -0,0 +1,39 @@
+-- Taxonomia de receitas: tags tipadas (facetas) com moderação de sugestões
+-- Tags canônicas são criadas por admins; sugestões de usuários ficam pendentes até aprovação
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(120) NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'approved',
+    suggested_by_id BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_type_slug ON tags(slug, type);
+CREATE INDEX IF NOT EXISTS idx_tags_status ON tags(status);
+CREATE INDEX IF NOT EXISTS idx_tags_suggested_by_id ON tags(suggested_by_id);
+
+-- Vínculo N:N entre receitas e tags
+CREATE TABLE IF NOT EXISTS recipe_tags (
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (recipe_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_tags_tag_id ON recipe_tags(tag_id);
+
+-- Tags canônicas de refeição
+INSERT INTO tags (name, slug, type, status, created_at, updated_at) VALUES
+    ('Café da manhã', 'cafe-da-manha', 'meal_type', 'approved', NOW(), NOW()),
+    ('Almoço', 'almoco', 'meal_type', 'approved', NOW(), NOW()),
+    ('Jantar', 'jantar', 'meal_type', 'approved', NOW(), NOW()),
+    ('Sobremesa', 'sobremesa', 'meal_type', 'approved', NOW(), NOW())
+ON CONFLICT DO NOTHING;
+
+-- Comentários para documentação
+COMMENT ON COLUMN tags.type IS 'Faceta: cuisine, meal_type, technique ou occasion';
+COMMENT ON COLUMN tags.status IS 'Moderação: approved (canônica), pending (sugestão) ou rejected';
+COMMENT ON COLUMN tags.suggested_by_id IS 'Usuário que sugeriu a tag (NULL = criada por admin)';
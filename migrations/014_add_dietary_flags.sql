@@ -0,0 +1,45 @@
+-- Restrições alimentares: flags nos ingredientes e classificação derivada nas receitas
+-- A classificação das receitas é recalculada pela API sempre que a lista de ingredientes muda
+
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS contains_gluten BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS contains_lactose BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS contains_nuts BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS contains_egg BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS contains_shellfish BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS contains_soy BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS contains_meat BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS animal_origin BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS is_vegan BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS is_vegetarian BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS is_gluten_free BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS is_lactose_free BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_is_vegan ON recipes(is_vegan);
+CREATE INDEX IF NOT EXISTS idx_recipes_is_vegetarian ON recipes(is_vegetarian);
+CREATE INDEX IF NOT EXISTS idx_recipes_is_gluten_free ON recipes(is_gluten_free);
+CREATE INDEX IF NOT EXISTS idx_recipes_is_lactose_free ON recipes(is_lactose_free);
+
+-- Classificação das receitas existentes (mesmas regras de pkg/dietary)
+-- Pode ser executado novamente depois de revisar as flags dos ingredientes
+UPDATE recipes r SET
+    is_vegan = c.vegan,
+    is_vegetarian = c.vegetarian,
+    is_gluten_free = c.gluten_free,
+    is_lactose_free = c.lactose_free
+FROM (
+    SELECT ri.recipe_id,
+        NOT BOOL_OR(i.animal_origin OR i.contains_lactose OR i.contains_egg OR i.contains_meat OR i.contains_shellfish) AS vegan,
+        NOT BOOL_OR(i.contains_meat OR i.contains_shellfish) AS vegetarian,
+        NOT BOOL_OR(i.contains_gluten) AS gluten_free,
+        NOT BOOL_OR(i.contains_lactose) AS lactose_free
+    FROM recipe_ingredients ri
+    JOIN ingredients i ON i.id = ri.ingredient_id
+    GROUP BY ri.recipe_id
+) c
+WHERE r.id = c.recipe_id;
+
+-- Comentários para documentação
+COMMENT ON COLUMN ingredients.contains_meat IS 'Carnes, aves, peixes ou frutos do mar (não vegetariano)';
+COMMENT ON COLUMN ingredients.animal_origin IS 'Qualquer produto de origem animal (não vegano)';
+COMMENT ON COLUMN recipes.is_vegan IS 'Derivado dos ingredientes; FALSE para receitas sem ingredientes';
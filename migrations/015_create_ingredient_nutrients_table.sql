@@ -0,0 +1,19 @@
+-- Perfil estendido de nutrientes dos ingredientes (TACO)
+-- Traço ("Tr") e não medido ("NA", "*") são registrados pelo status, com amount NULL, e nunca como zero
+
+CREATE TABLE IF NOT EXISTS ingredient_nutrients (
+    id BIGSERIAL PRIMARY KEY,
+    ingredient_id BIGINT NOT NULL REFERENCES ingredients(id) ON DELETE CASCADE,
+    nutrient VARCHAR(40) NOT NULL,
+    amount DOUBLE PRECISION,
+    status VARCHAR(20) NOT NULL DEFAULT 'measured',
+    CONSTRAINT chk_ingredient_nutrients_status CHECK (status IN ('measured', 'trace', 'not_measured')),
+    CONSTRAINT chk_ingredient_nutrients_amount CHECK ((status = 'measured') = (amount IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingredient_nutrient ON ingredient_nutrients(ingredient_id, nutrient);
+
+-- Comentários para documentação
+COMMENT ON COLUMN ingredient_nutrients.nutrient IS 'Chave do nutriente (sodium, cholesterol, saturated_fat, calcium, iron, vitamin_c...)';
+COMMENT ON COLUMN ingredient_nutrients.amount IS 'Quantidade em 100g do ingrediente, na unidade do nutriente; NULL para traço ou não medido';
+COMMENT ON COLUMN ingredient_nutrients.status IS 'measured, trace (Tr na TACO) ou not_measured (NA/* na TACO)';
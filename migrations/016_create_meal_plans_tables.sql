@@ -0,0 +1,43 @@
+-- Planejamento semanal de refeições, compartilhável com membros da casa
+-- Cada slot é uma receita em uma refeição (café da manhã, almoço, jantar ou lanche) de um dia
+
+CREATE TABLE IF NOT EXISTS meal_plans (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_meal_plans_user_id ON meal_plans(user_id);
+
+CREATE TABLE IF NOT EXISTS meal_plan_slots (
+    id BIGSERIAL PRIMARY KEY,
+    meal_plan_id BIGINT NOT NULL REFERENCES meal_plans(id) ON DELETE CASCADE,
+    date VARCHAR(10) NOT NULL,
+    meal_type VARCHAR(20) NOT NULL,
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    servings BIGINT NOT NULL DEFAULT 1,
+    notes VARCHAR(500),
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT chk_meal_plan_slots_meal_type CHECK (meal_type IN ('breakfast', 'lunch', 'dinner', 'snack')),
+    CONSTRAINT chk_meal_plan_slots_servings CHECK (servings >= 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_meal_plan_slots_plan_date ON meal_plan_slots(meal_plan_id, date);
+CREATE INDEX IF NOT EXISTS idx_meal_plan_slots_recipe_id ON meal_plan_slots(recipe_id);
+
+CREATE TABLE IF NOT EXISTS meal_plan_members (
+    id BIGSERIAL PRIMARY KEY,
+    meal_plan_id BIGINT NOT NULL REFERENCES meal_plans(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meal_plan_member ON meal_plan_members(meal_plan_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_meal_plan_members_user_id ON meal_plan_members(user_id);
+
+-- Comentários para documentação
+COMMENT ON COLUMN meal_plan_slots.date IS 'Dia da refeição no formato YYYY-MM-DD (sem horário nem fuso)';
+COMMENT ON COLUMN meal_plan_slots.servings IS 'Porções da receita nesta refeição; os totais nutricionais usam total da receita / porções da receita * servings';
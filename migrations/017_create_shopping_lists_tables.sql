@@ -0,0 +1,38 @@
+-- Listas de compras geradas a partir de receitas ou de um período do planejamento
+-- Ingredientes iguais são somados na unidade base (g, ml ou un) e agrupados pela categoria do ingrediente
+
+CREATE TABLE IF NOT EXISTS shopping_lists (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    meal_plan_id BIGINT REFERENCES meal_plans(id) ON DELETE SET NULL,
+    share_token VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_shopping_lists_user_id ON shopping_lists(user_id);
+CREATE INDEX IF NOT EXISTS idx_shopping_lists_meal_plan_id ON shopping_lists(meal_plan_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shopping_lists_share_token ON shopping_lists(share_token);
+
+CREATE TABLE IF NOT EXISTS shopping_list_items (
+    id BIGSERIAL PRIMARY KEY,
+    shopping_list_id BIGINT NOT NULL REFERENCES shopping_lists(id) ON DELETE CASCADE,
+    ingredient_id BIGINT REFERENCES ingredients(id) ON DELETE SET NULL,
+    name VARCHAR(150) NOT NULL,
+    category VARCHAR(100),
+    quantity DOUBLE PRECISION,
+    unit VARCHAR(50),
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    manual BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_shopping_list_items_shopping_list_id ON shopping_list_items(shopping_list_id);
+CREATE INDEX IF NOT EXISTS idx_shopping_list_items_ingredient_id ON shopping_list_items(ingredient_id);
+
+-- Comentários para documentação
+COMMENT ON COLUMN shopping_lists.share_token IS 'Token do link de compartilhamento: permite ver a lista e marcar itens sem conta';
+COMMENT ON COLUMN shopping_list_items.category IS 'Corredor do supermercado (categoria do ingrediente; outros para itens sem categoria)';
+COMMENT ON COLUMN shopping_list_items.quantity IS 'Quantidade somada, já convertida para exibição (g/kg, ml/l ou un); NULL em itens manuais sem quantidade';
@@ -0,0 +1,21 @@
+-- Despensa do usuário: lotes de ingredientes com quantidade e validade
+-- Cada compra é um lote separado; o consumo retira primeiro dos lotes que vencem antes
+
+CREATE TABLE IF NOT EXISTS pantry_items (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ingredient_id BIGINT NOT NULL REFERENCES ingredients(id) ON DELETE CASCADE,
+    quantity DOUBLE PRECISION NOT NULL,
+    unit VARCHAR(50) NOT NULL,
+    expires_on VARCHAR(10),
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pantry_items_user_id ON pantry_items(user_id);
+CREATE INDEX IF NOT EXISTS idx_pantry_items_ingredient_id ON pantry_items(ingredient_id);
+CREATE INDEX IF NOT EXISTS idx_pantry_items_expires_on ON pantry_items(expires_on);
+
+-- Comentários para documentação
+COMMENT ON COLUMN pantry_items.quantity IS 'Quantidade restante do lote, na unidade base (g, ml ou un) quando a unidade é conhecida';
+COMMENT ON COLUMN pantry_items.expires_on IS 'Data de validade (YYYY-MM-DD); NULL para itens sem validade, consumidos por último';
@@ -0,0 +1,54 @@
+-- Metas diárias de nutrição do usuário e nutrição por porção pré-calculada nas receitas
+-- Os valores por porção permitem filtrar receitas (?max_calories=, ?min_protein=...) usando índices
+
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS calories_per_serving DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS protein_per_serving DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS carbs_per_serving DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS fat_per_serving DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS fiber_per_serving DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_calories_per_serving ON recipes(calories_per_serving);
+CREATE INDEX IF NOT EXISTS idx_recipes_protein_per_serving ON recipes(protein_per_serving);
+CREATE INDEX IF NOT EXISTS idx_recipes_carbs_per_serving ON recipes(carbs_per_serving);
+CREATE INDEX IF NOT EXISTS idx_recipes_fat_per_serving ON recipes(fat_per_serving);
+CREATE INDEX IF NOT EXISTS idx_recipes_fiber_per_serving ON recipes(fiber_per_serving);
+
+-- Preencher as receitas existentes (mesmo cálculo de nutrition.Recompute: quantidades em gramas, valores por 100g)
+UPDATE recipes
+SET calories_per_serving = totals.calories / GREATEST(recipes.servings, 1),
+    protein_per_serving = totals.protein / GREATEST(recipes.servings, 1),
+    carbs_per_serving = totals.carbs / GREATEST(recipes.servings, 1),
+    fat_per_serving = totals.fat / GREATEST(recipes.servings, 1),
+    fiber_per_serving = totals.fiber / GREATEST(recipes.servings, 1)
+FROM (
+    SELECT recipe_ingredients.recipe_id,
+           SUM(recipe_ingredients.quantity / 100 * ingredients.calories) AS calories,
+           SUM(recipe_ingredients.quantity / 100 * COALESCE(ingredients.protein, 0)) AS protein,
+           SUM(recipe_ingredients.quantity / 100 * COALESCE(ingredients.carbs, 0)) AS carbs,
+           SUM(recipe_ingredients.quantity / 100 * COALESCE(ingredients.fat, 0)) AS fat,
+           SUM(recipe_ingredients.quantity / 100 * COALESCE(ingredients.fiber, 0)) AS fiber
+    FROM recipe_ingredients
+    JOIN ingredients ON ingredients.id = recipe_ingredients.ingredient_id
+    GROUP BY recipe_ingredients.recipe_id
+) AS totals
+WHERE totals.recipe_id = recipes.id;
+
+CREATE TABLE IF NOT EXISTS nutrition_goals (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    preset VARCHAR(30),
+    calories DOUBLE PRECISION NOT NULL DEFAULT 0,
+    protein DOUBLE PRECISION NOT NULL DEFAULT 0,
+    carbs DOUBLE PRECISION NOT NULL DEFAULT 0,
+    fat DOUBLE PRECISION NOT NULL DEFAULT 0,
+    fiber DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_nutrition_goals_user_id ON nutrition_goals(user_id);
+
+-- Comentários para documentação
+COMMENT ON COLUMN recipes.calories_per_serving IS 'Calorias por porção, recalculadas quando ingredientes, quantidades ou porções mudam';
+COMMENT ON COLUMN nutrition_goals.preset IS 'Preset escolhido (reference, weight_loss, low_carb, muscle_gain); NULL quando personalizado';
+COMMENT ON COLUMN nutrition_goals.calories IS 'Meta diária em kcal; 0 significa meta não definida (o mesmo vale para os demais valores, em gramas)';
@@ -0,0 +1,27 @@
+-- Tabela de preços de ingredientes por região e data de vigência (mantida pelos admins)
+-- e custo estimado por porção das receitas, usado no filtro e na ordenação por orçamento
+
+CREATE TABLE IF NOT EXISTS ingredient_prices (
+    id BIGSERIAL PRIMARY KEY,
+    ingredient_id BIGINT NOT NULL REFERENCES ingredients(id) ON DELETE CASCADE,
+    region VARCHAR(2) NOT NULL,
+    price DOUBLE PRECISION NOT NULL,
+    quantity DOUBLE PRECISION NOT NULL,
+    unit VARCHAR(50) NOT NULL,
+    unit_price DOUBLE PRECISION NOT NULL,
+    base_unit VARCHAR(50) NOT NULL,
+    effective_from VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingredient_prices_lookup ON ingredient_prices(ingredient_id, region, effective_from);
+
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS cost_per_serving DOUBLE PRECISION;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_cost_per_serving ON recipes(cost_per_serving);
+
+-- Comentários para documentação
+COMMENT ON COLUMN ingredient_prices.region IS 'UF (SP, RJ...) ou BR para a referência nacional, usada quando a região não tem preço';
+COMMENT ON COLUMN ingredient_prices.unit_price IS 'Preço por unidade base (R$/g, R$/ml ou R$/un)';
+COMMENT ON COLUMN ingredient_prices.effective_from IS 'Data de início da vigência (YYYY-MM-DD); vale o preço mais recente até o dia consultado';
+COMMENT ON COLUMN recipes.cost_per_serving IS 'Custo estimado por porção com os preços nacionais (R$); NULL quando algum ingrediente não tem preço';
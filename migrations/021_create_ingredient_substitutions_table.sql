@@ -0,0 +1,19 @@
+-- Substituições curadas de ingredientes (mantidas pelos admins)
+-- Ratio é a quantidade do substituto para cada unidade do original (ex.: manteiga → óleo, 0.8)
+
+CREATE TABLE IF NOT EXISTS ingredient_substitutions (
+    id BIGSERIAL PRIMARY KEY,
+    ingredient_id BIGINT NOT NULL REFERENCES ingredients(id) ON DELETE CASCADE,
+    substitute_id BIGINT NOT NULL REFERENCES ingredients(id) ON DELETE CASCADE,
+    ratio DOUBLE PRECISION NOT NULL DEFAULT 1,
+    notes VARCHAR(500),
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingredient_substitutions_pair ON ingredient_substitutions(ingredient_id, substitute_id);
+CREATE INDEX IF NOT EXISTS idx_ingredient_substitutions_substitute_id ON ingredient_substitutions(substitute_id);
+
+-- Comentários para documentação
+COMMENT ON COLUMN ingredient_substitutions.ratio IS 'Quantidade do substituto para cada unidade do ingrediente original';
+COMMENT ON COLUMN ingredient_substitutions.notes IS 'Observações de uso (ex.: em bolos a massa fica mais úmida)';
@@ -0,0 +1,15 @@
+-- Receitas semelhantes pré-calculadas ("mais como esta")
+-- Reconstruída pelo job de similaridade (pkg/similarity) quando receitas são criadas, alteradas ou excluídas
+
+CREATE TABLE IF NOT EXISTS recipe_similarities (
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    similar_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (recipe_id, similar_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_similarities_similar_id ON recipe_similarities(similar_id);
+
+-- Comentários para documentação
+COMMENT ON COLUMN recipe_similarities.score IS 'Similaridade de 0 a 1: ingredientes (Jaccard ponderado por IDF), tags, dificuldade e tempo de preparo';
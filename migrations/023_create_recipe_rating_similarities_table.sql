@@ -0,0 +1,17 @@
+-- Modelo de recomendação: similaridade item-item calculada a partir das avaliações
+-- Reconstruída pelo job de recomendação (pkg/recommendation) ou por cmd/build-recommendations
+
+CREATE TABLE IF NOT EXISTS recipe_rating_similarities (
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    similar_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    common_raters INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (recipe_id, similar_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_rating_similarities_similar_id ON recipe_rating_similarities(similar_id);
+
+-- Comentários para documentação
+COMMENT ON COLUMN recipe_rating_similarities.score IS 'Cosseno ajustado entre as notas das duas receitas, reduzido quando há poucos avaliadores em comum (0 a 1)';
+COMMENT ON COLUMN recipe_rating_similarities.common_raters IS 'Quantidade de usuários que avaliaram as duas receitas';
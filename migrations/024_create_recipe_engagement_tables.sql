@@ -0,0 +1,32 @@
+-- Engajamento das receitas: eventos de visualização e preparo deduplicados por janela,
+-- totais diários por receita e score de tendência usado em sort_by=trending
+
+CREATE TABLE IF NOT EXISTS recipe_events (
+    id BIGSERIAL PRIMARY KEY,
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL,
+    actor VARCHAR(64) NOT NULL,
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_events_dedup ON recipe_events(recipe_id, type, actor, window_start);
+CREATE INDEX IF NOT EXISTS idx_recipe_events_window_start ON recipe_events(window_start);
+
+CREATE TABLE IF NOT EXISTS recipe_daily_stats (
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    day VARCHAR(10) NOT NULL,
+    views INTEGER NOT NULL DEFAULT 0,
+    cooks INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (recipe_id, day)
+);
+
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS trending_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_trending_score ON recipes(trending_score);
+
+-- Comentários para documentação
+COMMENT ON COLUMN recipe_events.actor IS 'SHA-256 de user:<id> ou ip:<endereço>; o IP nunca é gravado em claro';
+COMMENT ON COLUMN recipe_events.window_start IS 'Início da janela de deduplicação (30 min para view, 24 h para cook); eventos são descartados após a janela';
+COMMENT ON COLUMN recipe_daily_stats.day IS 'Dia em UTC (YYYY-MM-DD)';
+COMMENT ON COLUMN recipes.trending_score IS 'Visualizações, preparos (x5) e avaliações (x3) dos últimos 14 dias com meia-vida de 48 h';
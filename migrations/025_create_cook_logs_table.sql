@@ -0,0 +1,23 @@
+-- Registros de "fiz esta receita": foto, observações e ajustes de quem preparou
+-- Alimentam a galeria pública da receita, o histórico do usuário e o cook_count das listagens
+
+CREATE TABLE IF NOT EXISTS cook_logs (
+    id BIGSERIAL PRIMARY KEY,
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notes TEXT,
+    adjustments TEXT,
+    photo_url VARCHAR(500),
+    photo_public_id VARCHAR(200),
+    cooked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_cook_logs_recipe_id ON cook_logs(recipe_id);
+CREATE INDEX IF NOT EXISTS idx_cook_logs_user_id ON cook_logs(user_id);
+CREATE INDEX IF NOT EXISTS idx_cook_logs_cooked_at ON cook_logs(cooked_at);
+
+-- Comentários para documentação
+COMMENT ON COLUMN cook_logs.adjustments IS 'Ajustes feitos no preparo (ex.: troca de ingredientes, tempo de forno)';
+COMMENT ON COLUMN cook_logs.photo_public_id IS 'ID público da foto no Cloudinary (pasta cook-logs), usado para remover a imagem';
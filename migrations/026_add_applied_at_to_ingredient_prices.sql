@@ -0,0 +1,12 @@
+-- Marca os preços já aplicados ao custo das receitas
+-- O job de recálculo aplica os preços nacionais em vigor com applied_at nulo, inclusive os que
+-- entraram em vigor com o servidor parado
+
+ALTER TABLE ingredient_prices ADD COLUMN IF NOT EXISTS applied_at TIMESTAMP WITH TIME ZONE;
+
+-- Preços que já estavam em vigor ao serem cadastrados foram aplicados na hora
+UPDATE ingredient_prices SET applied_at = created_at
+WHERE applied_at IS NULL AND effective_from <= TO_CHAR(created_at, 'YYYY-MM-DD');
+
+-- Comentários para documentação
+COMMENT ON COLUMN ingredient_prices.applied_at IS 'Quando o custo das receitas foi recalculado com este preço; NULL para preços agendados ainda não aplicados';
@@ -0,0 +1,209 @@
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+)
+
+// DayFormat é o formato dos dias nas tabelas de totais diários (UTC)
+const DayFormat = "2006-01-02"
+
+// Janela de deduplicação por tipo de evento: o mesmo usuário ou IP conta uma vez por janela
+var dedupWindows = map[string]time.Duration{
+	models.RecipeEventView: 30 * time.Minute,
+	models.RecipeEventCook: 24 * time.Hour,
+}
+
+// Pesos de cada sinal no score de tendência
+const (
+	viewWeight   = 1
+	cookWeight   = 5
+	ratingWeight = 3
+)
+
+// TrendingHalfLife é o tempo para um evento valer metade no score de tendência
+const TrendingHalfLife = 48 * time.Hour
+
+// TrendingDays é quantos dias de histórico entram no score de tendência
+const TrendingDays = 14
+
+// IsEventType indica se o tipo de evento é aceito
+func IsEventType(eventType string) bool {
+	_, ok := dedupWindows[eventType]
+	return ok
+}
+
+// UserActor identifica um usuário autenticado nos eventos
+func UserActor(userID uint) string {
+	return hashActor(fmt.Sprintf("user:%d", userID))
+}
+
+// IPActor identifica um visitante anônimo pelo IP; o IP nunca é gravado em claro
+func IPActor(ip string) string {
+	return hashActor("ip:" + ip)
+}
+
+func hashActor(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// Record registra um evento e soma no total diário, se o visitante ainda não contou nesta janela
+// Retorna false quando o evento é duplicado
+func Record(db *gorm.DB, recipeID uint, eventType, actor string, at time.Time) (bool, error) {
+	window, ok := dedupWindows[eventType]
+	if !ok {
+		return false, fmt.Errorf("tipo de evento inválido: %s", eventType)
+	}
+	at = at.UTC()
+
+	recorded := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		event := models.RecipeEvent{RecipeID: recipeID, Type: eventType, Actor: actor, WindowStart: at.Truncate(window)}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&event)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		stat := models.RecipeDailyStat{RecipeID: recipeID, Day: at.Format(DayFormat)}
+		column := "views"
+		if eventType == models.RecipeEventCook {
+			stat.Cooks = 1
+			column = "cooks"
+		} else {
+			stat.Views = 1
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "recipe_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{column: gorm.Expr("recipe_daily_stats." + column + " + 1")}),
+		}).Create(&stat).Error; err != nil {
+			return err
+		}
+		recorded = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar evento: %w", err)
+	}
+	return recorded, nil
+}
+
+// PurgeEvents remove os eventos cujas janelas de deduplicação já terminaram
+func PurgeEvents(db *gorm.DB, now time.Time) (int64, error) {
+	var longest time.Duration
+	for _, window := range dedupWindows {
+		if window > longest {
+			longest = window
+		}
+	}
+	result := db.Where("window_start < ?", now.UTC().Add(-longest)).Delete(&models.RecipeEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// decay é o peso de um evento ocorrido em at: 1 agora, 0.5 após TrendingHalfLife
+func decay(at, now time.Time) float64 {
+	age := now.Sub(at)
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, age.Hours()/TrendingHalfLife.Hours())
+}
+
+// TrendingScores calcula o score de tendência de cada receita com visualizações, preparos ou avaliações recentes
+// Os totais diários são considerados no meio do dia
+func TrendingScores(db *gorm.DB, now time.Time) (map[uint]float64, error) {
+	now = now.UTC()
+	since := now.AddDate(0, 0, -TrendingDays)
+
+	var stats []models.RecipeDailyStat
+	if err := db.Where("day >= ?", since.Format(DayFormat)).Find(&stats).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar totais diários: %w", err)
+	}
+
+	var ratings []models.Rating
+	if err := db.Select("recipe_id", "created_at").Where("created_at >= ?", since).Find(&ratings).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar avaliações recentes: %w", err)
+	}
+
+	scores := make(map[uint]float64)
+	for _, stat := range stats {
+		day, err := time.Parse(DayFormat, stat.Day)
+		if err != nil {
+			continue
+		}
+		scores[stat.RecipeID] += float64(stat.Views*viewWeight+stat.Cooks*cookWeight) * decay(day.Add(12*time.Hour), now)
+	}
+	for _, rating := range ratings {
+		scores[rating.RecipeID] += ratingWeight * decay(rating.CreatedAt, now)
+	}
+	return scores, nil
+}
+
+// RefreshTrending recalcula recipes.trending_score; receitas sem engajamento recente voltam a zero
+// Usa UpdateColumn para não alterar updated_at
+func RefreshTrending(db *gorm.DB, now time.Time) (int, error) {
+	scores, err := TrendingScores(db, now)
+	if err != nil {
+		return 0, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Recipe{}).Where("trending_score <> 0").UpdateColumn("trending_score", 0).Error; err != nil {
+			return err
+		}
+		for recipeID, score := range scores {
+			if err := tx.Model(&models.Recipe{}).Where("id = ?", recipeID).
+				UpdateColumn("trending_score", math.Round(score*1000)/1000).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gravar scores de tendência: %w", err)
+	}
+	return len(scores), nil
+}
+
+// StartScheduler inicia o job que descarta eventos antigos e recalcula o score de tendência
+// O primeiro cálculo acontece logo na inicialização
+func StartScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		refresh := func() {
+			now := time.Now()
+			purged, err := PurgeEvents(database.DB, now)
+			if err != nil {
+				log.Error("erro ao descartar eventos antigos", "error", err)
+			} else if purged > 0 {
+				log.Info("eventos antigos descartados", "count", purged)
+			}
+
+			count, err := RefreshTrending(database.DB, now)
+			if err != nil {
+				log.Error("erro ao recalcular receitas em alta", "error", err)
+				return
+			}
+			log.Info("receitas em alta recalculadas", "recipes", count)
+		}
+
+		refresh()
+		for range ticker.C {
+			refresh()
+		}
+	}()
+	log.Info("job de receitas em alta iniciado", "interval", interval)
+}
@@ -0,0 +1,121 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+// Engagement soma visualizações, preparos e avaliações de um período
+type Engagement struct {
+	Views         int     `json:"views"`
+	Cooks         int     `json:"cooks"`
+	Ratings       int     `json:"ratings"`
+	AverageRating float64 `json:"average_rating,omitempty"` // Média das avaliações recebidas no período
+	scoreSum      int
+}
+
+func (e *Engagement) addRating(score int) {
+	e.Ratings++
+	e.scoreSum += score
+	e.AverageRating = math.Round(float64(e.scoreSum)/float64(e.Ratings)*100) / 100
+}
+
+// DailyEngagement é o engajamento de um dia (UTC)
+type DailyEngagement struct {
+	Date string `json:"date"`
+	Engagement
+}
+
+// RecipeEngagement é o engajamento de uma receita no período
+type RecipeEngagement struct {
+	RecipeID uint   `json:"recipe_id"`
+	Title    string `json:"title"`
+	Engagement
+}
+
+// AuthorReport é o relatório de engajamento das receitas de um autor
+type AuthorReport struct {
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	Totals  Engagement         `json:"totals"`
+	Daily   []DailyEngagement  `json:"daily"`   // Todos os dias do período, inclusive os sem engajamento
+	Recipes []RecipeEngagement `json:"recipes"` // Mais vistas primeiro
+}
+
+// AuthorAnalytics monta o relatório dos últimos days dias (incluindo hoje) das receitas do usuário
+// Inclui rascunhos e receitas privadas; receitas excluídas ficam de fora
+func AuthorAnalytics(db *gorm.DB, userID uint, days int, now time.Time) (*AuthorReport, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := today.AddDate(0, 0, -(days - 1))
+
+	report := &AuthorReport{
+		From:    start.Format(DayFormat),
+		To:      today.Format(DayFormat),
+		Daily:   make([]DailyEngagement, days),
+		Recipes: []RecipeEngagement{},
+	}
+	byDay := make(map[string]*DailyEngagement, days)
+	for i := range report.Daily {
+		report.Daily[i].Date = start.AddDate(0, 0, i).Format(DayFormat)
+		byDay[report.Daily[i].Date] = &report.Daily[i]
+	}
+
+	var recipes []models.Recipe
+	if err := db.Select("id", "title").Where("user_id = ?", userID).Order("id ASC").Find(&recipes).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar receitas do autor: %w", err)
+	}
+	if len(recipes) == 0 {
+		return report, nil
+	}
+
+	ids := make([]uint, len(recipes))
+	byRecipe := make(map[uint]*RecipeEngagement, len(recipes))
+	report.Recipes = make([]RecipeEngagement, len(recipes))
+	for i, recipe := range recipes {
+		ids[i] = recipe.ID
+		report.Recipes[i] = RecipeEngagement{RecipeID: recipe.ID, Title: recipe.Title}
+		byRecipe[recipe.ID] = &report.Recipes[i]
+	}
+
+	var stats []models.RecipeDailyStat
+	if err := db.Where("recipe_id IN ? AND day >= ? AND day <= ?", ids, report.From, report.To).Find(&stats).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar totais diários: %w", err)
+	}
+	for _, stat := range stats {
+		for _, e := range []*Engagement{&report.Totals, &byDay[stat.Day].Engagement, &byRecipe[stat.RecipeID].Engagement} {
+			e.Views += stat.Views
+			e.Cooks += stat.Cooks
+		}
+	}
+
+	var ratings []models.Rating
+	if err := db.Select("recipe_id", "score", "created_at").
+		Where("recipe_id IN ? AND created_at >= ?", ids, start).
+		Find(&ratings).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar avaliações: %w", err)
+	}
+	for _, rating := range ratings {
+		day, ok := byDay[rating.CreatedAt.UTC().Format(DayFormat)]
+		if !ok {
+			continue
+		}
+		for _, e := range []*Engagement{&report.Totals, &day.Engagement, &byRecipe[rating.RecipeID].Engagement} {
+			e.addRating(rating.Score)
+		}
+	}
+
+	sort.SliceStable(report.Recipes, func(i, j int) bool {
+		if report.Recipes[i].Views != report.Recipes[j].Views {
+			return report.Recipes[i].Views > report.Recipes[j].Views
+		}
+		return report.Recipes[i].Cooks > report.Recipes[j].Cooks
+	})
+	return report, nil
+}
@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefix identifica chaves de API no header Authorization
+const APIKeyPrefix = "rk_"
+
+// Escopos disponíveis para chaves de API
+// Não há escopo de escrita de ingredientes: o catálogo só é alterado em /admin, que não aceita chaves
+const (
+	ScopeRecipesRead     = "recipes:read"
+	ScopeRecipesWrite    = "recipes:write"
+	ScopeIngredientsRead = "ingredients:read"
+)
+
+// ValidScopes lista todos os escopos aceitos na criação de chaves
+var ValidScopes = []string{
+	ScopeRecipesRead,
+	ScopeRecipesWrite,
+	ScopeIngredientsRead,
+}
+
+var (
+	// ErrAPIKeyNotFound indica que a chave não foi encontrada
+	ErrAPIKeyNotFound = errors.New("chave de API não encontrada")
+	// ErrAPIKeyExpired indica que a chave expirou
+	ErrAPIKeyExpired = errors.New("chave de API expirada")
+	// ErrAPIKeyRevoked indica que a chave foi revogada
+	ErrAPIKeyRevoked = errors.New("chave de API revogada")
+	// ErrAPIKeyInvalidScope indica que um escopo solicitado não existe
+	ErrAPIKeyInvalidScope = errors.New("escopo de chave de API inválido")
+	// ErrAPIKeyLimitReached indica que o usuário atingiu o limite de chaves ativas
+	ErrAPIKeyLimitReached = errors.New("limite de chaves de API atingido")
+)
+
+// MaxAPIKeysPerUser limita quantas chaves ativas cada usuário pode ter
+var MaxAPIKeysPerUser int
+
+// apiKeyLastUsedInterval evita escrever last_used_at em toda requisição
+const apiKeyLastUsedInterval = time.Minute
+
+func init() {
+	// Configurar limite de chaves por usuário (padrão: 10)
+	maxKeys := os.Getenv("MAX_API_KEYS_PER_USER")
+	if maxKeys == "" {
+		MaxAPIKeysPerUser = 10
+	} else {
+		max, err := strconv.Atoi(maxKeys)
+		if err != nil || max <= 0 {
+			MaxAPIKeysPerUser = 10
+		} else {
+			MaxAPIKeysPerUser = max
+		}
+	}
+}
+
+// APIKeyInfo contém informações para criar uma chave de API
+type APIKeyInfo struct {
+	UserID    uint
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// IsValidScope verifica se um escopo é suportado
+func IsValidScope(scope string) bool {
+	for _, s := range ValidScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAPIKey verifica se um token do header Authorization é uma chave de API
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// CreateAPIKey cria uma nova chave de API e retorna a chave em texto puro
+// A chave em texto puro não é persistida e só pode ser exibida uma vez
+func CreateAPIKey(info APIKeyInfo) (string, *models.APIKey, error) {
+	// Normalizar e validar escopos (sem duplicatas)
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, scope := range info.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !IsValidScope(scope) {
+			return "", nil, fmt.Errorf("%w: %s", ErrAPIKeyInvalidScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	// Verificar limite de chaves ativas
+	var activeCount int64
+	if err := database.DB.Model(&models.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", info.UserID, time.Now()).
+		Count(&activeCount).Error; err != nil {
+		return "", nil, fmt.Errorf("erro ao contar chaves de API: %w", err)
+	}
+	if activeCount >= int64(MaxAPIKeysPerUser) {
+		return "", nil, ErrAPIKeyLimitReached
+	}
+
+	// Gerar token aleatório
+	token, err := generateRandomToken()
+	if err != nil {
+		return "", nil, fmt.Errorf("erro ao gerar chave: %w", err)
+	}
+	fullKey := APIKeyPrefix + token
+
+	apiKey := models.APIKey{
+		UserID:    info.UserID,
+		Name:      info.Name,
+		Prefix:    fullKey[:len(APIKeyPrefix)+8],
+		KeyHash:   hashToken(fullKey),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: info.ExpiresAt,
+	}
+
+	if err := database.DB.Create(&apiKey).Error; err != nil {
+		return "", nil, fmt.Errorf("erro ao salvar chave de API: %w", err)
+	}
+
+	return fullKey, &apiKey, nil
+}
+
+// ValidateAPIKey valida uma chave de API e retorna o registro com o usuário carregado
+// Também atualiza last_used_at (no máximo uma vez por minuto)
+func ValidateAPIKey(key string) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	err := database.DB.Where("key_hash = ?", hashToken(key)).
+		Preload("User").
+		First(&apiKey).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar chave de API: %w", err)
+	}
+
+	// Usuário removido invalida a chave
+	if apiKey.User == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	if apiKey.IsRevoked() {
+		return nil, ErrAPIKeyRevoked
+	}
+
+	if apiKey.IsExpired() {
+		return nil, ErrAPIKeyExpired
+	}
+
+	// Atualizar last_used_at sem alterar updated_at
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyLastUsedInterval {
+		if err := database.DB.Model(&apiKey).UpdateColumn("last_used_at", now).Error; err == nil {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return &apiKey, nil
+}
+
+// GetUserAPIKeys retorna todas as chaves de um usuário (incluindo revogadas)
+func GetUserAPIKeys(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := database.DB.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&keys).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar chaves de API: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey revoga uma chave de API pelo ID
+func RevokeAPIKey(keyID uint) error {
+	result := database.DB.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", keyID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return fmt.Errorf("erro ao revogar chave de API: %w", result.Error)
+	}
+
+	return nil
+}
+
+// RevokeAllUserAPIKeys revoga todas as chaves de API de um usuário
+func RevokeAllUserAPIKeys(userID uint) error {
+	result := database.DB.Model(&models.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return fmt.Errorf("erro ao revogar chaves de API: %w", result.Error)
+	}
+
+	return nil
+}
@@ -1,21 +1,147 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword gera um hash bcrypt da senha
-// Usa cost 12 para balanceamento entre segurança e performance
+// Formato dos hashes (PHC string format), versionado pelo próprio prefixo:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>   (atual)
+//	$2a$12$...                                     (legado, bcrypt - apenas verificação)
+const argon2idPrefix = "$argon2id$"
+
+// Argon2Params contém os parâmetros de custo do argon2id
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// PasswordHashParams são os parâmetros usados para gerar novos hashes
+// Configuráveis via PASSWORD_ARGON2_MEMORY_KB, PASSWORD_ARGON2_ITERATIONS e PASSWORD_ARGON2_PARALLELISM
+var PasswordHashParams Argon2Params
+
+var errInvalidPasswordHash = errors.New("hash de senha em formato inválido")
+
+func init() {
+	PasswordHashParams = Argon2Params{
+		Memory:      uint32(envInt("PASSWORD_ARGON2_MEMORY_KB", 64*1024)),
+		Iterations:  uint32(envInt("PASSWORD_ARGON2_ITERATIONS", 3)),
+		Parallelism: uint8(envInt("PASSWORD_ARGON2_PARALLELISM", 2)),
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// envInt lê um inteiro positivo de uma env var, usando defaultValue se ausente ou inválido
+func envInt(name string, defaultValue int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return defaultValue
+	}
+	return parsed
+}
+
+// HashPassword gera um hash argon2id da senha com os parâmetros configurados
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 12)
-	if err != nil {
+	p := PasswordHashParams
+
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
 		return "", err
 	}
-	return string(bytes), nil
+
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
-// CheckPassword compara uma senha em texto com seu hash bcrypt
+// CheckPassword compara uma senha em texto com seu hash (argon2id ou bcrypt legado)
 func CheckPassword(hashedPassword, password string) bool {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		p, salt, key, err := decodeArgon2idHash(hashedPassword)
+		if err != nil {
+			return false
+		}
+		computed := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+		return subtle.ConstantTimeCompare(key, computed) == 1
+	}
+
+	// Hashes legados (bcrypt)
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+// NeedsRehash indica se o hash deve ser regerado com os parâmetros atuais
+// Verdadeiro para hashes bcrypt legados ou argon2id com custo diferente do configurado
+func NeedsRehash(hashedPassword string) bool {
+	if !strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return true
+	}
+
+	p, _, _, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	current := PasswordHashParams
+	return p.Memory != current.Memory ||
+		p.Iterations != current.Iterations ||
+		p.Parallelism != current.Parallelism ||
+		p.KeyLength != current.KeyLength
+}
+
+// decodeArgon2idHash extrai parâmetros, salt e chave de um hash argon2id
+func decodeArgon2idHash(encoded string) (*Argon2Params, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return nil, nil, nil, errInvalidPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, errInvalidPasswordHash
+	}
+
+	p := &Argon2Params{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return nil, nil, nil, errInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, errInvalidPasswordHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, errInvalidPasswordHash
+	}
+
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
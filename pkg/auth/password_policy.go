@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limites da política de senhas
+const (
+	PasswordMinLength = 8
+	PasswordMaxLength = 128
+)
+
+var (
+	// ErrPasswordTooShort indica que a senha é curta demais
+	ErrPasswordTooShort = errors.New("a senha deve ter no mínimo 8 caracteres")
+	// ErrPasswordTooLong indica que a senha é longa demais
+	ErrPasswordTooLong = errors.New("a senha deve ter no máximo 128 caracteres")
+	// ErrPasswordTooCommon indica que a senha está na lista de senhas comuns
+	ErrPasswordTooCommon = errors.New("a senha é muito comum, escolha outra")
+	// ErrPasswordContainsPersonalInfo indica que a senha contém dados do usuário
+	ErrPasswordContainsPersonalInfo = errors.New("a senha não pode conter seu nome ou e-mail")
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+// commonPasswords é a denylist offline carregada do arquivo embutido
+var commonPasswords = loadCommonPasswords(commonPasswordsFile)
+
+// loadCommonPasswords carrega a denylist ignorando comentários e linhas vazias
+func loadCommonPasswords(content string) map[string]bool {
+	passwords := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = true
+	}
+	return passwords
+}
+
+// IsCommonPassword verifica se a senha está na denylist de senhas comuns
+func IsCommonPassword(password string) bool {
+	return commonPasswords[strings.ToLower(strings.TrimSpace(password))]
+}
+
+// ValidatePasswordPolicy valida uma nova senha contra a política
+// Usado no cadastro e na troca de senha; name e email podem ser vazios
+func ValidatePasswordPolicy(password, name, email string) error {
+	length := utf8.RuneCountInString(password)
+	if length < PasswordMinLength {
+		return ErrPasswordTooShort
+	}
+	if length > PasswordMaxLength {
+		return ErrPasswordTooLong
+	}
+
+	if IsCommonPassword(password) {
+		return ErrPasswordTooCommon
+	}
+
+	// Bloquear senhas que contenham partes do nome ou do e-mail (4+ caracteres)
+	lowered := strings.ToLower(password)
+	personal := strings.Fields(strings.ToLower(name))
+	if at := strings.Index(email, "@"); at > 0 {
+		personal = append(personal, strings.ToLower(email[:at]))
+	}
+	for _, part := range personal {
+		if utf8.RuneCountInString(part) >= 4 && strings.Contains(lowered, part) {
+			return ErrPasswordContainsPersonalInfo
+		}
+	}
+
+	return nil
+}
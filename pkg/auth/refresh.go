@@ -25,8 +25,10 @@ var (
 	ErrRefreshTokenRevoked = errors.New("refresh token revogado")
 	// ErrRefreshTokenInvalid indica que o token é inválido
 	ErrRefreshTokenInvalid = errors.New("refresh token inválido")
-	// ErrDeviceFingerprintMismatch indica que o device fingerprint não corresponde
-	ErrDeviceFingerprintMismatch = errors.New("device fingerprint não corresponde")
+	// ErrDeviceMismatch indica que o token foi apresentado por outro dispositivo (device ID diferente)
+	ErrDeviceMismatch = errors.New("dispositivo não corresponde")
+	// ErrDeviceNotFound indica que a sessão não existe, não está ativa ou pertence a outro usuário
+	ErrDeviceNotFound = errors.New("dispositivo não encontrado")
 )
 
 // Configurações de refresh token
@@ -49,7 +51,7 @@ func init() {
 		}
 	}
 
-	// Configurar validação de device fingerprint (padrão: true)
+	// Configurar avaliação de device fingerprint como sinal de risco (padrão: true)
 	enableFingerprint := os.Getenv("ENABLE_DEVICE_FINGERPRINT")
 	if enableFingerprint == "" || enableFingerprint == "true" {
 		EnableDeviceFingerprint = true
@@ -63,11 +65,19 @@ type RefreshTokenInfo struct {
 	UserID            uint
 	Email             string
 	Role              string
+	DeviceID          string
 	DeviceName        string
 	DeviceFingerprint string
 	IPAddress         string
 }
 
+// DeviceContext identifica o dispositivo que apresenta um refresh token
+type DeviceContext struct {
+	DeviceID    string // ID estável gerado pelo cliente (header X-Device-ID)
+	Fingerprint string // Hash do User-Agent, usado apenas como sinal de risco
+	IPAddress   string
+}
+
 // RefreshTokenResult contém os tokens gerados após refresh
 type RefreshTokenResult struct {
 	AccessToken  string
@@ -84,6 +94,11 @@ func generateRandomToken() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
+// GenerateRandomToken gera um token aleatório seguro (exportada para uso externo)
+func GenerateRandomToken() (string, error) {
+	return generateRandomToken()
+}
+
 // hashToken gera SHA256 hash do token
 func hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
@@ -110,11 +125,30 @@ func CreateRefreshToken(info RefreshTokenInfo) (string, error) {
 	// Hash do token para armazenar no banco
 	tokenHash := hashToken(fullToken)
 
+	deviceName := info.DeviceName
+	if info.DeviceID != "" {
+		// Um dispositivo tem uma única sessão ativa; o nome escolhido pelo usuário é mantido
+		var previous models.RefreshToken
+		if err := database.DB.Unscoped().
+			Where("user_id = ? AND device_id = ?", info.UserID, info.DeviceID).
+			Order("created_at DESC").
+			First(&previous).Error; err == nil && previous.DeviceName != "" {
+			deviceName = previous.DeviceName
+		}
+
+		if err := database.DB.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND device_id = ? AND revoked_at IS NULL", info.UserID, info.DeviceID).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return "", fmt.Errorf("erro ao revogar sessão anterior do dispositivo: %w", err)
+		}
+	}
+
 	// Criar registro no banco
 	refreshToken := models.RefreshToken{
 		UserID:            info.UserID,
 		TokenHash:         tokenHash,
-		DeviceName:        info.DeviceName,
+		DeviceID:          info.DeviceID,
+		DeviceName:        deviceName,
 		DeviceFingerprint: info.DeviceFingerprint,
 		IPAddress:         info.IPAddress,
 		ExpiresAt:         time.Now().Add(RefreshTokenDuration),
@@ -125,8 +159,10 @@ func CreateRefreshToken(info RefreshTokenInfo) (string, error) {
 	}
 
 	// Limitar número de tokens por usuário
+	// Captura a conexão atual para a goroutine não depender do global após retornar
+	db := database.DB
 	go func() {
-		if err := RevokeOldTokens(info.UserID, MaxRefreshTokensPerUser); err != nil {
+		if err := revokeOldTokens(db, info.UserID, MaxRefreshTokensPerUser); err != nil {
 			log.Error("erro ao revogar tokens antigos", "user_id", info.UserID, "error", err)
 		}
 	}()
@@ -135,7 +171,8 @@ func CreateRefreshToken(info RefreshTokenInfo) (string, error) {
 }
 
 // ValidateRefreshToken valida um refresh token e retorna suas informações
-func ValidateRefreshToken(token string, deviceFingerprint string) (*models.RefreshToken, error) {
+// O device ID é verificado de forma estrita; fingerprint e IP são apenas sinais de risco
+func ValidateRefreshToken(token string, device DeviceContext) (*models.RefreshToken, error) {
 	// Calcular hash do token
 	tokenHash := hashToken(token)
 
@@ -162,26 +199,48 @@ func ValidateRefreshToken(token string, deviceFingerprint string) (*models.Refre
 		return nil, ErrRefreshTokenExpired
 	}
 
-	// Validar device fingerprint se habilitado
-	if EnableDeviceFingerprint && deviceFingerprint != "" {
-		if refreshToken.DeviceFingerprint != deviceFingerprint {
-			// Log de segurança: possível tentativa de uso de token roubado
-			log.Warn("device fingerprint mismatch",
-				"user_id", refreshToken.UserID,
-				"token_id", refreshToken.ID,
-				"expected", refreshToken.DeviceFingerprint,
-				"received", deviceFingerprint)
-			return nil, ErrDeviceFingerprintMismatch
-		}
+	// Tokens emitidos para um device ID só valem para esse dispositivo
+	// Tokens sem device ID (clientes antigos) adotam o ID enviado na próxima rotação
+	if refreshToken.DeviceID != "" && refreshToken.DeviceID != device.DeviceID {
+		// Log de segurança: possível tentativa de uso de token roubado
+		log.Warn("device id mismatch",
+			"user_id", refreshToken.UserID,
+			"token_id", refreshToken.ID)
+		return nil, ErrDeviceMismatch
+	}
+
+	if signals := refreshRiskSignals(&refreshToken, device); len(signals) > 0 {
+		log.Warn("refresh token risk signals",
+			"user_id", refreshToken.UserID,
+			"token_id", refreshToken.ID,
+			"signals", signals)
 	}
 
 	return &refreshToken, nil
 }
 
+// refreshRiskSignals compara o dispositivo atual com o que recebeu o token
+// Mudanças de User-Agent (atualização do app) e de IP (rede móvel) são comuns e não bloqueiam
+func refreshRiskSignals(refreshToken *models.RefreshToken, device DeviceContext) []string {
+	var signals []string
+
+	if EnableDeviceFingerprint && device.Fingerprint != "" && refreshToken.DeviceFingerprint != device.Fingerprint {
+		signals = append(signals, "fingerprint_changed")
+	}
+	if device.IPAddress != "" && refreshToken.IPAddress != device.IPAddress {
+		signals = append(signals, "ip_changed")
+	}
+	if refreshToken.DeviceID == "" && device.DeviceID == "" {
+		signals = append(signals, "no_device_id")
+	}
+
+	return signals
+}
+
 // RefreshAccessToken valida um refresh token e gera novos tokens (rotation)
-func RefreshAccessToken(token string, deviceFingerprint string, ipAddress string) (*RefreshTokenResult, error) {
+func RefreshAccessToken(token string, device DeviceContext) (*RefreshTokenResult, error) {
 	// Validar refresh token
-	refreshToken, err := ValidateRefreshToken(token, deviceFingerprint)
+	refreshToken, err := ValidateRefreshToken(token, device)
 	if err != nil {
 		return nil, err
 	}
@@ -208,9 +267,10 @@ func RefreshAccessToken(token string, deviceFingerprint string, ipAddress string
 		UserID:            refreshToken.UserID,
 		Email:             refreshToken.User.Email,
 		Role:              refreshToken.User.Role,
+		DeviceID:          device.DeviceID,
 		DeviceName:        refreshToken.DeviceName,
-		DeviceFingerprint: deviceFingerprint,
-		IPAddress:         ipAddress,
+		DeviceFingerprint: device.Fingerprint,
+		IPAddress:         device.IPAddress,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar novo refresh token: %w", err)
@@ -225,7 +285,12 @@ func RefreshAccessToken(token string, deviceFingerprint string, ipAddress string
 
 // RevokeRefreshToken revoga um refresh token específico pelo hash
 func RevokeRefreshToken(tokenHash string) error {
-	result := database.DB.Model(&models.RefreshToken{}).
+	return revokeRefreshToken(database.DB, tokenHash)
+}
+
+// revokeRefreshToken revoga um refresh token usando a conexão informada
+func revokeRefreshToken(db *gorm.DB, tokenHash string) error {
+	result := db.Model(&models.RefreshToken{}).
 		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
 		Update("revoked_at", time.Now())
 
@@ -258,9 +323,14 @@ func RevokeAllUserTokens(userID uint) error {
 
 // RevokeOldTokens mantém apenas os N tokens mais recentes de um usuário
 func RevokeOldTokens(userID uint, keepCount int) error {
+	return revokeOldTokens(database.DB, userID, keepCount)
+}
+
+// revokeOldTokens implementa RevokeOldTokens usando a conexão informada
+func revokeOldTokens(db *gorm.DB, userID uint, keepCount int) error {
 	// Buscar todos os tokens não revogados do usuário, ordenados por data de criação
 	var tokens []models.RefreshToken
-	err := database.DB.Where("user_id = ? AND revoked_at IS NULL", userID).
+	err := db.Where("user_id = ? AND revoked_at IS NULL", userID).
 		Order("created_at DESC").
 		Find(&tokens).Error
 
@@ -272,7 +342,7 @@ func RevokeOldTokens(userID uint, keepCount int) error {
 	if len(tokens) > keepCount {
 		tokensToRevoke := tokens[keepCount:]
 		for _, token := range tokensToRevoke {
-			if err := RevokeRefreshToken(token.TokenHash); err != nil {
+			if err := revokeRefreshToken(db, token.TokenHash); err != nil {
 				log.Error("erro ao revogar token antigo", "token_id", token.ID, "error", err)
 			}
 		}
@@ -296,6 +366,65 @@ func GetUserActiveTokens(userID uint) ([]models.RefreshToken, error) {
 	return tokens, nil
 }
 
+// IsKnownDevice verifica se o usuário já usou este dispositivo antes (inclusive sessões encerradas)
+// Usa o device ID quando disponível; clientes sem device ID são reconhecidos pelo fingerprint
+func IsKnownDevice(userID uint, deviceID, fingerprint string) (bool, error) {
+	query := database.DB.Unscoped().Model(&models.RefreshToken{}).Where("user_id = ?", userID)
+	if deviceID != "" {
+		query = query.Where("device_id = ?", deviceID)
+	} else {
+		query = query.Where("device_fingerprint = ?", fingerprint)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("erro ao verificar dispositivo: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// findUserActiveToken busca uma sessão ativa do usuário pelo ID
+func findUserActiveToken(userID uint, tokenID string) (*models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	err := database.DB.Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", tokenID, userID, time.Now()).
+		First(&refreshToken).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar dispositivo: %w", err)
+	}
+
+	return &refreshToken, nil
+}
+
+// RenameDevice altera o nome de uma sessão ativa do usuário
+// O nome acompanha o dispositivo nas rotações e em novos logins com o mesmo device ID
+func RenameDevice(userID uint, tokenID, name string) (*models.RefreshToken, error) {
+	refreshToken, err := findUserActiveToken(userID, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Model(refreshToken).Update("device_name", name).Error; err != nil {
+		return nil, fmt.Errorf("erro ao renomear dispositivo: %w", err)
+	}
+
+	return refreshToken, nil
+}
+
+// RevokeDevice encerra uma sessão ativa do usuário pelo ID
+func RevokeDevice(userID uint, tokenID string) error {
+	refreshToken, err := findUserActiveToken(userID, tokenID)
+	if err != nil {
+		return err
+	}
+
+	return RevokeRefreshToken(refreshToken.TokenHash)
+}
+
 // CleanupExpiredTokens remove tokens expirados do banco de dados
 func CleanupExpiredTokens() error {
 	result := database.DB.Unscoped().
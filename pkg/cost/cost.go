@@ -0,0 +1,237 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/units"
+)
+
+// Currency é a moeda dos preços e estimativas
+const Currency = "BRL"
+
+// Motivos para um ingrediente ficar fora da estimativa
+const (
+	ReasonNoPrice      = "no_price"      // Sem preço vigente na região nem na referência nacional
+	ReasonUnitMismatch = "unit_mismatch" // Unidade da receita não converte para a unidade do preço (ex.: dente x kg)
+)
+
+// ItemCost é o custo de uma linha de ingrediente da receita
+type ItemCost struct {
+	IngredientID uint     `json:"ingredient_id"`
+	Name         string   `json:"name"`
+	Quantity     float64  `json:"quantity"`
+	Unit         string   `json:"unit"`
+	Cost         *float64 `json:"cost"`                   // Nulo quando não foi possível estimar
+	PriceRegion  string   `json:"price_region,omitempty"` // Região do preço usado (a pedida ou BR)
+}
+
+// MissingPrice é um ingrediente que não entrou na estimativa
+type MissingPrice struct {
+	IngredientID uint   `json:"ingredient_id"`
+	Name         string `json:"name"`
+	Reason       string `json:"reason"`
+}
+
+// Estimate é a estimativa de custo de uma receita
+// Total e PerServing somam apenas os ingredientes com preço; Complete indica se todos tinham
+type Estimate struct {
+	Region     string         `json:"region"`
+	Currency   string         `json:"currency"`
+	Servings   int            `json:"servings"`
+	Total      float64        `json:"total"`
+	PerServing float64        `json:"per_serving"`
+	Complete   bool           `json:"complete"`
+	Items      []ItemCost     `json:"items"`
+	Missing    []MissingPrice `json:"missing"`
+}
+
+// CurrentPrices busca o preço vigente em on de cada ingrediente: o da região, ou o nacional (BR) quando a região não tem
+func CurrentPrices(db *gorm.DB, ingredientIDs []uint, region string, on time.Time) (map[uint]models.IngredientPrice, error) {
+	prices := make(map[uint]models.IngredientPrice)
+	if len(ingredientIDs) == 0 {
+		return prices, nil
+	}
+
+	var rows []models.IngredientPrice
+	if err := db.Where("ingredient_id IN ? AND region IN ? AND effective_from <= ?",
+		ingredientIDs, []string{region, models.DefaultPriceRegion}, on.Format(models.DateLayout)).
+		Order("effective_from DESC, id DESC").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar preços: %w", err)
+	}
+
+	// Linhas já vêm do preço mais recente para o mais antigo
+	for _, row := range rows {
+		current, found := prices[row.IngredientID]
+		if !found || (row.Region == region && current.Region != region) {
+			prices[row.IngredientID] = row
+		}
+	}
+	return prices, nil
+}
+
+// Calculate estima o custo dos ingredientes da receita (Ingredient precisa estar carregado)
+// Quantidades da receita e dos preços são convertidas para a unidade base antes da comparação
+func Calculate(items []models.RecipeIngredient, prices map[uint]models.IngredientPrice, region string, servings int) Estimate {
+	if servings < 1 {
+		servings = 1
+	}
+
+	estimate := Estimate{
+		Region:   region,
+		Currency: Currency,
+		Servings: servings,
+		Complete: true,
+		Items:    make([]ItemCost, 0, len(items)),
+		Missing:  []MissingPrice{},
+	}
+
+	var total float64
+	for _, item := range items {
+		line := ItemCost{IngredientID: item.IngredientID, Name: item.Ingredient.Name, Quantity: item.Quantity, Unit: item.Unit}
+
+		price, found := prices[item.IngredientID]
+		quantity, unit := units.Normalize(item.Quantity, item.Unit)
+		switch {
+		case !found:
+			estimate.Missing = append(estimate.Missing, MissingPrice{item.IngredientID, item.Ingredient.Name, ReasonNoPrice})
+		case unit != price.BaseUnit:
+			estimate.Missing = append(estimate.Missing, MissingPrice{item.IngredientID, item.Ingredient.Name, ReasonUnitMismatch})
+		default:
+			value := quantity * price.UnitPrice
+			total += value
+			rounded := round(value)
+			line.Cost = &rounded
+			line.PriceRegion = price.Region
+		}
+		estimate.Items = append(estimate.Items, line)
+	}
+
+	estimate.Complete = len(estimate.Missing) == 0
+	estimate.Total = round(total)
+	estimate.PerServing = round(total / float64(servings))
+	return estimate
+}
+
+// Recompute recalcula e grava o custo por porção da receita na região de referência (BR)
+// Fica nulo quando a receita não tem ingredientes ou algum deles não tem preço
+// Deve ser chamado sempre que os ingredientes, as quantidades ou as porções da receita mudarem
+func Recompute(db *gorm.DB, recipeID uint) (*float64, error) {
+	var recipe models.Recipe
+	if err := db.Unscoped().Select("id", "servings").First(&recipe, recipeID).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar receita: %w", err)
+	}
+
+	var items []models.RecipeIngredient
+	if err := db.Preload("Ingredient").Where("recipe_id = ?", recipeID).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar ingredientes: %w", err)
+	}
+
+	var perServing *float64
+	if len(items) > 0 {
+		ingredientIDs := make([]uint, len(items))
+		for i, item := range items {
+			ingredientIDs[i] = item.IngredientID
+		}
+		prices, err := CurrentPrices(db, ingredientIDs, models.DefaultPriceRegion, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if estimate := Calculate(items, prices, models.DefaultPriceRegion, recipe.Servings); estimate.Complete {
+			perServing = &estimate.PerServing
+		}
+	}
+
+	if err := db.Model(&models.Recipe{}).Unscoped().Where("id = ?", recipeID).
+		UpdateColumn("cost_per_serving", perServing).Error; err != nil {
+		return nil, fmt.Errorf("erro ao gravar custo por porção: %w", err)
+	}
+	return perServing, nil
+}
+
+// RecomputeForIngredient recalcula o custo por porção de todas as receitas que usam o ingrediente
+// Usado quando a tabela de preços do ingrediente muda
+func RecomputeForIngredient(db *gorm.DB, ingredientID uint) error {
+	var recipeIDs []uint
+	if err := db.Model(&models.RecipeIngredient{}).
+		Where("ingredient_id = ?", ingredientID).
+		Distinct().
+		Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return fmt.Errorf("erro ao buscar receitas do ingrediente: %w", err)
+	}
+
+	for _, recipeID := range recipeIDs {
+		if _, err := Recompute(db, recipeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyEffectivePrices recalcula as receitas dos ingredientes com preço de referência que já está em vigor
+// mas ainda não foi aplicado (preços cadastrados com data futura) e marca esses preços como aplicados
+// A pendência fica gravada no preço, então vigências ocorridas com o servidor parado são aplicadas na próxima execução
+func ApplyEffectivePrices(db *gorm.DB, now time.Time) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var pending []models.IngredientPrice
+		if err := tx.Select("id", "ingredient_id").
+			Where("region = ? AND applied_at IS NULL AND effective_from <= ?", models.DefaultPriceRegion, now.Format(models.DateLayout)).
+			Find(&pending).Error; err != nil {
+			return fmt.Errorf("erro ao buscar preços que entraram em vigor: %w", err)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		priceIDs := make([]uint, len(pending))
+		ingredients := make(map[uint]bool)
+		for i, price := range pending {
+			priceIDs[i] = price.ID
+			if ingredients[price.IngredientID] {
+				continue
+			}
+			ingredients[price.IngredientID] = true
+			if err := RecomputeForIngredient(tx, price.IngredientID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.IngredientPrice{}).Where("id IN ?", priceIDs).
+			UpdateColumn("applied_at", now).Error; err != nil {
+			return fmt.Errorf("erro ao marcar preços como aplicados: %w", err)
+		}
+		log.Info("custos de receitas recalculados", "ingredients", len(ingredients), "prices", len(priceIDs))
+		return nil
+	})
+}
+
+// StartScheduler inicia o job que recalcula o custo das receitas quando preços agendados entram em vigor
+// Roda também ao iniciar, aplicando os preços que entraram em vigor com o servidor parado
+func StartScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		apply := func() {
+			if err := ApplyEffectivePrices(database.DB, time.Now()); err != nil {
+				log.Error("erro ao recalcular custos de receitas", "error", err)
+			}
+		}
+
+		apply()
+		for range ticker.C {
+			apply()
+		}
+	}()
+	log.Info("job de recálculo de custos iniciado", "interval", interval)
+}
+
+// round arredonda para centavos
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,138 @@
+package dietary
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+// Dietas reconhecidas no filtro de receitas
+const (
+	DietVegan       = "vegan"
+	DietVegetarian  = "vegetarian"
+	DietGlutenFree  = "gluten_free"
+	DietLactoseFree = "lactose_free"
+)
+
+// DietColumns mapeia cada dieta para a coluna de classificação em recipes
+var DietColumns = map[string]string{
+	DietVegan:       "is_vegan",
+	DietVegetarian:  "is_vegetarian",
+	DietGlutenFree:  "is_gluten_free",
+	DietLactoseFree: "is_lactose_free",
+}
+
+// Classification é a classificação alimentar de uma receita
+type Classification struct {
+	Vegan       bool
+	Vegetarian  bool
+	GlutenFree  bool
+	LactoseFree bool
+}
+
+// ApplyTo copia a classificação para os campos da receita
+func (c Classification) ApplyTo(recipe *models.Recipe) {
+	recipe.IsVegan = c.Vegan
+	recipe.IsVegetarian = c.Vegetarian
+	recipe.IsGlutenFree = c.GlutenFree
+	recipe.IsLactoseFree = c.LactoseFree
+}
+
+// Satisfies indica se a classificação atende à dieta informada (chave de DietColumns)
+func (c Classification) Satisfies(diet string) bool {
+	switch diet {
+	case DietVegan:
+		return c.Vegan
+	case DietVegetarian:
+		return c.Vegetarian
+	case DietGlutenFree:
+		return c.GlutenFree
+	case DietLactoseFree:
+		return c.LactoseFree
+	}
+	return false
+}
+
+// Classify deriva a classificação a partir dos ingredientes da receita
+// Receitas sem ingredientes não recebem nenhuma classificação
+func Classify(ingredients []models.Ingredient) Classification {
+	if len(ingredients) == 0 {
+		return Classification{}
+	}
+
+	c := Classification{Vegan: true, Vegetarian: true, GlutenFree: true, LactoseFree: true}
+	for _, ing := range ingredients {
+		if ing.ContainsMeat || ing.ContainsShellfish {
+			c.Vegetarian = false
+		}
+		// Leite, ovos e carnes são de origem animal mesmo sem a flag explícita
+		if ing.AnimalOrigin || ing.ContainsLactose || ing.ContainsEgg || ing.ContainsMeat || ing.ContainsShellfish {
+			c.Vegan = false
+		}
+		if ing.ContainsGluten {
+			c.GlutenFree = false
+		}
+		if ing.ContainsLactose {
+			c.LactoseFree = false
+		}
+	}
+	return c
+}
+
+// Allergens lista os alérgenos presentes nos ingredientes, na ordem de models.Allergens
+func Allergens(ingredients []models.Ingredient) []string {
+	var allergens []string
+	for _, allergen := range models.Allergens {
+		for i := range ingredients {
+			if ingredients[i].HasAllergen(allergen) {
+				allergens = append(allergens, allergen)
+				break
+			}
+		}
+	}
+	return allergens
+}
+
+// Recompute recalcula e grava a classificação de uma receita a partir dos ingredientes atuais
+// Deve ser chamado sempre que a lista de ingredientes da receita mudar
+func Recompute(db *gorm.DB, recipeID uint) (Classification, error) {
+	var ingredients []models.Ingredient
+	if err := db.Model(&models.Ingredient{}).
+		Joins("JOIN recipe_ingredients ON recipe_ingredients.ingredient_id = ingredients.id").
+		Where("recipe_ingredients.recipe_id = ?", recipeID).
+		Find(&ingredients).Error; err != nil {
+		return Classification{}, fmt.Errorf("erro ao carregar ingredientes: %w", err)
+	}
+
+	c := Classify(ingredients)
+	if err := db.Model(&models.Recipe{}).Where("id = ?", recipeID).UpdateColumns(map[string]interface{}{
+		"is_vegan":        c.Vegan,
+		"is_vegetarian":   c.Vegetarian,
+		"is_gluten_free":  c.GlutenFree,
+		"is_lactose_free": c.LactoseFree,
+	}).Error; err != nil {
+		return Classification{}, fmt.Errorf("erro ao gravar classificação: %w", err)
+	}
+	return c, nil
+}
+
+// RecomputeForIngredient recalcula a classificação de todas as receitas que usam o ingrediente
+// Usado quando as flags de um ingrediente do catálogo são alteradas
+func RecomputeForIngredient(db *gorm.DB, ingredientID uint) error {
+	var recipeIDs []uint
+	if err := db.Model(&models.RecipeIngredient{}).
+		Where("ingredient_id = ?", ingredientID).
+		Distinct().
+		Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return fmt.Errorf("erro ao buscar receitas do ingrediente: %w", err)
+	}
+
+	for _, recipeID := range recipeIDs {
+		if _, err := Recompute(db, recipeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
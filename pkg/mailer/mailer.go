@@ -0,0 +1,101 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+)
+
+// Message representa um e-mail a ser enviado
+type Message struct {
+	To      string
+	Subject string
+	Body    string // Texto puro
+}
+
+// Sender interface para serviços de envio de e-mail (permite mocking)
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// DefaultSender é o serviço usado por Send
+// Pode ser substituído nos testes para capturar as mensagens
+var DefaultSender Sender = newSenderFromEnv()
+
+// Send envia uma mensagem usando o DefaultSender
+func Send(ctx context.Context, msg Message) error {
+	return DefaultSender.Send(ctx, msg)
+}
+
+// newSenderFromEnv usa SMTP se SMTP_HOST estiver configurado, senão apenas loga
+func newSenderFromEnv() Sender {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return &LogSender{IncludeBody: os.Getenv("ENV") != "production"}
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+
+	return &SMTPSender{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USER"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+}
+
+// SMTPSender envia e-mails via servidor SMTP
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send envia a mensagem via SMTP (texto puro, UTF-8)
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	headers := []string{
+		"From: " + s.From,
+		"To: " + msg.To,
+		"Subject: " + msg.Subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + msg.Body
+
+	if err := smtp.SendMail(s.Host+":"+s.Port, auth, s.From, []string{msg.To}, []byte(body)); err != nil {
+		return fmt.Errorf("erro ao enviar e-mail: %w", err)
+	}
+
+	log.InfoCtx(ctx, "email sent", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+// LogSender apenas registra as mensagens no log (desenvolvimento)
+type LogSender struct {
+	IncludeBody bool // Nunca habilitar em produção: o corpo pode conter tokens
+}
+
+// Send registra a mensagem no log
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	if s.IncludeBody {
+		log.InfoCtx(ctx, "email (not sent, SMTP not configured)", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	} else {
+		log.WarnCtx(ctx, "email not sent, SMTP not configured", "to", msg.To, "subject", msg.Subject)
+	}
+	return nil
+}
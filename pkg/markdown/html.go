@@ -0,0 +1,167 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingPattern    = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*$`)
+	listItemPattern   = regexp.MustCompile(`^( *)([-*+]|\d+[.)])\s+(.*)$`)
+	rulePattern       = regexp.MustCompile(`^(-{3,}|\*{3,}|_{3,})$`)
+	boldItalicPattern = regexp.MustCompile(`\*\*\*(\S(?:.*?\S)?)\*\*\*`)
+	boldPattern       = regexp.MustCompile(`(?:\*\*|__)(\S(?:.*?\S)?)(?:\*\*|__)`)
+	italicPattern     = regexp.MustCompile(`\*(\S(?:.*?\S)?)\*`)
+)
+
+// listLevel representa uma lista aberta durante a renderização
+type listLevel struct {
+	indent  int
+	ordered bool
+}
+
+// ToHTML renderiza o subconjunto permitido de Markdown em HTML seguro
+// Todo texto é escapado; construções fora da lista permitida (inclusive HTML
+// gravado antes da sanitização) aparecem como texto literal.
+func ToHTML(source string) string {
+	var out []string
+	var lists []listLevel
+	var paragraph []string
+
+	closeParagraph := func() {
+		if len(paragraph) > 0 {
+			out = append(out, "<p>"+strings.Join(paragraph, "\n")+"</p>")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		top := lists[len(lists)-1]
+		lists = lists[:len(lists)-1]
+		out[len(out)-1] += "</li>"
+		if top.ordered {
+			out = append(out, "</ol>")
+		} else {
+			out = append(out, "</ul>")
+		}
+	}
+	closeLists := func() {
+		for len(lists) > 0 {
+			closeList()
+		}
+	}
+
+	for _, line := range strings.Split(normalize(source), "\n") {
+		if strings.TrimSpace(line) == "" {
+			closeParagraph()
+			continue
+		}
+
+		if match := listItemPattern.FindStringSubmatch(line); match != nil {
+			closeParagraph()
+
+			indent := len(match[1])
+			marker := match[2]
+			ordered := marker[0] >= '0' && marker[0] <= '9'
+
+			for len(lists) > 0 && indent < lists[len(lists)-1].indent {
+				closeList()
+			}
+
+			open := true
+			if len(lists) > 0 && indent == lists[len(lists)-1].indent {
+				if lists[len(lists)-1].ordered == ordered {
+					out[len(out)-1] += "</li>"
+					open = false
+				} else {
+					closeList()
+				}
+			}
+
+			if open {
+				lists = append(lists, listLevel{indent: indent, ordered: ordered})
+				switch {
+				case !ordered:
+					out = append(out, "<ul>")
+				case listStart(marker) != 1:
+					out = append(out, fmt.Sprintf(`<ol start="%d">`, listStart(marker)))
+				default:
+					out = append(out, "<ol>")
+				}
+			}
+
+			out = append(out, "<li>"+renderInline(match[3]))
+			continue
+		}
+
+		// Linha indentada dentro de uma lista continua o item atual
+		if len(lists) > 0 && strings.HasPrefix(line, " ") {
+			out[len(out)-1] += "\n" + renderInline(strings.TrimSpace(line))
+			continue
+		}
+
+		closeLists()
+		trimmed := strings.TrimSpace(line)
+
+		if match := headingPattern.FindStringSubmatch(trimmed); match != nil {
+			closeParagraph()
+			level := len(match[1])
+			out = append(out, fmt.Sprintf("<h%d>%s</h%d>", level, renderInline(match[2]), level))
+			continue
+		}
+
+		if rulePattern.MatchString(trimmed) {
+			closeParagraph()
+			out = append(out, "<hr>")
+			continue
+		}
+
+		paragraph = append(paragraph, renderInline(trimmed))
+	}
+
+	closeParagraph()
+	closeLists()
+
+	return strings.Join(out, "\n")
+}
+
+// listStart retorna o número inicial de um item de lista numerada
+func listStart(marker string) int {
+	n, err := strconv.Atoi(strings.TrimRight(marker, ".)"))
+	if err != nil {
+		return 1
+	}
+	return n
+}
+
+// renderInline renderiza links e ênfase de uma linha, escapando o restante
+// Os links são trocados por marcadores antes da ênfase, para que **[texto](url)** funcione
+func renderInline(text string) string {
+	var links []string
+	text = linkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		match := linkPattern.FindStringSubmatch(link)
+		if !isAllowedURL(match[2]) {
+			return link
+		}
+		links = append(links, fmt.Sprintf(`<a href="%s" rel="nofollow noopener noreferrer">%s</a>`,
+			html.EscapeString(strings.TrimSpace(match[2])), renderEmphasis(match[1])))
+		return fmt.Sprintf("\x00%d\x00", len(links)-1)
+	})
+
+	rendered := renderEmphasis(text)
+	for i, link := range links {
+		rendered = strings.Replace(rendered, fmt.Sprintf("\x00%d\x00", i), link, 1)
+	}
+	return rendered
+}
+
+// renderEmphasis escapa o texto e aplica negrito e itálico
+func renderEmphasis(text string) string {
+	escaped := html.EscapeString(text)
+	escaped = boldItalicPattern.ReplaceAllString(escaped, "<strong><em>$1</em></strong>")
+	escaped = boldPattern.ReplaceAllString(escaped, "<strong>$1</strong>")
+	escaped = italicPattern.ReplaceAllString(escaped, "<em>$1</em>")
+	return escaped
+}
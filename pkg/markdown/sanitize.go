@@ -0,0 +1,135 @@
+package markdown
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Construções permitidas no modo de preparo (ver MARKDOWN_INSTRUCTIONS_GUIDE.md):
+// cabeçalhos, parágrafos, listas numeradas e não numeradas (inclusive aninhadas),
+// linha horizontal, negrito, itálico e links http/https/mailto.
+// Qualquer outra construção é recusada na validação.
+
+var (
+	htmlTagPattern       = regexp.MustCompile(`<\s*/?\s*[a-zA-Z!?][^>]*>`)
+	imagePattern         = regexp.MustCompile(`!\[[^\]]*\]\(`)
+	linkPattern          = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]*)\)`)
+	referenceLinkPattern = regexp.MustCompile(`^\s*\[[^\]]+\]:\s*\S+`)
+	fencePattern         = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// invisibleRunes são caracteres de largura zero e de controle de direção,
+// usados para esconder conteúdo ou inverter a exibição de links
+var invisibleRunes = map[rune]bool{
+	'\u200b': true, '\u200c': true, '\u200d': true, '\u200e': true, '\u200f': true,
+	'\u202a': true, '\u202b': true, '\u202c': true, '\u202d': true, '\u202e': true,
+	'\u2066': true, '\u2067': true, '\u2068': true, '\u2069': true, '\ufeff': true,
+}
+
+// ValidationError indica uma construção Markdown fora da lista permitida
+type ValidationError struct {
+	Line   int
+	Reason string
+}
+
+// Error implementa a interface error
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("linha %d: %s", e.Line, e.Reason)
+}
+
+// Sanitize normaliza o Markdown e verifica se usa apenas construções permitidas
+// Retorna o Markdown normalizado ou *ValidationError com a primeira construção recusada
+func Sanitize(source string) (string, error) {
+	normalized := normalize(source)
+
+	for i, line := range strings.Split(normalized, "\n") {
+		if reason := disallowedConstruct(line); reason != "" {
+			return "", &ValidationError{Line: i + 1, Reason: reason}
+		}
+	}
+
+	return normalized, nil
+}
+
+// normalize padroniza quebras de linha e espaços e remove caracteres de controle e invisíveis
+func normalize(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	source = strings.ReplaceAll(source, "\t", "    ")
+
+	source = strings.Map(func(r rune) rune {
+		if r == '\n' {
+			return r
+		}
+		if r < 0x20 || r == 0x7f || invisibleRunes[r] {
+			return -1
+		}
+		return r
+	}, source)
+
+	lines := strings.Split(source, "\n")
+	result := make([]string, 0, len(lines))
+	blank := 0
+	for _, line := range lines {
+		line = strings.TrimRight(line, " ")
+		if line == "" {
+			blank++
+			// No máximo uma linha em branco entre blocos
+			if blank > 1 {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		result = append(result, line)
+	}
+
+	return strings.Trim(strings.Join(result, "\n"), "\n")
+}
+
+// disallowedConstruct retorna o motivo da recusa de uma linha ou "" se for permitida
+func disallowedConstruct(line string) string {
+	trimmed := strings.TrimSpace(line)
+
+	switch {
+	case fencePattern.MatchString(line):
+		return "blocos de código não são permitidos"
+	case strings.HasPrefix(trimmed, ">"):
+		return "citações não são permitidas"
+	case strings.HasPrefix(trimmed, "|"):
+		return "tabelas não são permitidas"
+	case strings.Contains(line, "<!--") || htmlTagPattern.MatchString(line):
+		return "HTML não é permitido"
+	case imagePattern.MatchString(line):
+		return "imagens não são permitidas (use a imagem da receita ou dos passos)"
+	case referenceLinkPattern.MatchString(line):
+		return "links de referência não são permitidos"
+	}
+
+	for _, match := range linkPattern.FindAllStringSubmatch(line, -1) {
+		if !isAllowedURL(match[2]) {
+			return "links devem usar http, https ou mailto"
+		}
+	}
+
+	return ""
+}
+
+// isAllowedURL verifica se o destino de um link é http(s) com host ou mailto
+func isAllowedURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return u.Opaque != ""
+	default:
+		return false
+	}
+}
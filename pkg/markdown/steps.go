@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParsedStep representa um passo extraído de um modo de preparo em Markdown
+type ParsedStep struct {
+	Text            string
+	DurationSeconds *int
+}
+
+var (
+	// numberedItemPattern reconhece itens de lista numerada de primeiro nível ("1. texto" ou "1) texto")
+	numberedItemPattern = regexp.MustCompile(`^(\d+)[.)]\s+(.+)$`)
+	// durationPattern reconhece durações como "40 minutos", "2-3 min", "40 a 45 minutos" ou "1 hora"
+	durationPattern = regexp.MustCompile(`(?i)(\d+)(?:\s*(?:-|a|–)\s*(\d+))?\s*(minutos?|min|horas?|h)\b`)
+)
+
+// ExtractSteps converte as listas numeradas de um modo de preparo em passos
+// Itens aninhados (indentados) são anexados ao passo anterior; cabeçalhos e parágrafos são ignorados.
+// Listas em seções diferentes (ex: "### Massa", "### Recheio") viram passos consecutivos.
+func ExtractSteps(instructions string) []ParsedStep {
+	var steps []ParsedStep
+	var current []string
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		text := strings.TrimSpace(strings.Join(current, "\n"))
+		if text != "" {
+			steps = append(steps, ParsedStep{
+				Text:            text,
+				DurationSeconds: ParseDuration(text),
+			})
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(instructions, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		indented := len(line) > 0 && (line[0] == ' ' || line[0] == '\t')
+
+		if !indented {
+			if match := numberedItemPattern.FindStringSubmatch(trimmed); match != nil {
+				flush()
+				current = []string{strings.TrimSpace(match[2])}
+				continue
+			}
+		}
+
+		if current == nil {
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			// Linha em branco pode separar itens da mesma lista; mantém o passo aberto
+		case indented:
+			current = append(current, trimmed)
+		default:
+			// Parágrafo, cabeçalho ou outra lista de primeiro nível encerra o passo
+			flush()
+		}
+	}
+	flush()
+
+	return steps
+}
+
+// ParseDuration extrai a duração (em segundos) mencionada em um texto
+// Para intervalos ("40-45 minutos") usa o limite superior; retorna nil se não houver duração
+func ParseDuration(text string) *int {
+	match := durationPattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+
+	value, err := strconv.Atoi(match[1])
+	if err != nil {
+		return nil
+	}
+	if match[2] != "" {
+		if upper, err := strconv.Atoi(match[2]); err == nil && upper > value {
+			value = upper
+		}
+	}
+
+	unit := strings.ToLower(match[3])
+	seconds := value * 60
+	if strings.HasPrefix(unit, "h") {
+		seconds = value * 3600
+	}
+
+	if seconds <= 0 {
+		return nil
+	}
+	return &seconds
+}
@@ -0,0 +1,71 @@
+package nutrition
+
+import (
+	"math"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+// GoalPreset é um conjunto pronto de metas diárias
+type GoalPreset struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Macros
+}
+
+// GoalPresets lista os presets de metas, do padrão da rotulagem aos objetivos específicos
+// O preset reference usa os valores diários de referência da tabela nutricional (IN 75/2020)
+var GoalPresets = []GoalPreset{
+	{"reference", "Referência ANVISA (2000 kcal)", Macros{
+		Calories: dailyValues[LabelEnergy],
+		Protein:  dailyValues[LabelProtein],
+		Carbs:    dailyValues[LabelCarbohydrates],
+		Fat:      dailyValues[LabelTotalFat],
+		Fiber:    dailyValues[LabelFiber],
+	}},
+	{"weight_loss", "Emagrecimento (1500 kcal)", Macros{Calories: 1500, Protein: 90, Carbs: 150, Fat: 50, Fiber: 25}},
+	{"low_carb", "Low carb (1800 kcal)", Macros{Calories: 1800, Protein: 110, Carbs: 100, Fat: 100, Fiber: 25}},
+	{"muscle_gain", "Ganho de massa (2800 kcal)", Macros{Calories: 2800, Protein: 160, Carbs: 350, Fat: 80, Fiber: 30}},
+}
+
+// FindGoalPreset busca um preset pela chave
+func FindGoalPreset(key string) (GoalPreset, bool) {
+	for _, preset := range GoalPresets {
+		if preset.Key == key {
+			return preset, true
+		}
+	}
+	return GoalPreset{}, false
+}
+
+// GoalMacros retorna as metas do usuário como macronutrientes
+func GoalMacros(goal *models.NutritionGoal) Macros {
+	return Macros{
+		Calories: goal.Calories,
+		Protein:  goal.Protein,
+		Carbs:    goal.Carbs,
+		Fat:      goal.Fat,
+		Fiber:    goal.Fiber,
+	}
+}
+
+// Coverage calcula o percentual da meta diária coberto por uma porção da receita
+func Coverage(recipe *models.Recipe, goal *models.NutritionGoal) *models.GoalCoverage {
+	serving := PerServing(recipe)
+	return &models.GoalCoverage{
+		Calories: percentOf(serving.Calories, goal.Calories),
+		Protein:  percentOf(serving.Protein, goal.Protein),
+		Carbs:    percentOf(serving.Carbs, goal.Carbs),
+		Fat:      percentOf(serving.Fat, goal.Fat),
+		Fiber:    percentOf(serving.Fiber, goal.Fiber),
+	}
+}
+
+// percentOf retorna o percentual arredondado de value em relação à meta (nulo quando a meta não foi definida)
+func percentOf(value, goal float64) *int {
+	if goal <= 0 {
+		return nil
+	}
+	percent := int(math.Round(value / goal * 100))
+	return &percent
+}
@@ -0,0 +1,192 @@
+package nutrition
+
+import (
+	"math"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+// Itens da tabela nutricional que vêm dos campos de Ingredient
+const (
+	LabelEnergy        = "energy"
+	LabelCarbohydrates = "carbohydrates"
+	LabelProtein       = "protein"
+	LabelTotalFat      = "total_fat"
+	LabelFiber         = "fiber"
+)
+
+// labelItem descreve uma linha da tabela nutricional (RDC 429/2020, na ordem obrigatória)
+type labelItem struct {
+	key    string
+	name   string
+	unit   string
+	indent bool // Subitem (açúcares, gorduras saturadas e trans)
+}
+
+var labelItems = []labelItem{
+	{LabelEnergy, "Valor energético", "kcal", false},
+	{LabelCarbohydrates, "Carboidratos", "g", false},
+	{models.NutrientTotalSugars, "Açúcares totais", "g", true},
+	{models.NutrientAddedSugars, "Açúcares adicionados", "g", true},
+	{LabelProtein, "Proteínas", "g", false},
+	{LabelTotalFat, "Gorduras totais", "g", false},
+	{models.NutrientSaturatedFat, "Gorduras saturadas", "g", true},
+	{models.NutrientTransFat, "Gorduras trans", "g", true},
+	{LabelFiber, "Fibras alimentares", "g", false},
+	{models.NutrientSodium, "Sódio", "mg", false},
+}
+
+// dailyValues são os valores diários de referência para adultos (IN 75/2020, Anexo II)
+// Açúcares totais e gorduras trans não têm VD
+var dailyValues = map[string]float64{
+	LabelEnergy:                 2000,
+	LabelCarbohydrates:          300,
+	models.NutrientAddedSugars:  50,
+	LabelProtein:                50,
+	LabelTotalFat:               65,
+	models.NutrientSaturatedFat: 20,
+	LabelFiber:                  25,
+	models.NutrientSodium:       2000,
+}
+
+// nonSignificant são os limites abaixo dos quais o valor é declarado como zero (IN 75/2020, Anexo IV)
+var nonSignificant = map[string]float64{
+	LabelEnergy:                 4,
+	LabelCarbohydrates:          0.5,
+	models.NutrientTotalSugars:  0.5,
+	models.NutrientAddedSugars:  0.5,
+	LabelProtein:                0.5,
+	LabelTotalFat:               0.5,
+	models.NutrientSaturatedFat: 0.1,
+	models.NutrientTransFat:     0.1,
+	LabelFiber:                  0.5,
+	models.NutrientSodium:       5,
+}
+
+// highInLimits são os limites da rotulagem frontal "ALTO EM" para alimentos sólidos, por 100g (IN 75/2020, Anexo XV)
+var highInLimits = []struct {
+	key   string
+	limit float64
+}{
+	{models.NutrientAddedSugars, 15},
+	{models.NutrientSaturatedFat, 6},
+	{models.NutrientSodium, 600},
+}
+
+// LabelRow é uma linha da tabela nutricional
+// Valores nulos indicam nutriente sem medição nos ingredientes
+type LabelRow struct {
+	Nutrient   string   `json:"nutrient"`
+	Name       string   `json:"name"`
+	Unit       string   `json:"unit"`
+	Indent     bool     `json:"indent,omitempty"`
+	Per100g    *float64 `json:"per_100g"`
+	PerPortion *float64 `json:"per_portion"`
+	DailyValue *int     `json:"daily_value_percent"` // %VD da porção; nulo quando não há VD
+	Status     string   `json:"status"`
+}
+
+// Label é a tabela de informação nutricional no modelo da ANVISA (RDC 429/2020 e IN 75/2020)
+type Label struct {
+	Servings     int        `json:"servings"`
+	PortionGrams float64    `json:"portion_grams"`
+	Rows         []LabelRow `json:"rows"`
+	HighIn       []string   `json:"high_in"`              // Rotulagem frontal: added_sugars, saturated_fat, sodium
+	Incomplete   []string   `json:"incomplete,omitempty"` // Itens sem medição em todos os ingredientes
+}
+
+// BuildLabel monta a tabela nutricional da receita, por 100g e por porção (uma porção = total / servings)
+// Quantidades dos ingredientes são tratadas em gramas, como no cálculo nutricional da receita
+func BuildLabel(items []models.RecipeIngredient, servings int) Label {
+	if servings < 1 {
+		servings = 1
+	}
+
+	var weight float64
+	macros := map[string]float64{}
+	for _, item := range items {
+		factor := item.Quantity / 100.0
+		weight += item.Quantity
+		macros[LabelEnergy] += item.Ingredient.Calories * factor
+		macros[LabelCarbohydrates] += item.Ingredient.Carbs * factor
+		macros[LabelProtein] += item.Ingredient.Protein * factor
+		macros[LabelTotalFat] += item.Ingredient.Fat * factor
+		macros[LabelFiber] += item.Ingredient.Fiber * factor
+	}
+
+	profile := make(map[string]NutrientAmount)
+	for _, amount := range Profile(items, servings) {
+		profile[amount.Nutrient] = amount
+	}
+
+	label := Label{
+		Servings:     servings,
+		PortionGrams: roundTo(weight/float64(servings), 0),
+		HighIn:       []string{},
+	}
+
+	per100g := make(map[string]float64)
+	for _, item := range labelItems {
+		row := LabelRow{Nutrient: item.key, Name: item.name, Unit: item.unit, Indent: item.indent}
+
+		var total *float64
+		if value, ok := macros[item.key]; ok {
+			total = &value
+			row.Status = models.NutrientStatusMeasured
+		} else {
+			amount := profile[item.key]
+			total = amount.Total
+			row.Status = amount.Status
+			if amount.Status == models.NutrientStatusTrace {
+				zero := 0.0
+				total = &zero
+			}
+		}
+		if row.Status != models.NutrientStatusMeasured && row.Status != models.NutrientStatusTrace {
+			label.Incomplete = append(label.Incomplete, item.key)
+		}
+
+		if total != nil {
+			portion := *total / float64(servings)
+			row.PerPortion = declared(item.key, portion)
+			if weight > 0 {
+				per100g[item.key] = *total / weight * 100
+				row.Per100g = declared(item.key, per100g[item.key])
+			}
+			if dv, ok := dailyValues[item.key]; ok {
+				percent := int(math.Round(*row.PerPortion / dv * 100))
+				row.DailyValue = &percent
+			}
+		}
+
+		label.Rows = append(label.Rows, row)
+	}
+
+	// Parciais são limites inferiores: se já atingem o limite, o alerta vale
+	for _, high := range highInLimits {
+		if value, ok := per100g[high.key]; ok && value >= high.limit {
+			label.HighIn = append(label.HighIn, high.key)
+		}
+	}
+
+	return label
+}
+
+// declared aplica as regras de declaração da IN 75/2020:
+// valores não significativos viram zero; a partir de 10, inteiros; abaixo de 10, uma casa decimal
+func declared(key string, value float64) *float64 {
+	if value <= nonSignificant[key] {
+		value = 0
+	} else if value >= 10 {
+		value = roundTo(value, 0)
+	} else {
+		value = roundTo(value, 1)
+	}
+	return &value
+}
+
+// roundTo arredonda para o número de casas decimais informado
+func roundTo(value float64, decimals int) float64 {
+	pow := math.Pow(10, float64(decimals))
+	return math.Round(value*pow) / pow
+}
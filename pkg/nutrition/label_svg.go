@@ -0,0 +1,112 @@
+package nutrition
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+// highInNames são os textos da rotulagem frontal
+var highInNames = map[string]string{
+	models.NutrientAddedSugars:  "AÇÚCAR ADICIONADO",
+	models.NutrientSaturatedFat: "GORDURA SATURADA",
+	models.NutrientSodium:       "SÓDIO",
+}
+
+// Dimensões do SVG (px)
+const (
+	svgWidth     = 360
+	svgRowHeight = 22
+	svgPadding   = 8
+)
+
+// SVG renderiza a rotulagem frontal (se houver) e a tabela nutricional em preto e branco
+func (l Label) SVG() []byte {
+	var body strings.Builder
+	y := svgPadding
+
+	// Rotulagem frontal "ALTO EM"
+	if len(l.HighIn) > 0 {
+		boxHeight := 30 + len(l.HighIn)*svgRowHeight
+		fmt.Fprintf(&body, `<rect x="%d" y="%d" width="160" height="%d" fill="#000"/>`, svgPadding, y, boxHeight)
+		fmt.Fprintf(&body, `<text x="%d" y="%d" fill="#fff" font-size="16" font-weight="bold">ALTO EM</text>`, svgPadding+10, y+22)
+		for i, key := range l.HighIn {
+			fmt.Fprintf(&body, `<rect x="%d" y="%d" width="140" height="%d" fill="#fff"/>`, svgPadding+10, y+30+i*svgRowHeight, svgRowHeight-4)
+			fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="12" font-weight="bold">%s</text>`, svgPadding+14, y+44+i*svgRowHeight, html.EscapeString(highInNames[key]))
+		}
+		y += boxHeight + 12
+	}
+
+	// Tabela nutricional
+	tableTop := y
+	col100g, colPortion, colDV := 210, 270, 345
+
+	fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="15" font-weight="bold">INFORMAÇÃO NUTRICIONAL</text>`, svgPadding+6, y+20)
+	y += 28
+	fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="11">Porções por receita: %d</text>`, svgPadding+6, y+12, l.Servings)
+	y += 16
+	fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="11">Porção: %s g</text>`, svgPadding+6, y+12, formatNumber(l.PortionGrams))
+	y += 20
+	fmt.Fprintf(&body, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#000" stroke-width="4"/>`, svgPadding, y, svgWidth-svgPadding, y)
+
+	fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="11" font-weight="bold" text-anchor="end">100 g</text>`, col100g, y+16)
+	fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="11" font-weight="bold" text-anchor="end">%s g</text>`, colPortion, y+16, formatNumber(l.PortionGrams))
+	fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="11" font-weight="bold" text-anchor="end">%%VD*</text>`, colDV, y+16)
+	y += svgRowHeight
+
+	for _, row := range l.Rows {
+		fmt.Fprintf(&body, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#000" stroke-width="1"/>`, svgPadding, y, svgWidth-svgPadding, y)
+
+		x := svgPadding + 6
+		if row.Indent {
+			x += 12
+		}
+		fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="11">%s (%s)</text>`, x, y+15, html.EscapeString(row.Name), row.Unit)
+		fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="11" text-anchor="end">%s</text>`, col100g, y+15, formatOptional(row.Per100g))
+		fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="11" text-anchor="end">%s</text>`, colPortion, y+15, formatOptional(row.PerPortion))
+
+		dv := ""
+		if row.DailyValue != nil {
+			dv = strconv.Itoa(*row.DailyValue)
+		}
+		fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="11" text-anchor="end">%s</text>`, colDV, y+15, dv)
+		y += svgRowHeight
+	}
+
+	fmt.Fprintf(&body, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#000" stroke-width="2"/>`, svgPadding, y, svgWidth-svgPadding, y)
+	fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="9">*Percentual de valores diários fornecidos pela porção.</text>`, svgPadding+6, y+14)
+	if len(l.Incomplete) > 0 {
+		y += 14
+		fmt.Fprintf(&body, `<text x="%d" y="%d" font-size="9">— Sem medição nos ingredientes.</text>`, svgPadding+6, y+14)
+	}
+	y += 22
+
+	// Borda da tabela
+	fmt.Fprintf(&body, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="#000" stroke-width="2"/>`,
+		svgPadding, tableTop, svgWidth-2*svgPadding, y-tableTop)
+
+	height := y + svgPadding
+	var out strings.Builder
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">`,
+		svgWidth, height, svgWidth, height)
+	fmt.Fprintf(&out, `<rect width="100%%" height="100%%" fill="#fff"/>`)
+	out.WriteString(body.String())
+	out.WriteString(`</svg>`)
+	return []byte(out.String())
+}
+
+// formatOptional formata um valor declarado, usando travessão quando não há medição
+func formatOptional(value *float64) string {
+	if value == nil {
+		return "—"
+	}
+	return formatNumber(*value)
+}
+
+// formatNumber formata no padrão brasileiro (vírgula decimal)
+func formatNumber(value float64) string {
+	return strings.Replace(strconv.FormatFloat(value, 'f', -1, 64), ".", ",", 1)
+}
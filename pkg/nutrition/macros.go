@@ -0,0 +1,143 @@
+package nutrition
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+// Macronutrientes com valor por porção pré-calculado em recipes
+const (
+	MacroCalories = "calories"
+	MacroProtein  = "protein"
+	MacroCarbs    = "carbs"
+	MacroFat      = "fat"
+	MacroFiber    = "fiber"
+)
+
+// MacroColumns mapeia cada macronutriente para a coluna por porção em recipes
+var MacroColumns = map[string]string{
+	MacroCalories: "calories_per_serving",
+	MacroProtein:  "protein_per_serving",
+	MacroCarbs:    "carbs_per_serving",
+	MacroFat:      "fat_per_serving",
+	MacroFiber:    "fiber_per_serving",
+}
+
+// Macros são os totais de macronutrientes (valores dos ingredientes são por 100g)
+type Macros struct {
+	Calories float64 `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Carbs    float64 `json:"carbs"`
+	Fat      float64 `json:"fat"`
+	Fiber    float64 `json:"fiber"`
+}
+
+// SumMacros soma os macronutrientes dos ingredientes de uma receita
+// A quantidade de cada ingrediente é tratada em gramas (fator = quantidade / 100)
+func SumMacros(items []models.RecipeIngredient) Macros {
+	var total Macros
+	for _, item := range items {
+		factor := item.Quantity / 100.0
+		total.Calories += item.Ingredient.Calories * factor
+		total.Protein += item.Ingredient.Protein * factor
+		total.Carbs += item.Ingredient.Carbs * factor
+		total.Fat += item.Ingredient.Fat * factor
+		total.Fiber += item.Ingredient.Fiber * factor
+	}
+	return total
+}
+
+// Scale multiplica todos os valores pelo fator (ex.: total / porções da receita)
+func (m Macros) Scale(factor float64) Macros {
+	return Macros{
+		Calories: m.Calories * factor,
+		Protein:  m.Protein * factor,
+		Carbs:    m.Carbs * factor,
+		Fat:      m.Fat * factor,
+		Fiber:    m.Fiber * factor,
+	}
+}
+
+// Add soma outro conjunto de macronutrientes
+func (m Macros) Add(other Macros) Macros {
+	return Macros{
+		Calories: m.Calories + other.Calories,
+		Protein:  m.Protein + other.Protein,
+		Carbs:    m.Carbs + other.Carbs,
+		Fat:      m.Fat + other.Fat,
+		Fiber:    m.Fiber + other.Fiber,
+	}
+}
+
+// PerServing retorna os valores por porção pré-calculados da receita
+func PerServing(recipe *models.Recipe) Macros {
+	return Macros{
+		Calories: recipe.CaloriesPerServing,
+		Protein:  recipe.ProteinPerServing,
+		Carbs:    recipe.CarbsPerServing,
+		Fat:      recipe.FatPerServing,
+		Fiber:    recipe.FiberPerServing,
+	}
+}
+
+// ApplyTo copia os valores (por porção) para os campos da receita
+func (m Macros) ApplyTo(recipe *models.Recipe) {
+	recipe.CaloriesPerServing = m.Calories
+	recipe.ProteinPerServing = m.Protein
+	recipe.CarbsPerServing = m.Carbs
+	recipe.FatPerServing = m.Fat
+	recipe.FiberPerServing = m.Fiber
+}
+
+// Recompute recalcula e grava os valores por porção de uma receita a partir dos ingredientes e porções atuais
+// Deve ser chamado sempre que os ingredientes, as quantidades ou as porções da receita mudarem
+func Recompute(db *gorm.DB, recipeID uint) (Macros, error) {
+	var recipe models.Recipe
+	if err := db.Unscoped().Select("id", "servings").First(&recipe, recipeID).Error; err != nil {
+		return Macros{}, fmt.Errorf("erro ao carregar receita: %w", err)
+	}
+
+	var items []models.RecipeIngredient
+	if err := db.Preload("Ingredient").Where("recipe_id = ?", recipeID).Find(&items).Error; err != nil {
+		return Macros{}, fmt.Errorf("erro ao carregar ingredientes: %w", err)
+	}
+
+	servings := recipe.Servings
+	if servings < 1 {
+		servings = 1
+	}
+	perServing := SumMacros(items).Scale(1 / float64(servings))
+
+	if err := db.Model(&models.Recipe{}).Unscoped().Where("id = ?", recipeID).UpdateColumns(map[string]interface{}{
+		"calories_per_serving": perServing.Calories,
+		"protein_per_serving":  perServing.Protein,
+		"carbs_per_serving":    perServing.Carbs,
+		"fat_per_serving":      perServing.Fat,
+		"fiber_per_serving":    perServing.Fiber,
+	}).Error; err != nil {
+		return Macros{}, fmt.Errorf("erro ao gravar nutrição por porção: %w", err)
+	}
+	return perServing, nil
+}
+
+// RecomputeForIngredient recalcula os valores por porção de todas as receitas que usam o ingrediente
+// Usado quando os macronutrientes de um ingrediente do catálogo são alterados
+func RecomputeForIngredient(db *gorm.DB, ingredientID uint) error {
+	var recipeIDs []uint
+	if err := db.Model(&models.RecipeIngredient{}).
+		Where("ingredient_id = ?", ingredientID).
+		Distinct().
+		Pluck("recipe_id", &recipeIDs).Error; err != nil {
+		return fmt.Errorf("erro ao buscar receitas do ingrediente: %w", err)
+	}
+
+	for _, recipeID := range recipeIDs {
+		if _, err := Recompute(db, recipeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
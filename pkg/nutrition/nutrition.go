@@ -0,0 +1,126 @@
+package nutrition
+
+import (
+	"fmt"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+// StatusPartial indica que parte dos ingredientes não tem o nutriente medido:
+// o total é um limite inferior
+const StatusPartial = "partial"
+
+// NutrientAmount é o total de um nutriente na receita
+// Total e PerServing são nulos quando nenhum ingrediente tem valor medido
+type NutrientAmount struct {
+	Nutrient      string   `json:"nutrient"`
+	Name          string   `json:"name"`
+	Unit          string   `json:"unit"`
+	Total         *float64 `json:"total"`
+	PerServing    *float64 `json:"per_serving"`
+	Status        string   `json:"status"`                   // measured, partial, trace ou not_measured
+	ContainsTrace bool     `json:"contains_trace,omitempty"` // Algum ingrediente tem apenas traços
+}
+
+// Profile soma o perfil estendido de nutrientes dos ingredientes da receita
+// Os ingredientes devem vir com Ingredient.Nutrients carregado (Preload("Ingredient.Nutrients"))
+//
+// Regras de status, por nutriente:
+//   - measured: todos os ingredientes têm valor medido (traços contam como desprezíveis)
+//   - partial: há valores medidos, mas algum ingrediente não foi analisado
+//   - trace: nenhum valor medido, apenas traços
+//   - not_measured: nenhum ingrediente tem o nutriente analisado
+func Profile(items []models.RecipeIngredient, servings int) []NutrientAmount {
+	if servings < 1 {
+		servings = 1
+	}
+
+	// Índice: ingrediente -> nutriente -> medição
+	byIngredient := make(map[uint]map[string]models.IngredientNutrient, len(items))
+	for _, item := range items {
+		values := make(map[string]models.IngredientNutrient, len(item.Ingredient.Nutrients))
+		for _, n := range item.Ingredient.Nutrients {
+			values[n.Nutrient] = n
+		}
+		byIngredient[item.IngredientID] = values
+	}
+
+	profile := make([]NutrientAmount, 0, len(models.Nutrients))
+	for _, nutrient := range models.Nutrients {
+		amount := NutrientAmount{Nutrient: nutrient.Key, Name: nutrient.Name, Unit: nutrient.Unit}
+
+		var total float64
+		measured, missing := 0, 0
+		for _, item := range items {
+			value, ok := byIngredient[item.IngredientID][nutrient.Key]
+			switch {
+			case ok && value.Status == models.NutrientStatusMeasured && value.Amount != nil:
+				total += *value.Amount * item.Quantity / 100.0
+				measured++
+			case ok && value.Status == models.NutrientStatusTrace:
+				amount.ContainsTrace = true
+			default:
+				missing++
+			}
+		}
+
+		switch {
+		case len(items) == 0 || (measured == 0 && !amount.ContainsTrace):
+			amount.Status = models.NutrientStatusNotMeasured
+		case missing > 0:
+			amount.Status = StatusPartial
+		case measured == 0:
+			amount.Status = models.NutrientStatusTrace
+		default:
+			amount.Status = models.NutrientStatusMeasured
+		}
+
+		if measured > 0 {
+			perServing := total / float64(servings)
+			amount.Total = &total
+			amount.PerServing = &perServing
+		}
+
+		profile = append(profile, amount)
+	}
+
+	return profile
+}
+
+// ValidateNutrients verifica se as medições informadas usam nutrientes conhecidos e status coerentes
+func ValidateNutrients(nutrients []models.IngredientNutrient) error {
+	seen := make(map[string]bool, len(nutrients))
+	for _, n := range nutrients {
+		if !isKnownNutrient(n.Nutrient) {
+			return fmt.Errorf("nutriente desconhecido: %s", n.Nutrient)
+		}
+		if seen[n.Nutrient] {
+			return fmt.Errorf("nutriente repetido: %s", n.Nutrient)
+		}
+		seen[n.Nutrient] = true
+
+		switch n.Status {
+		case "", models.NutrientStatusMeasured:
+			if n.Amount == nil || *n.Amount < 0 {
+				return fmt.Errorf("nutriente %s medido precisa de amount maior ou igual a zero", n.Nutrient)
+			}
+		case models.NutrientStatusTrace, models.NutrientStatusNotMeasured:
+			if n.Amount != nil {
+				return fmt.Errorf("nutriente %s com status %s não pode ter amount", n.Nutrient, n.Status)
+			}
+		default:
+			return fmt.Errorf("status inválido para %s. Use measured, trace ou not_measured", n.Nutrient)
+		}
+	}
+	return nil
+}
+
+// isKnownNutrient verifica se a chave pertence ao perfil estendido
+func isKnownNutrient(key string) bool {
+	for _, n := range models.Nutrients {
+		if n.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package pantry
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/units"
+)
+
+// epsilon evita lotes com sobras de arredondamento (ex.: 0.0000001 g)
+const epsilon = 1e-6
+
+// Consumption é o resultado do consumo de um ingrediente da despensa
+// Missing é o que faltou na despensa (na mesma unidade base)
+type Consumption struct {
+	IngredientID uint    `json:"ingredient_id"`
+	Unit         string  `json:"unit"`
+	Requested    float64 `json:"requested"`
+	Consumed     float64 `json:"consumed"`
+	Missing      float64 `json:"missing"`
+}
+
+// Consume retira a quantidade dos lotes do ingrediente, começando pelo que vence antes (lotes sem validade por último)
+// Só lotes na mesma unidade base são usados; lotes zerados são removidos
+func Consume(tx *gorm.DB, userID, ingredientID uint, quantity float64, unit string) (Consumption, error) {
+	quantity, unit = units.Normalize(quantity, unit)
+	result := Consumption{IngredientID: ingredientID, Unit: unit, Requested: round(quantity)}
+
+	var lots []models.PantryItem
+	if err := tx.Where("user_id = ? AND ingredient_id = ? AND unit = ?", userID, ingredientID, unit).
+		Order("expires_on IS NULL, expires_on ASC, id ASC").
+		Find(&lots).Error; err != nil {
+		return result, err
+	}
+
+	remaining := quantity
+	for _, lot := range lots {
+		if remaining <= epsilon {
+			break
+		}
+
+		used := math.Min(lot.Quantity, remaining)
+		remaining -= used
+		if lot.Quantity-used <= epsilon {
+			if err := tx.Delete(&lot).Error; err != nil {
+				return result, err
+			}
+			continue
+		}
+		if err := tx.Model(&lot).Update("quantity", lot.Quantity-used).Error; err != nil {
+			return result, err
+		}
+	}
+
+	if remaining < epsilon {
+		remaining = 0
+	}
+	result.Consumed = round(quantity - remaining)
+	result.Missing = round(remaining)
+	return result, nil
+}
+
+// ConsumeRecipe retira da despensa os ingredientes da receita multiplicados pelo fator de porções
+// O mesmo ingrediente listado mais de uma vez é somado antes do consumo
+func ConsumeRecipe(tx *gorm.DB, userID uint, items []models.RecipeIngredient, factor float64) ([]Consumption, error) {
+	type key struct {
+		ingredientID uint
+		unit         string
+	}
+
+	totals := make(map[key]float64)
+	var order []key
+	for _, item := range items {
+		quantity, unit := units.Normalize(item.Quantity*factor, item.Unit)
+		k := key{item.IngredientID, unit}
+		if _, ok := totals[k]; !ok {
+			order = append(order, k)
+		}
+		totals[k] += quantity
+	}
+
+	consumed := make([]Consumption, 0, len(order))
+	for _, k := range order {
+		c, err := Consume(tx, userID, k.ingredientID, totals[k], k.unit)
+		if err != nil {
+			return nil, err
+		}
+		consumed = append(consumed, c)
+	}
+	return consumed, nil
+}
+
+// RecipeMatch é uma receita sugerida para aproveitar ingredientes que vão vencer
+type RecipeMatch struct {
+	Recipe              models.Recipe `json:"recipe"`
+	ExpiringIngredients []string      `json:"expiring_ingredients"` // Nomes, na ordem de validade
+	SoonestExpiry       string        `json:"soonest_expiry"`
+	PantryCoverage      float64       `json:"pantry_coverage"` // Fração dos ingredientes da receita que há na despensa
+}
+
+// RankRecipes ordena as receitas que usam ingredientes da despensa com validade entre today e until (inclusive)
+// Critérios: mais ingredientes vencendo, validade mais próxima e maior cobertura pela despensa
+// Considera receitas publicadas públicas e as do próprio usuário
+func RankRecipes(db *gorm.DB, userID uint, today, until time.Time, limit int) ([]RecipeMatch, error) {
+	var lots []models.PantryItem
+	if err := db.Where("user_id = ?", userID).Preload("Ingredient").Find(&lots).Error; err != nil {
+		return nil, err
+	}
+
+	from, to := today.Format(models.DateLayout), until.Format(models.DateLayout)
+	inPantry := make(map[uint]bool, len(lots))
+	expiring := make(map[uint]string) // ingrediente -> validade mais próxima
+	names := make(map[uint]string)
+	for _, lot := range lots {
+		inPantry[lot.IngredientID] = true
+		if lot.Ingredient != nil {
+			names[lot.IngredientID] = lot.Ingredient.Name
+		}
+		if lot.ExpiresOn == nil || *lot.ExpiresOn < from || *lot.ExpiresOn > to {
+			continue
+		}
+		if current, ok := expiring[lot.IngredientID]; !ok || *lot.ExpiresOn < current {
+			expiring[lot.IngredientID] = *lot.ExpiresOn
+		}
+	}
+	if len(expiring) == 0 {
+		return []RecipeMatch{}, nil
+	}
+
+	expiringIDs := make([]uint, 0, len(expiring))
+	for id := range expiring {
+		expiringIDs = append(expiringIDs, id)
+	}
+
+	var recipes []models.Recipe
+	if err := db.Where("id IN (?)", db.Model(&models.RecipeIngredient{}).Select("recipe_id").Where("ingredient_id IN ?", expiringIDs)).
+		Where("(recipes.status = ? AND recipes.visibility = ?) OR recipes.user_id = ?",
+			models.RecipeStatusPublished, models.RecipeVisibilityPublic, userID).
+		Preload("Ingredients").
+		Find(&recipes).Error; err != nil {
+		return nil, err
+	}
+
+	matches := make([]RecipeMatch, 0, len(recipes))
+	for _, recipe := range recipes {
+		match := RecipeMatch{ExpiringIngredients: []string{}}
+
+		seen := make(map[uint]bool, len(recipe.Ingredients))
+		covered := 0
+		var used []uint
+		for _, item := range recipe.Ingredients {
+			if seen[item.IngredientID] {
+				continue
+			}
+			seen[item.IngredientID] = true
+			if inPantry[item.IngredientID] {
+				covered++
+			}
+			if _, ok := expiring[item.IngredientID]; ok {
+				used = append(used, item.IngredientID)
+			}
+		}
+
+		sort.Slice(used, func(i, j int) bool { return expiring[used[i]] < expiring[used[j]] })
+		for _, id := range used {
+			match.ExpiringIngredients = append(match.ExpiringIngredients, names[id])
+		}
+		match.SoonestExpiry = expiring[used[0]]
+		match.PantryCoverage = round(float64(covered) / float64(len(seen)))
+
+		recipe.Ingredients = nil
+		match.Recipe = recipe
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if len(a.ExpiringIngredients) != len(b.ExpiringIngredients) {
+			return len(a.ExpiringIngredients) > len(b.ExpiringIngredients)
+		}
+		if a.SoonestExpiry != b.SoonestExpiry {
+			return a.SoonestExpiry < b.SoonestExpiry
+		}
+		if a.PantryCoverage != b.PantryCoverage {
+			return a.PantryCoverage > b.PantryCoverage
+		}
+		return a.Recipe.ID < b.Recipe.ID
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// round arredonda para duas casas decimais
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,39 @@
+package privacy
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+const (
+	// DeletedUserName substitui o nome de contas excluídas ou eliminadas
+	DeletedUserName = "Usuário removido"
+	// deletedUserEmailHost é um domínio reservado (RFC 2606) que nunca recebe e-mails
+	deletedUserEmailHost = "anonimizado.invalid"
+)
+
+// AnonymizeUser remove dados pessoais do usuário e os comentários das suas avaliações
+// O e-mail é substituído para liberar o endereço para um novo cadastro
+func AnonymizeUser(tx *gorm.DB, user *models.User) error {
+	// UpdateColumn evita os hooks de Rating (que validam o score de um modelo vazio)
+	if err := tx.Model(&models.Rating{}).
+		Where("user_id = ?", user.ID).
+		UpdateColumn("comment", "").Error; err != nil {
+		return err
+	}
+
+	return tx.Model(user).Updates(map[string]interface{}{
+		"name":                    DeletedUserName,
+		"email":                   fmt.Sprintf("removido-%d@%s", user.ID, deletedUserEmailHost),
+		"password":                "",
+		"bio":                     "",
+		"avatar_url":              "",
+		"avatar_public_id":        "",
+		"pending_email":           "",
+		"email_change_token_hash": "",
+		"email_change_expires_at": nil,
+	}).Error
+}
@@ -0,0 +1,305 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/analytics"
+	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+	"github.com/davidsonmarra/receitas-app/pkg/storage"
+)
+
+// ErasurePolicyVersion identifica a política de eliminação aplicada (ver LGPD_IMPLEMENTATION.md)
+const ErasurePolicyVersion = "2026-10"
+
+// EraseUser elimina os dados pessoais do usuário conforme a política documentada:
+//
+//   - receitas do usuário, seus ingredientes, passos, revisões e as avaliações recebidas: excluídas definitivamente
+//   - sessões, chaves de API, exportações e análises de alimentos: excluídas definitivamente
+//   - avaliações feitas em receitas de terceiros: mantidas sem comentário e sem atribuição
+//   - forks de terceiros das receitas do usuário: mantidos, sem o título da original na atribuição
+//   - tags sugeridas pelo usuário: mantidas sem atribuição
+//   - planejamentos de refeições do usuário e suas participações em planos de terceiros: excluídos definitivamente
+//   - refeições planejadas por terceiros com receitas do usuário: excluídas junto com as receitas
+//   - listas de compras do usuário: excluídas definitivamente
+//   - despensa do usuário: excluída definitivamente
+//   - metas de nutrição do usuário: excluídas definitivamente
+//   - usuário: anonimizado e mantido como registro removido (preserva a integridade das avaliações)
+//
+// A eliminação é registrada em data_erasures sem dados pessoais.
+func EraseUser(ctx context.Context, user *models.User) (*models.DataErasure, error) {
+	requestedAt := time.Now()
+	emailHash := auth.HashString(strings.ToLower(user.Email))
+	summary := make(map[string]int64)
+
+	// Imagens no Cloudinary só podem ser removidas após o commit
+	var imagePublicIDs []string
+	if user.AvatarPublicID != "" {
+		imagePublicIDs = append(imagePublicIDs, user.AvatarPublicID)
+	}
+
+	var record models.DataErasure
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var recipes []models.Recipe
+		if err := tx.Unscoped().Select("id", "image_public_id").
+			Where("user_id = ?", user.ID).
+			Find(&recipes).Error; err != nil {
+			return err
+		}
+
+		recipeIDs := make([]uint, 0, len(recipes))
+		for _, recipe := range recipes {
+			recipeIDs = append(recipeIDs, recipe.ID)
+			if recipe.ImagePublicID != "" {
+				imagePublicIDs = append(imagePublicIDs, recipe.ImagePublicID)
+			}
+		}
+
+		if len(recipeIDs) > 0 {
+			result := tx.Unscoped().Where("recipe_id IN ?", recipeIDs).Delete(&models.Rating{})
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["ratings_received"] = result.RowsAffected
+
+			var steps []models.RecipeStep
+			if err := tx.Select("id", "image_public_id").Where("recipe_id IN ?", recipeIDs).Find(&steps).Error; err != nil {
+				return err
+			}
+			stepIDs := make([]uint, 0, len(steps))
+			for _, step := range steps {
+				stepIDs = append(stepIDs, step.ID)
+				if step.ImagePublicID != "" {
+					imagePublicIDs = append(imagePublicIDs, step.ImagePublicID)
+				}
+			}
+			if len(stepIDs) > 0 {
+				if err := tx.Exec("DELETE FROM recipe_step_ingredients WHERE recipe_step_id IN ?", stepIDs).Error; err != nil {
+					return err
+				}
+				result = tx.Where("id IN ?", stepIDs).Delete(&models.RecipeStep{})
+				if result.Error != nil {
+					return result.Error
+				}
+				summary["recipe_steps"] = result.RowsAffected
+			}
+
+			if err := tx.Exec("DELETE FROM recipe_tags WHERE recipe_id IN ?", recipeIDs).Error; err != nil {
+				return err
+			}
+
+			// Refeições planejadas (inclusive em planos de terceiros) com as receitas do usuário
+			result = tx.Where("recipe_id IN ?", recipeIDs).Delete(&models.MealPlanSlot{})
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["meal_plan_slots"] = result.RowsAffected
+
+			result = tx.Where("recipe_id IN ?", recipeIDs).Delete(&models.RecipeRevision{})
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["recipe_revisions"] = result.RowsAffected
+
+			result = tx.Where("recipe_id IN ?", recipeIDs).Delete(&models.RecipeIngredient{})
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["recipe_ingredients"] = result.RowsAffected
+
+			// Registros de "fiz esta receita" de terceiros nas receitas do usuário, com as fotos
+			var recipeCookLogs []models.CookLog
+			if err := tx.Select("id", "photo_public_id").Where("recipe_id IN ?", recipeIDs).Find(&recipeCookLogs).Error; err != nil {
+				return err
+			}
+			for _, cookLog := range recipeCookLogs {
+				if cookLog.PhotoPublicID != "" {
+					imagePublicIDs = append(imagePublicIDs, cookLog.PhotoPublicID)
+				}
+			}
+			result = tx.Where("recipe_id IN ?", recipeIDs).Delete(&models.CookLog{})
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["cook_logs_received"] = result.RowsAffected
+
+			// Estatísticas de engajamento das receitas do usuário
+			if err := tx.Where("recipe_id IN ?", recipeIDs).Delete(&models.RecipeEvent{}).Error; err != nil {
+				return err
+			}
+			result = tx.Where("recipe_id IN ?", recipeIDs).Delete(&models.RecipeDailyStat{})
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["recipe_daily_stats"] = result.RowsAffected
+
+			// Forks de terceiros mantêm o vínculo e o autor (anonimizado), sem o título da original
+			result = tx.Unscoped().Model(&models.Recipe{}).
+				Where("forked_from_id IN ? AND id NOT IN ?", recipeIDs, recipeIDs).
+				UpdateColumn("forked_from_title", "")
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["fork_attributions_anonymized"] = result.RowsAffected
+
+			result = tx.Unscoped().Where("id IN ?", recipeIDs).Delete(&models.Recipe{})
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["recipes"] = result.RowsAffected
+		}
+
+		// Tabelas excluídas definitivamente
+		for name, model := range map[string]interface{}{
+			"refresh_tokens": &models.RefreshToken{},
+			"api_keys":       &models.APIKey{},
+			"data_exports":   &models.DataExport{},
+			"food_analyses":  &models.FoodAnalysis{},
+		} {
+			result := tx.Unscoped().Where("user_id = ?", user.ID).Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+			summary[name] = result.RowsAffected
+		}
+
+		// Planejamentos do usuário (com refeições e membros) e participações em planos de terceiros
+		var planIDs []uint
+		if err := tx.Model(&models.MealPlan{}).Where("user_id = ?", user.ID).Pluck("id", &planIDs).Error; err != nil {
+			return err
+		}
+		if len(planIDs) > 0 {
+			if err := tx.Where("meal_plan_id IN ?", planIDs).Delete(&models.MealPlanSlot{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("meal_plan_id IN ?", planIDs).Delete(&models.MealPlanMember{}).Error; err != nil {
+				return err
+			}
+			result := tx.Where("id IN ?", planIDs).Delete(&models.MealPlan{})
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["meal_plans"] = result.RowsAffected
+
+			// Listas de terceiros geradas a partir desses planos são mantidas, sem a origem
+			if err := tx.Model(&models.ShoppingList{}).Where("meal_plan_id IN ?", planIDs).UpdateColumn("meal_plan_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Where("user_id = ?", user.ID).Delete(&models.MealPlanMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		summary["meal_plan_memberships"] = result.RowsAffected
+
+		// Listas de compras do usuário e seus itens
+		var listIDs []uint
+		if err := tx.Model(&models.ShoppingList{}).Where("user_id = ?", user.ID).Pluck("id", &listIDs).Error; err != nil {
+			return err
+		}
+		if len(listIDs) > 0 {
+			if err := tx.Where("shopping_list_id IN ?", listIDs).Delete(&models.ShoppingListItem{}).Error; err != nil {
+				return err
+			}
+			result := tx.Where("id IN ?", listIDs).Delete(&models.ShoppingList{})
+			if result.Error != nil {
+				return result.Error
+			}
+			summary["shopping_lists"] = result.RowsAffected
+		}
+
+		// Registros de "fiz esta receita" do usuário, com as fotos
+		var cookLogs []models.CookLog
+		if err := tx.Select("id", "photo_public_id").Where("user_id = ?", user.ID).Find(&cookLogs).Error; err != nil {
+			return err
+		}
+		for _, cookLog := range cookLogs {
+			if cookLog.PhotoPublicID != "" {
+				imagePublicIDs = append(imagePublicIDs, cookLog.PhotoPublicID)
+			}
+		}
+		result = tx.Where("user_id = ?", user.ID).Delete(&models.CookLog{})
+		if result.Error != nil {
+			return result.Error
+		}
+		summary["cook_logs"] = result.RowsAffected
+
+		// Eventos de deduplicação gerados pelo usuário em receitas de terceiros (os totais diários são anônimos)
+		result = tx.Where("actor = ?", analytics.UserActor(user.ID)).Delete(&models.RecipeEvent{})
+		if result.Error != nil {
+			return result.Error
+		}
+		summary["recipe_events"] = result.RowsAffected
+
+		// Despensa do usuário
+		result = tx.Where("user_id = ?", user.ID).Delete(&models.PantryItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		summary["pantry_items"] = result.RowsAffected
+
+		// Metas de nutrição
+		result = tx.Where("user_id = ?", user.ID).Delete(&models.NutritionGoal{})
+		if result.Error != nil {
+			return result.Error
+		}
+		summary["nutrition_goals"] = result.RowsAffected
+
+		// Sugestões de tags deixam de apontar para o usuário
+		if err := tx.Model(&models.Tag{}).Where("suggested_by_id = ?", user.ID).UpdateColumn("suggested_by_id", nil).Error; err != nil {
+			return err
+		}
+
+		var anonymizedRatings int64
+		if err := tx.Model(&models.Rating{}).Where("user_id = ?", user.ID).Count(&anonymizedRatings).Error; err != nil {
+			return err
+		}
+		summary["ratings_anonymized"] = anonymizedRatings
+
+		if err := AnonymizeUser(tx, user); err != nil {
+			return err
+		}
+		if err := tx.Delete(user).Error; err != nil {
+			return err
+		}
+
+		summaryJSON, err := json.Marshal(summary)
+		if err != nil {
+			return err
+		}
+
+		record = models.DataErasure{
+			UserID:        user.ID,
+			EmailHash:     emailHash,
+			PolicyVersion: ErasurePolicyVersion,
+			Summary:       string(summaryJSON),
+			RequestedAt:   requestedAt,
+			CompletedAt:   time.Now(),
+		}
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao eliminar dados do usuário: %w", err)
+	}
+
+	// Remover imagens (best effort)
+	if len(imagePublicIDs) > 0 {
+		if imageService, err := storage.ServiceFactory(); err == nil {
+			for _, publicID := range imagePublicIDs {
+				if err := imageService.DeleteImage(ctx, publicID); err != nil {
+					log.WarnCtx(ctx, "failed to delete image during erasure", "user_id", user.ID, "public_id", publicID, "error", err)
+				}
+			}
+		}
+	}
+
+	return &record, nil
+}
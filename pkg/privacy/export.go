@@ -0,0 +1,390 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+)
+
+// ExportTTL é o prazo para baixar uma exportação pronta
+const ExportTTL = 7 * 24 * time.Hour
+
+// ExportProcessingTimeout é o tempo máximo de geração; depois disso a exportação é considerada falha
+// Cobre gerações interrompidas por queda ou reinício do servidor
+const ExportProcessingTimeout = 15 * time.Minute
+
+var (
+	// ErrExportNotFound indica que a exportação não existe ou pertence a outro usuário
+	ErrExportNotFound = errors.New("exportação não encontrada")
+	// ErrExportInProgress indica que já existe uma exportação sendo gerada
+	ErrExportInProgress = errors.New("exportação já em andamento")
+	// ErrExportNotReady indica que o arquivo ainda não foi gerado (ou falhou)
+	ErrExportNotReady = errors.New("exportação não está pronta")
+	// ErrExportAlreadyDownloaded indica que o arquivo já foi baixado
+	ErrExportAlreadyDownloaded = errors.New("exportação já foi baixada")
+	// ErrExportExpired indica que o prazo para download passou
+	ErrExportExpired = errors.New("exportação expirada")
+)
+
+// exportProfile contém os dados de perfil, incluindo campos privados
+type exportProfile struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	PendingEmail string    `json:"pending_email,omitempty"`
+	Role         string    `json:"role"`
+	Bio          string    `json:"bio,omitempty"`
+	AvatarURL    string    `json:"avatar_url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// exportAnalysis representa uma análise de alimentos com o resultado em JSON
+type exportAnalysis struct {
+	JobID     string          `json:"job_id"`
+	Status    string          `json:"status"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// exportReadme descreve o conteúdo do arquivo para o titular dos dados
+const exportReadme = `Exportação de dados pessoais - Receitas App
+
+Este arquivo contém todos os dados vinculados à sua conta (LGPD, art. 18, II e V):
+
+  perfil.json            dados cadastrais
+  receitas.json          receitas criadas por você, com ingredientes
+  avaliacoes.json        avaliações feitas por você
+  dispositivos.json      sessões (dispositivos) registradas no login
+  chaves_api.json        chaves de API (sem o segredo, que nunca é armazenado)
+  analises.json          análises de alimentos por imagem
+  planos.json            planejamentos de refeições criados por você, com as refeições
+  listas_compras.json    listas de compras, com os itens
+  despensa.json          itens da despensa, com quantidades e validades
+  metas_nutricao.json    metas diárias de nutrição
+  preparos.json          registros de "fiz esta receita", com observações, ajustes e fotos
+
+Senhas e tokens são armazenados apenas como hash e não fazem parte da exportação.
+`
+
+// RequestExport cria uma exportação e inicia a geração do arquivo em background
+func RequestExport(userID uint) (*models.DataExport, error) {
+	db := database.DB
+
+	if err := failStaleExports(db, userID); err != nil {
+		return nil, err
+	}
+
+	var inProgress int64
+	if err := db.Model(&models.DataExport{}).
+		Where("user_id = ? AND status = ?", userID, models.DataExportStatusProcessing).
+		Count(&inProgress).Error; err != nil {
+		return nil, fmt.Errorf("erro ao verificar exportações: %w", err)
+	}
+	if inProgress > 0 {
+		return nil, ErrExportInProgress
+	}
+
+	export := models.DataExport{
+		UserID: userID,
+		Status: models.DataExportStatusProcessing,
+	}
+	if err := db.Create(&export).Error; err != nil {
+		return nil, fmt.Errorf("erro ao criar exportação: %w", err)
+	}
+
+	// Handle capturado antes da goroutine: não depende do global após o retorno
+	go buildExport(db, export.ID, userID)
+
+	return &export, nil
+}
+
+// GetExport retorna os metadados de uma exportação do usuário (sem o arquivo)
+func GetExport(userID, exportID uint) (*models.DataExport, error) {
+	var export models.DataExport
+	err := database.DB.Omit("archive").
+		Where("id = ? AND user_id = ?", exportID, userID).
+		First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar exportação: %w", err)
+	}
+
+	return &export, nil
+}
+
+// ConsumeExport retorna o arquivo de uma exportação pronta e o descarta
+// A marcação é condicional, então downloads concorrentes resultam em um único sucesso
+func ConsumeExport(userID, exportID uint) ([]byte, error) {
+	var export models.DataExport
+	err := database.DB.Where("id = ? AND user_id = ?", exportID, userID).First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar exportação: %w", err)
+	}
+
+	switch export.Status {
+	case models.DataExportStatusDownloaded:
+		return nil, ErrExportAlreadyDownloaded
+	case models.DataExportStatusExpired:
+		return nil, ErrExportExpired
+	case models.DataExportStatusReady:
+	default:
+		return nil, ErrExportNotReady
+	}
+
+	if export.IsExpired() {
+		return nil, ErrExportExpired
+	}
+
+	result := database.DB.Model(&models.DataExport{}).
+		Where("id = ? AND status = ?", export.ID, models.DataExportStatusReady).
+		Updates(map[string]interface{}{
+			"status":        models.DataExportStatusDownloaded,
+			"downloaded_at": time.Now(),
+			"archive":       nil,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao marcar exportação como baixada: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrExportAlreadyDownloaded
+	}
+
+	return export.Archive, nil
+}
+
+// buildExport gera o arquivo ZIP e atualiza o status da exportação
+func buildExport(db *gorm.DB, exportID, userID uint) {
+	archive, err := buildArchive(db, userID)
+	if err != nil {
+		log.Error("failed to build data export", "export_id", exportID, "user_id", userID, "error", err)
+		db.Model(&models.DataExport{}).Where("id = ?", exportID).Updates(map[string]interface{}{
+			"status": models.DataExportStatusFailed,
+			"error":  "Erro ao gerar exportação",
+		})
+		return
+	}
+
+	expiresAt := time.Now().Add(ExportTTL)
+	if err := db.Model(&models.DataExport{}).Where("id = ?", exportID).Updates(map[string]interface{}{
+		"status":     models.DataExportStatusReady,
+		"archive":    archive,
+		"size_bytes": len(archive),
+		"expires_at": expiresAt,
+	}).Error; err != nil {
+		log.Error("failed to save data export", "export_id", exportID, "user_id", userID, "error", err)
+		return
+	}
+
+	log.Info("data export ready", "export_id", exportID, "user_id", userID, "size_bytes", len(archive))
+}
+
+// buildArchive coleta os dados do usuário e monta o ZIP
+func buildArchive(db *gorm.DB, userID uint) ([]byte, error) {
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	var recipes []models.Recipe
+	if err := db.Where("user_id = ?", userID).
+		Preload("Ingredients.Ingredient").
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("\"order\" ASC, id ASC")
+		}).
+		Order("id").
+		Find(&recipes).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar receitas: %w", err)
+	}
+
+	var ratings []models.Rating
+	if err := db.Where("user_id = ?", userID).Order("id").Find(&ratings).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar avaliações: %w", err)
+	}
+
+	var devices []models.RefreshToken
+	if err := db.Unscoped().Where("user_id = ?", userID).Order("created_at").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar dispositivos: %w", err)
+	}
+
+	var apiKeys []models.APIKey
+	if err := db.Unscoped().Where("user_id = ?", userID).Order("id").Find(&apiKeys).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar chaves de API: %w", err)
+	}
+
+	var analyses []models.FoodAnalysis
+	if err := db.Where("user_id = ?", userID).Order("id").Find(&analyses).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar análises: %w", err)
+	}
+
+	var plans []models.MealPlan
+	if err := db.Where("user_id = ?", userID).
+		Preload("Slots", func(db *gorm.DB) *gorm.DB {
+			return db.Order("date ASC, id ASC")
+		}).
+		Order("id").
+		Find(&plans).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar planejamentos: %w", err)
+	}
+
+	var shoppingLists []models.ShoppingList
+	if err := db.Where("user_id = ?", userID).Preload("Items").Order("id").Find(&shoppingLists).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar listas de compras: %w", err)
+	}
+
+	var pantryItems []models.PantryItem
+	if err := db.Where("user_id = ?", userID).Preload("Ingredient").Order("id").Find(&pantryItems).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar despensa: %w", err)
+	}
+
+	var nutritionGoals []models.NutritionGoal
+	if err := db.Where("user_id = ?", userID).Find(&nutritionGoals).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar metas de nutrição: %w", err)
+	}
+
+	var cookLogs []models.CookLog
+	if err := db.Where("user_id = ?", userID).Order("id").Find(&cookLogs).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar registros de preparo: %w", err)
+	}
+
+	exportedAnalyses := make([]exportAnalysis, 0, len(analyses))
+	for _, a := range analyses {
+		item := exportAnalysis{JobID: a.JobID, Status: a.Status, Error: a.Error, CreatedAt: a.CreatedAt}
+		if a.Result != "" {
+			item.Result = json.RawMessage(a.Result)
+		}
+		exportedAnalyses = append(exportedAnalyses, item)
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"perfil.json", exportProfile{
+			ID:           user.ID,
+			Name:         user.Name,
+			Email:        user.Email,
+			PendingEmail: user.PendingEmail,
+			Role:         user.Role,
+			Bio:          user.Bio,
+			AvatarURL:    user.AvatarURL,
+			CreatedAt:    user.CreatedAt,
+			UpdatedAt:    user.UpdatedAt,
+		}},
+		{"receitas.json", recipes},
+		{"avaliacoes.json", ratings},
+		{"dispositivos.json", devices},
+		{"chaves_api.json", apiKeys},
+		{"analises.json", exportedAnalyses},
+		{"planos.json", plans},
+		{"listas_compras.json", shoppingLists},
+		{"despensa.json", pantryItems},
+		{"metas_nutricao.json", nutritionGoals},
+		{"preparos.json", cookLogs},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	readme, err := zw.Create("LEIA-ME.txt")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := readme.Write([]byte(exportReadme)); err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(f.data); err != nil {
+			return nil, fmt.Errorf("erro ao serializar %s: %w", f.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// failStaleExports marca como falhas as exportações em processamento há mais de ExportProcessingTimeout
+// userID 0 considera todos os usuários
+func failStaleExports(db *gorm.DB, userID uint) error {
+	query := db.Model(&models.DataExport{}).
+		Where("status = ? AND created_at < ?", models.DataExportStatusProcessing, time.Now().Add(-ExportProcessingTimeout))
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status": models.DataExportStatusFailed,
+		"error":  "Geração interrompida",
+	})
+	if result.Error != nil {
+		return fmt.Errorf("erro ao encerrar exportações interrompidas: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		log.Warn("exportações interrompidas marcadas como falhas", "count", result.RowsAffected)
+	}
+	return nil
+}
+
+// CleanupExpiredExports descarta arquivos não baixados dentro do prazo
+// e encerra exportações cuja geração foi interrompida
+func CleanupExpiredExports() error {
+	if err := failStaleExports(database.DB, 0); err != nil {
+		return err
+	}
+
+	result := database.DB.Model(&models.DataExport{}).
+		Where("status = ? AND expires_at < ?", models.DataExportStatusReady, time.Now()).
+		Updates(map[string]interface{}{
+			"status":  models.DataExportStatusExpired,
+			"archive": nil,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("erro ao limpar exportações expiradas: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		log.Info("exportações expiradas descartadas", "count", result.RowsAffected)
+	}
+
+	return nil
+}
+
+// StartDataExportCleanup inicia job em background para descartar exportações expiradas
+func StartDataExportCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := CleanupExpiredExports(); err != nil {
+				log.Error("erro ao limpar exportações expiradas", "error", err)
+			}
+		}
+	}()
+	log.Info("job de limpeza de exportações iniciado", "interval", interval)
+}
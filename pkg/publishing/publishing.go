@@ -0,0 +1,152 @@
+package publishing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+)
+
+// IncompleteError indica que a receita não atende aos requisitos de publicação
+type IncompleteError struct {
+	Missing []string
+}
+
+func (e *IncompleteError) Error() string {
+	return "receita incompleta, falta: " + strings.Join(e.Missing, ", ")
+}
+
+// CheckCompleteness verifica se a receita pode ser publicada:
+// pelo menos um ingrediente, modo de preparo preenchido e imagem
+func CheckCompleteness(db *gorm.DB, recipe *models.Recipe) error {
+	var missing []string
+
+	var ingredientCount int64
+	if err := db.Model(&models.RecipeIngredient{}).
+		Where("recipe_id = ?", recipe.ID).
+		Count(&ingredientCount).Error; err != nil {
+		return fmt.Errorf("erro ao contar ingredientes: %w", err)
+	}
+	if ingredientCount == 0 {
+		missing = append(missing, "ingredientes")
+	}
+
+	if strings.TrimSpace(recipe.Instructions) == "" {
+		missing = append(missing, "modo de preparo")
+	}
+
+	if recipe.ImageURL == "" {
+		missing = append(missing, "imagem")
+	}
+
+	if len(missing) > 0 {
+		return &IncompleteError{Missing: missing}
+	}
+	return nil
+}
+
+// Publish marca a receita como publicada, cancelando qualquer agendamento
+// A data da primeira publicação é preservada em republicações
+func Publish(db *gorm.DB, recipe *models.Recipe, now time.Time) error {
+	_, err := publish(db.Model(recipe), recipe, now)
+	return err
+}
+
+// PublishScheduled publica a receita apenas se ela ainda é um rascunho com agendamento vencido
+// Retorna false, sem alterar nada, quando a receita foi arquivada, reagendada ou teve o agendamento
+// cancelado depois de carregada
+func PublishScheduled(db *gorm.DB, recipe *models.Recipe, now time.Time) (bool, error) {
+	query := db.Model(recipe).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", models.RecipeStatusDraft, now)
+	return publish(query, recipe, now)
+}
+
+// publish aplica a publicação às linhas selecionadas por query e atualiza a receita em memória
+func publish(query *gorm.DB, recipe *models.Recipe, now time.Time) (bool, error) {
+	updates := map[string]interface{}{
+		"status":     models.RecipeStatusPublished,
+		"publish_at": nil,
+	}
+	if recipe.PublishedAt == nil {
+		updates["published_at"] = now
+	}
+
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	recipe.Status = models.RecipeStatusPublished
+	recipe.PublishAt = nil
+	if recipe.PublishedAt == nil {
+		recipe.PublishedAt = &now
+	}
+	return true, nil
+}
+
+// PublishScheduledRecipes publica os rascunhos cujo agendamento já venceu
+// Receitas que deixaram de atender aos requisitos permanecem como rascunho e têm o agendamento cancelado
+func PublishScheduledRecipes() error {
+	db := database.DB
+	now := time.Now()
+
+	var recipes []models.Recipe
+	if err := db.Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", models.RecipeStatusDraft, now).
+		Find(&recipes).Error; err != nil {
+		return fmt.Errorf("erro ao buscar receitas agendadas: %w", err)
+	}
+
+	published := 0
+	for i := range recipes {
+		recipe := &recipes[i]
+
+		if err := CheckCompleteness(db, recipe); err != nil {
+			log.Warn("receita agendada incompleta, agendamento cancelado", "recipe_id", recipe.ID, "error", err)
+			// Só cancela se o agendamento vencido ainda é o mesmo (o dono pode ter reagendado ou arquivado)
+			if err := db.Model(recipe).
+				Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", models.RecipeStatusDraft, now).
+				Update("publish_at", nil).Error; err != nil {
+				log.Error("erro ao cancelar agendamento da receita", "recipe_id", recipe.ID, "error", err)
+			}
+			continue
+		}
+
+		ok, err := PublishScheduled(db, recipe, now)
+		if err != nil {
+			log.Error("erro ao publicar receita agendada", "recipe_id", recipe.ID, "error", err)
+			continue
+		}
+		if !ok {
+			log.Info("receita agendada alterada antes da publicação, ignorada", "recipe_id", recipe.ID)
+			continue
+		}
+		published++
+	}
+
+	if published > 0 {
+		log.Info("receitas agendadas publicadas", "count", published)
+	}
+
+	return nil
+}
+
+// StartScheduler inicia job em background para publicar receitas agendadas
+func StartScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := PublishScheduledRecipes(); err != nil {
+				log.Error("erro ao publicar receitas agendadas", "error", err)
+			}
+		}
+	}()
+	log.Info("job de publicação agendada de receitas iniciado", "interval", interval)
+}
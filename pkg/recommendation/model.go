@@ -0,0 +1,174 @@
+package recommendation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+)
+
+// MinCommonRaters é o mínimo de usuários em comum para duas receitas serem comparadas
+const MinCommonRaters = 2
+
+// shrinkage reduz a similaridade de pares com poucos avaliadores em comum: n / (n + shrinkage)
+const shrinkage = 3
+
+// MaxItemNeighbors é a quantidade de vizinhos guardados por receita
+const MaxItemNeighbors = 50
+
+// UserRating é uma avaliação usada no treino do modelo
+type UserRating struct {
+	UserID   uint
+	RecipeID uint
+	Score    float64
+}
+
+// pairStats acumula as somas do cosseno ajustado de um par de receitas
+type pairStats struct {
+	dot, normA, normB float64
+	count             int
+}
+
+// ItemSimilarities calcula a similaridade item-item pelo cosseno ajustado: as notas são centradas
+// na média de cada usuário, de modo que usuários exigentes e generosos contam igual
+// Apenas similaridades positivas são mantidas
+func ItemSimilarities(ratings []UserRating) []models.RecipeRatingSimilarity {
+	byUser := make(map[uint][]UserRating)
+	for _, r := range ratings {
+		byUser[r.UserID] = append(byUser[r.UserID], r)
+	}
+
+	pairs := make(map[[2]uint]*pairStats)
+	for _, userRatings := range byUser {
+		if len(userRatings) < 2 {
+			continue
+		}
+		var mean float64
+		for _, r := range userRatings {
+			mean += r.Score
+		}
+		mean /= float64(len(userRatings))
+
+		sort.Slice(userRatings, func(i, j int) bool { return userRatings[i].RecipeID < userRatings[j].RecipeID })
+		for i := 0; i < len(userRatings); i++ {
+			di := userRatings[i].Score - mean
+			for j := i + 1; j < len(userRatings); j++ {
+				dj := userRatings[j].Score - mean
+				key := [2]uint{userRatings[i].RecipeID, userRatings[j].RecipeID}
+				stats, ok := pairs[key]
+				if !ok {
+					stats = &pairStats{}
+					pairs[key] = stats
+				}
+				stats.dot += di * dj
+				stats.normA += di * di
+				stats.normB += dj * dj
+				stats.count++
+			}
+		}
+	}
+
+	neighbors := make(map[uint][]models.RecipeRatingSimilarity)
+	for key, stats := range pairs {
+		if stats.count < MinCommonRaters || stats.normA == 0 || stats.normB == 0 {
+			continue
+		}
+		cosine := stats.dot / (math.Sqrt(stats.normA) * math.Sqrt(stats.normB))
+		score := math.Round(cosine*float64(stats.count)/float64(stats.count+shrinkage)*1000) / 1000
+		if score <= 0 {
+			continue
+		}
+		neighbors[key[0]] = append(neighbors[key[0]], models.RecipeRatingSimilarity{RecipeID: key[0], SimilarID: key[1], Score: score, CommonRaters: stats.count})
+		neighbors[key[1]] = append(neighbors[key[1]], models.RecipeRatingSimilarity{RecipeID: key[1], SimilarID: key[0], Score: score, CommonRaters: stats.count})
+	}
+
+	var rows []models.RecipeRatingSimilarity
+	for _, list := range neighbors {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Score != list[j].Score {
+				return list[i].Score > list[j].Score
+			}
+			return list[i].SimilarID < list[j].SimilarID
+		})
+		if len(list) > MaxItemNeighbors {
+			list = list[:MaxItemNeighbors]
+		}
+		rows = append(rows, list...)
+	}
+	return rows
+}
+
+// Rebuild treina o modelo item-item com as avaliações atuais e substitui a tabela recipe_rating_similarities
+func Rebuild(db *gorm.DB) (int, error) {
+	var ratings []UserRating
+	if err := db.Model(&models.Rating{}).
+		Select("ratings.user_id, ratings.recipe_id, ratings.score").
+		Joins("JOIN recipes ON recipes.id = ratings.recipe_id AND recipes.deleted_at IS NULL").
+		Scan(&ratings).Error; err != nil {
+		return 0, fmt.Errorf("erro ao carregar avaliações: %w", err)
+	}
+
+	rows := ItemSimilarities(ratings)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.RecipeRatingSimilarity{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gravar modelo de recomendação: %w", err)
+	}
+	return len(rows), nil
+}
+
+// RatingsChangedSince indica se alguma avaliação foi criada, alterada ou excluída depois de since
+func RatingsChangedSince(db *gorm.DB, since time.Time) (bool, error) {
+	var count int64
+	err := db.Unscoped().Model(&models.Rating{}).
+		Where("updated_at > ? OR deleted_at > ?", since, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// StartScheduler inicia o job que retreina o modelo de recomendação quando há avaliações novas
+// O primeiro treino acontece logo na inicialização
+func StartScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		var since time.Time
+		refresh := func() {
+			changed, err := RatingsChangedSince(database.DB, since)
+			if err != nil {
+				log.Error("erro ao verificar avaliações novas", "error", err)
+				return
+			}
+			if !changed {
+				return
+			}
+
+			started := time.Now()
+			count, err := Rebuild(database.DB)
+			if err != nil {
+				log.Error("erro ao treinar modelo de recomendação", "error", err)
+				return
+			}
+			since = started
+			log.Info("modelo de recomendação treinado", "pairs", count, "duration", time.Since(started))
+		}
+
+		refresh()
+		for range ticker.C {
+			refresh()
+		}
+	}()
+	log.Info("job de treino do modelo de recomendação iniciado", "interval", interval)
+}
@@ -0,0 +1,259 @@
+package recommendation
+
+import (
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+// Origem de uma recomendação
+const (
+	SourceCollaborative = "collaborative" // Usuários com gosto parecido avaliaram bem
+	SourceContent       = "content"       // Semelhante a receitas que o usuário gostou ou criou
+	SourcePopular       = "popular"       // Mais bem avaliadas (sem histórico suficiente)
+)
+
+// ColdStartRatings é o número de avaliações a partir do qual a filtragem colaborativa tem peso total
+// Abaixo disso, a similaridade de conteúdo completa a recomendação
+const ColdStartRatings = 5
+
+// neutralScore é a nota neutra: avaliações acima aproximam, abaixo afastam receitas parecidas
+const neutralScore = 3
+
+// popularityPrior é o peso da média geral na média bayesiana das mais bem avaliadas
+const popularityPrior = 5
+
+// Recommendation é uma receita recomendada para o usuário
+type Recommendation struct {
+	RecipeID  uint    `json:"recipe_id"`
+	Score     float64 `json:"score"` // 0 a 1 (relativo à melhor recomendação); 0 para populares
+	Source    string  `json:"source"`
+	BecauseOf *uint   `json:"because_of_recipe_id,omitempty"` // Receita que mais contribuiu
+}
+
+// signal acumula a pontuação de um candidato e a receita que mais contribuiu
+type signal struct {
+	score       float64
+	top         float64
+	contributor uint
+}
+
+// add soma uma contribuição; a maior contribuição positiva define a receita de origem
+func (s *signal) add(value float64, from uint) {
+	s.score += value
+	if value > s.top {
+		s.top = value
+		s.contributor = from
+	}
+}
+
+// Recommend gera até limit recomendações para o usuário
+// Combina a filtragem colaborativa (recipe_rating_similarities) com a similaridade de conteúdo
+// (recipe_similarities) conforme o histórico do usuário e completa com as mais bem avaliadas
+// Receitas criadas ou já avaliadas pelo usuário e receitas não listáveis nunca são recomendadas
+func Recommend(db *gorm.DB, userID uint, limit int) ([]Recommendation, error) {
+	var ratings []models.Rating
+	if err := db.Select("recipe_id", "score").Where("user_id = ?", userID).Find(&ratings).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar avaliações do usuário: %w", err)
+	}
+
+	var authored []uint
+	if err := db.Model(&models.Recipe{}).Where("user_id = ?", userID).Pluck("id", &authored).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar receitas do usuário: %w", err)
+	}
+
+	exclude := make(map[uint]bool)
+	ratedIDs := make([]uint, len(ratings))
+	weights := make(map[uint]float64) // Peso de cada receita avaliada ou criada como semente
+	for i, r := range ratings {
+		exclude[r.RecipeID] = true
+		ratedIDs[i] = r.RecipeID
+		weights[r.RecipeID] = float64(r.Score - neutralScore)
+	}
+	for _, id := range authored {
+		exclude[id] = true
+		if _, rated := weights[id]; !rated {
+			weights[id] = 1 // Quem cria uma receita gosta dela
+		}
+	}
+
+	collaborative := make(map[uint]*signal)
+	if len(ratedIDs) > 0 {
+		var rows []models.RecipeRatingSimilarity
+		if err := db.Where("recipe_id IN ?", ratedIDs).Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("erro ao carregar modelo colaborativo: %w", err)
+		}
+		edges := make([]edge, len(rows))
+		for i, row := range rows {
+			edges[i] = edge{row.RecipeID, row.SimilarID, row.Score}
+		}
+		accumulate(collaborative, edges, weights, exclude)
+	}
+
+	content := make(map[uint]*signal)
+	seeds := make([]uint, 0, len(weights))
+	for id, weight := range weights {
+		if weight > 0 {
+			seeds = append(seeds, id)
+		}
+	}
+	if len(seeds) > 0 {
+		var rows []models.RecipeSimilarity
+		if err := db.Where("recipe_id IN ?", seeds).Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("erro ao carregar receitas semelhantes: %w", err)
+		}
+		edges := make([]edge, len(rows))
+		for i, row := range rows {
+			edges[i] = edge{row.RecipeID, row.SimilarID, row.Score}
+		}
+		accumulate(content, edges, weights, exclude)
+	}
+
+	// Peso da filtragem colaborativa cresce com o histórico; sem sinal colaborativo, vale só o conteúdo
+	alpha := float64(len(ratings)) / ColdStartRatings
+	if alpha > 1 {
+		alpha = 1
+	}
+	if positive(collaborative) == 0 {
+		alpha = 0
+	}
+	maxCollaborative, maxContent := positive(collaborative), positive(content)
+
+	candidates := make(map[uint]*Recommendation)
+	for id, s := range collaborative {
+		if s.score <= 0 || alpha == 0 {
+			continue
+		}
+		from := s.contributor
+		candidates[id] = &Recommendation{RecipeID: id, Score: alpha * s.score / maxCollaborative, Source: SourceCollaborative, BecauseOf: &from}
+	}
+	for id, s := range content {
+		if s.score <= 0 || alpha == 1 {
+			continue
+		}
+		value := (1 - alpha) * s.score / maxContent
+		rec, ok := candidates[id]
+		if !ok {
+			from := s.contributor
+			candidates[id] = &Recommendation{RecipeID: id, Score: value, Source: SourceContent, BecauseOf: &from}
+			continue
+		}
+		if value > rec.Score {
+			from := s.contributor
+			rec.Source, rec.BecauseOf = SourceContent, &from
+		}
+		rec.Score += value
+	}
+
+	recommendations, err := listable(db, candidates)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].RecipeID < recommendations[j].RecipeID
+	})
+	if len(recommendations) >= limit {
+		return recommendations[:limit], nil
+	}
+
+	for _, rec := range recommendations {
+		exclude[rec.RecipeID] = true
+	}
+	popular, err := popularRecipes(db, exclude, limit-len(recommendations))
+	if err != nil {
+		return nil, err
+	}
+	return append(recommendations, popular...), nil
+}
+
+// edge é a similaridade de uma receita semente (avaliada ou criada) com um vizinho
+type edge struct {
+	from, to uint
+	score    float64
+}
+
+// accumulate soma as similaridades dos vizinhos das sementes, ponderadas pelo peso de cada semente
+func accumulate(signals map[uint]*signal, edges []edge, weights map[uint]float64, exclude map[uint]bool) {
+	for _, e := range edges {
+		if exclude[e.to] {
+			continue
+		}
+		s, ok := signals[e.to]
+		if !ok {
+			s = &signal{}
+			signals[e.to] = s
+		}
+		s.add(e.score*weights[e.from], e.from)
+	}
+}
+
+// positive retorna a maior pontuação positiva dos sinais (0 se não houver)
+func positive(signals map[uint]*signal) float64 {
+	var best float64
+	for _, s := range signals {
+		if s.score > best {
+			best = s.score
+		}
+	}
+	return best
+}
+
+// listable mantém apenas os candidatos publicados e públicos
+func listable(db *gorm.DB, candidates map[uint]*Recommendation) ([]Recommendation, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint, 0, len(candidates))
+	for id := range candidates {
+		ids = append(ids, id)
+	}
+
+	var listed []uint
+	if err := db.Model(&models.Recipe{}).Scopes(models.PubliclyListed).Where("recipes.id IN ?", ids).Pluck("recipes.id", &listed).Error; err != nil {
+		return nil, fmt.Errorf("erro ao filtrar receitas recomendadas: %w", err)
+	}
+
+	recommendations := make([]Recommendation, 0, len(listed))
+	for _, id := range listed {
+		recommendations = append(recommendations, *candidates[id])
+	}
+	return recommendations, nil
+}
+
+// popularRecipes lista as receitas mais bem avaliadas pela média bayesiana (poucas notas puxam para a média geral)
+func popularRecipes(db *gorm.DB, exclude map[uint]bool, limit int) ([]Recommendation, error) {
+	var globalMean float64
+	if err := db.Model(&models.Rating{}).Select("COALESCE(AVG(score), 0)").Scan(&globalMean).Error; err != nil {
+		return nil, fmt.Errorf("erro ao calcular média geral: %w", err)
+	}
+
+	excluded := make([]uint, 0, len(exclude))
+	for id := range exclude {
+		excluded = append(excluded, id)
+	}
+
+	query := db.Model(&models.Recipe{}).Scopes(models.PubliclyListed).
+		Joins("LEFT JOIN (SELECT recipe_id, SUM(score) AS total, COUNT(*) AS votes FROM ratings WHERE deleted_at IS NULL GROUP BY recipe_id) r ON r.recipe_id = recipes.id").
+		Order(gorm.Expr("(COALESCE(r.total, 0) + ? * ?) / (COALESCE(r.votes, 0) + ?) DESC, recipes.created_at DESC", popularityPrior, globalMean, popularityPrior)).
+		Limit(limit)
+	if len(excluded) > 0 {
+		query = query.Where("recipes.id NOT IN ?", excluded)
+	}
+
+	var ids []uint
+	if err := query.Pluck("recipes.id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar receitas populares: %w", err)
+	}
+
+	popular := make([]Recommendation, len(ids))
+	for i, id := range ids {
+		popular[i] = Recommendation{RecipeID: id, Source: SourcePopular}
+	}
+	return popular, nil
+}
@@ -0,0 +1,61 @@
+package shopping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+)
+
+// Text formata a lista em texto simples, com os corredores em maiúsculas
+func Text(list models.ShoppingList) string {
+	var b strings.Builder
+	b.WriteString(list.Name + "\n")
+
+	for _, group := range GroupByCategory(list.Items) {
+		fmt.Fprintf(&b, "\n%s\n", strings.ToUpper(group.Category))
+		for _, item := range group.Items {
+			fmt.Fprintf(&b, "- %s\n", describe(item))
+		}
+	}
+	return b.String()
+}
+
+// Checklist formata a lista para apps de mensagem: corredores em negrito (*texto*) e caixas de seleção
+func Checklist(list models.ShoppingList) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*\n", list.Name)
+
+	for _, group := range GroupByCategory(list.Items) {
+		fmt.Fprintf(&b, "\n*%s*\n", capitalize(group.Category))
+		for _, item := range group.Items {
+			box := "☐"
+			if item.Checked {
+				box = "☑"
+			}
+			fmt.Fprintf(&b, "%s %s\n", box, describe(item))
+		}
+	}
+	return b.String()
+}
+
+// describe monta "Nome: quantidade unidade" (itens sem quantidade mostram só o nome)
+func describe(item models.ShoppingListItem) string {
+	if item.Quantity == nil {
+		return item.Name
+	}
+	quantity := strings.Replace(strconv.FormatFloat(*item.Quantity, 'f', -1, 64), ".", ",", 1)
+	return strings.TrimSpace(fmt.Sprintf("%s: %s %s", item.Name, quantity, item.Unit))
+}
+
+// capitalize coloca a primeira letra em maiúscula
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,103 @@
+package shopping
+
+import (
+	"math"
+	"sort"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/units"
+)
+
+// DefaultCategory agrupa itens sem categoria (ingredientes sem categoria e itens manuais)
+const DefaultCategory = "outros"
+
+// Source é um ingrediente de receita já multiplicado pelo fator de porções
+type Source struct {
+	Ingredient models.Ingredient
+	Quantity   float64
+	Unit       string
+}
+
+// Group são os itens de um corredor do supermercado
+type Group struct {
+	Category string                    `json:"category"`
+	Items    []models.ShoppingListItem `json:"items"`
+}
+
+// Aggregate soma as quantidades do mesmo ingrediente, convertendo para a unidade base
+// Unidades incompatíveis (ex.: gramas e "dente") geram linhas separadas do mesmo ingrediente
+func Aggregate(sources []Source) []models.ShoppingListItem {
+	type key struct {
+		ingredientID uint
+		unit         string
+	}
+
+	totals := make(map[key]float64)
+	ingredients := make(map[uint]models.Ingredient)
+	var order []key
+	for _, source := range sources {
+		quantity, unit := units.Normalize(source.Quantity, source.Unit)
+		k := key{source.Ingredient.ID, unit}
+		if _, ok := totals[k]; !ok {
+			order = append(order, k)
+		}
+		totals[k] += quantity
+		ingredients[source.Ingredient.ID] = source.Ingredient
+	}
+
+	items := make([]models.ShoppingListItem, 0, len(order))
+	for _, k := range order {
+		ingredient := ingredients[k.ingredientID]
+		quantity, unit := units.Display(totals[k], k.unit)
+		quantity = math.Round(quantity*100) / 100
+
+		ingredientID := ingredient.ID
+		items = append(items, models.ShoppingListItem{
+			IngredientID: &ingredientID,
+			Name:         ingredient.Name,
+			Category:     categoryOf(ingredient.Category),
+			Quantity:     &quantity,
+			Unit:         unit,
+		})
+	}
+
+	Sort(items)
+	return items
+}
+
+// Sort ordena os itens por categoria e nome, mantendo itens manuais após os gerados no mesmo corredor
+func Sort(items []models.ShoppingListItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Category != items[j].Category {
+			return items[i].Category < items[j].Category
+		}
+		if items[i].Manual != items[j].Manual {
+			return !items[i].Manual
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+// GroupByCategory agrupa os itens por corredor, na ordem de Sort
+func GroupByCategory(items []models.ShoppingListItem) []Group {
+	sorted := append([]models.ShoppingListItem(nil), items...)
+	Sort(sorted)
+
+	groups := []Group{}
+	for _, item := range sorted {
+		category := categoryOf(item.Category)
+		if len(groups) == 0 || groups[len(groups)-1].Category != category {
+			groups = append(groups, Group{Category: category})
+		}
+		groups[len(groups)-1].Items = append(groups[len(groups)-1].Items, item)
+	}
+	return groups
+}
+
+// categoryOf retorna a categoria ou o corredor padrão
+func categoryOf(category string) string {
+	if category == "" {
+		return DefaultCategory
+	}
+	return category
+}
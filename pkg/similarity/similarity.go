@@ -0,0 +1,289 @@
+package similarity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/log"
+)
+
+// Pesos de cada sinal na similaridade final (somam 1)
+const (
+	ingredientWeight = 0.6
+	tagWeight        = 0.2
+	difficultyWeight = 0.1
+	prepTimeWeight   = 0.1
+)
+
+// MaxNeighbors é a quantidade de vizinhos guardados por receita
+const MaxNeighbors = 20
+
+// MinScore é a similaridade mínima para uma receita ser guardada como vizinha
+const MinScore = 0.1
+
+// difficultyLevels ordena as dificuldades para comparar receitas vizinhas de nível
+var difficultyLevels = map[string]int{"fácil": 0, "média": 1, "difícil": 2}
+
+// Profile reúne os atributos de uma receita usados na comparação
+type Profile struct {
+	ID          uint
+	Difficulty  string
+	PrepTime    int
+	Ingredients map[uint]bool
+	Tags        map[uint]bool
+}
+
+// IDF calcula o peso de cada ingrediente pela frequência inversa no corpus (ln((1+N)/(1+df)))
+// Ingredientes presentes em quase todas as receitas (sal, óleo) ficam com peso próximo de zero
+func IDF(corpus []*Profile) map[uint]float64 {
+	df := make(map[uint]int)
+	for _, p := range corpus {
+		for id := range p.Ingredients {
+			df[id]++
+		}
+	}
+
+	weights := make(map[uint]float64, len(df))
+	n := float64(len(corpus))
+	for id, count := range df {
+		weights[id] = math.Log((1 + n) / (1 + float64(count)))
+	}
+	return weights
+}
+
+// Score combina a sobreposição de ingredientes (Jaccard ponderado por IDF), de tags (Jaccard),
+// a dificuldade e o tempo de preparo em um valor de 0 a 1
+// Ingredientes fora do corpus recebem o peso máximo (ln(1+N))
+func Score(a, b *Profile, idf map[uint]float64, maxWeight float64) float64 {
+	var intersection, union float64
+	weight := func(id uint) float64 {
+		if w, ok := idf[id]; ok {
+			return w
+		}
+		return maxWeight
+	}
+	for id := range a.Ingredients {
+		w := weight(id)
+		union += w
+		if b.Ingredients[id] {
+			intersection += w
+		}
+	}
+	for id := range b.Ingredients {
+		if !a.Ingredients[id] {
+			union += weight(id)
+		}
+	}
+
+	score := 0.0
+	if union > 0 {
+		score += ingredientWeight * intersection / union
+	}
+	score += tagWeight * jaccard(a.Tags, b.Tags)
+
+	levelA, okA := difficultyLevels[a.Difficulty]
+	levelB, okB := difficultyLevels[b.Difficulty]
+	if okA && okB {
+		switch math.Abs(float64(levelA - levelB)) {
+		case 0:
+			score += difficultyWeight
+		case 1:
+			score += difficultyWeight / 2
+		}
+	}
+
+	if a.PrepTime > 0 && b.PrepTime > 0 {
+		score += prepTimeWeight * math.Min(float64(a.PrepTime), float64(b.PrepTime)) / math.Max(float64(a.PrepTime), float64(b.PrepTime))
+	}
+
+	return math.Round(score*1000) / 1000
+}
+
+// jaccard calcula |A ∩ B| / |A ∪ B|
+func jaccard(a, b map[uint]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for id := range a {
+		if b[id] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// Neighbors calcula os vizinhos de cada receita em sources entre as receitas do corpus
+// Só são comparadas receitas que compartilham ao menos um ingrediente relevante ou tag
+func Neighbors(sources, corpus []*Profile) []models.RecipeSimilarity {
+	idf := IDF(corpus)
+	maxWeight := math.Log(1 + float64(len(corpus)))
+
+	byIngredient := make(map[uint][]*Profile)
+	byTag := make(map[uint][]*Profile)
+	for _, p := range corpus {
+		for id := range p.Ingredients {
+			byIngredient[id] = append(byIngredient[id], p)
+		}
+		for id := range p.Tags {
+			byTag[id] = append(byTag[id], p)
+		}
+	}
+
+	var rows []models.RecipeSimilarity
+	for _, source := range sources {
+		// Ingredientes presentes em todas as receitas (peso zero) não aproximam receitas
+		candidates := make(map[uint]*Profile)
+		for id := range source.Ingredients {
+			if w, ok := idf[id]; ok && w == 0 {
+				continue
+			}
+			for _, p := range byIngredient[id] {
+				candidates[p.ID] = p
+			}
+		}
+		for id := range source.Tags {
+			for _, p := range byTag[id] {
+				candidates[p.ID] = p
+			}
+		}
+		delete(candidates, source.ID)
+
+		var neighbors []models.RecipeSimilarity
+		for _, candidate := range candidates {
+			if score := Score(source, candidate, idf, maxWeight); score >= MinScore {
+				neighbors = append(neighbors, models.RecipeSimilarity{RecipeID: source.ID, SimilarID: candidate.ID, Score: score})
+			}
+		}
+		sort.Slice(neighbors, func(i, j int) bool {
+			if neighbors[i].Score != neighbors[j].Score {
+				return neighbors[i].Score > neighbors[j].Score
+			}
+			return neighbors[i].SimilarID < neighbors[j].SimilarID
+		})
+		if len(neighbors) > MaxNeighbors {
+			neighbors = neighbors[:MaxNeighbors]
+		}
+		rows = append(rows, neighbors...)
+	}
+	return rows
+}
+
+// Rebuild recalcula os vizinhos de todas as receitas e substitui a tabela recipe_similarities
+// Toda receita recebe vizinhos, mas apenas receitas listáveis (publicadas e públicas) podem ser vizinhas
+func Rebuild(db *gorm.DB) (int, error) {
+	var recipes []models.Recipe
+	if err := db.Select("id", "difficulty", "prep_time", "status", "visibility").Find(&recipes).Error; err != nil {
+		return 0, fmt.Errorf("erro ao carregar receitas: %w", err)
+	}
+
+	profiles := make(map[uint]*Profile, len(recipes))
+	sources := make([]*Profile, 0, len(recipes))
+	var corpus []*Profile
+	for _, recipe := range recipes {
+		p := &Profile{
+			ID:          recipe.ID,
+			Difficulty:  recipe.Difficulty,
+			PrepTime:    recipe.PrepTime,
+			Ingredients: make(map[uint]bool),
+			Tags:        make(map[uint]bool),
+		}
+		profiles[recipe.ID] = p
+		sources = append(sources, p)
+		if recipe.Status == models.RecipeStatusPublished && recipe.Visibility == models.RecipeVisibilityPublic {
+			corpus = append(corpus, p)
+		}
+	}
+
+	var links []struct {
+		RecipeID uint
+		ItemID   uint
+	}
+	if err := db.Table("recipe_ingredients").Select("recipe_id, ingredient_id AS item_id").Scan(&links).Error; err != nil {
+		return 0, fmt.Errorf("erro ao carregar ingredientes das receitas: %w", err)
+	}
+	for _, link := range links {
+		if p, ok := profiles[link.RecipeID]; ok {
+			p.Ingredients[link.ItemID] = true
+		}
+	}
+
+	links = nil
+	if err := db.Table("recipe_tags").
+		Select("recipe_tags.recipe_id, recipe_tags.tag_id AS item_id").
+		Joins("JOIN tags ON tags.id = recipe_tags.tag_id").
+		Where("tags.status = ?", models.TagStatusApproved).
+		Scan(&links).Error; err != nil {
+		return 0, fmt.Errorf("erro ao carregar tags das receitas: %w", err)
+	}
+	for _, link := range links {
+		if p, ok := profiles[link.RecipeID]; ok {
+			p.Tags[link.ItemID] = true
+		}
+	}
+
+	rows := Neighbors(sources, corpus)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.RecipeSimilarity{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gravar receitas semelhantes: %w", err)
+	}
+	return len(rows), nil
+}
+
+// ChangedSince indica se alguma receita foi criada, alterada ou excluída depois de since
+// Alterações de ingredientes e tags também atualizam recipes.updated_at
+func ChangedSince(db *gorm.DB, since time.Time) (bool, error) {
+	var count int64
+	err := db.Unscoped().Model(&models.Recipe{}).
+		Where("updated_at > ? OR deleted_at > ?", since, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// StartScheduler inicia o job que reconstrói as receitas semelhantes quando receitas mudam
+// A primeira reconstrução acontece logo na inicialização
+func StartScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		var since time.Time
+		refresh := func() {
+			changed, err := ChangedSince(database.DB, since)
+			if err != nil {
+				log.Error("erro ao verificar alterações de receitas", "error", err)
+				return
+			}
+			if !changed {
+				return
+			}
+
+			started := time.Now()
+			count, err := Rebuild(database.DB)
+			if err != nil {
+				log.Error("erro ao reconstruir receitas semelhantes", "error", err)
+				return
+			}
+			since = started
+			log.Info("receitas semelhantes reconstruídas", "neighbors", count, "duration", time.Since(started))
+		}
+
+		refresh()
+		for range ticker.C {
+			refresh()
+		}
+	}()
+	log.Info("job de receitas semelhantes iniciado", "interval", interval)
+}
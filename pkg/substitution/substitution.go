@@ -0,0 +1,144 @@
+package substitution
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/dietary"
+)
+
+// Origem da sugestão de substituição
+const (
+	SourceCurated = "curated" // Tabela curada pelos admins
+	SourceSimilar = "similar" // Perfil de macronutrientes parecido, mesma categoria
+)
+
+// MinSimilarity é a similaridade mínima para um ingrediente da mesma categoria ser sugerido
+const MinSimilarity = 0.9
+
+// Constraints são as restrições que o substituto precisa atender
+type Constraints struct {
+	Diets            []string // Chaves de dietary.DietColumns
+	ExcludeAllergens []string // Chaves de models.AllergenColumns
+}
+
+// Empty indica se não há restrição
+func (c Constraints) Empty() bool {
+	return len(c.Diets) == 0 && len(c.ExcludeAllergens) == 0
+}
+
+// Violations lista as dietas e alérgenos das restrições que o ingrediente viola
+func (c Constraints) Violations(ingredient *models.Ingredient) []string {
+	var violations []string
+	classification := dietary.Classify([]models.Ingredient{*ingredient})
+	for _, diet := range c.Diets {
+		if !classification.Satisfies(diet) {
+			violations = append(violations, diet)
+		}
+	}
+	for _, allergen := range c.ExcludeAllergens {
+		if ingredient.HasAllergen(allergen) {
+			violations = append(violations, allergen)
+		}
+	}
+	return violations
+}
+
+// Substitute é uma sugestão de substituição para um ingrediente
+type Substitute struct {
+	Ingredient models.Ingredient `json:"ingredient"`
+	Source     string            `json:"source"`
+	Ratio      float64           `json:"ratio"` // Quantidade do substituto por unidade do original
+	Notes      string            `json:"notes,omitempty"`
+	Similarity float64           `json:"similarity"` // 0 a 1, pelo perfil de macronutrientes
+}
+
+// Similarity compara o perfil de macronutrientes (proteínas, carboidratos, gorduras e fibras por 100g)
+// pela similaridade de cosseno, penalizada pela diferença de calorias
+func Similarity(a, b *models.Ingredient) float64 {
+	va := []float64{a.Protein, a.Carbs, a.Fat, a.Fiber}
+	vb := []float64{b.Protein, b.Carbs, b.Fat, b.Fiber}
+
+	var dot, normA, normB float64
+	for i := range va {
+		dot += va[i] * vb[i]
+		normA += va[i] * va[i]
+		normB += vb[i] * vb[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	cosine := dot / (math.Sqrt(normA) * math.Sqrt(normB))
+
+	calories := 1.0
+	if high := math.Max(a.Calories, b.Calories); high > 0 {
+		calories = math.Min(a.Calories, b.Calories) / high
+	}
+	return math.Round(cosine*calories*100) / 100
+}
+
+// Find sugere até limit substitutos para o ingrediente que atendem às restrições
+// Substituições curadas vêm primeiro; depois, ingredientes da mesma categoria com perfil parecido
+func Find(db *gorm.DB, ingredient *models.Ingredient, constraints Constraints, limit int) ([]Substitute, error) {
+	substitutes := []Substitute{}
+	skip := map[uint]bool{ingredient.ID: true}
+
+	var curated []models.IngredientSubstitution
+	if err := db.Preload("Substitute").
+		Where("ingredient_id = ?", ingredient.ID).
+		Order("id ASC").
+		Find(&curated).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar substituições curadas: %w", err)
+	}
+
+	for _, sub := range curated {
+		skip[sub.SubstituteID] = true
+		if len(substitutes) == limit || len(constraints.Violations(&sub.Substitute)) > 0 {
+			continue
+		}
+		substitutes = append(substitutes, Substitute{
+			Ingredient: sub.Substitute,
+			Source:     SourceCurated,
+			Ratio:      sub.Ratio,
+			Notes:      sub.Notes,
+			Similarity: Similarity(ingredient, &sub.Substitute),
+		})
+	}
+	if len(substitutes) == limit || ingredient.Category == "" {
+		return substitutes, nil
+	}
+
+	var candidates []models.Ingredient
+	if err := db.Where("category = ?", ingredient.Category).Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar ingredientes da categoria: %w", err)
+	}
+
+	var similar []Substitute
+	for i := range candidates {
+		candidate := &candidates[i]
+		if skip[candidate.ID] || len(constraints.Violations(candidate)) > 0 {
+			continue
+		}
+		if score := Similarity(ingredient, candidate); score >= MinSimilarity {
+			similar = append(similar, Substitute{Ingredient: *candidate, Source: SourceSimilar, Ratio: 1, Similarity: score})
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool {
+		if similar[i].Similarity != similar[j].Similarity {
+			return similar[i].Similarity > similar[j].Similarity
+		}
+		return similar[i].Ingredient.Name < similar[j].Ingredient.Name
+	})
+
+	for _, sub := range similar {
+		if len(substitutes) == limit {
+			break
+		}
+		substitutes = append(substitutes, sub)
+	}
+	return substitutes, nil
+}
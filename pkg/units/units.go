@@ -0,0 +1,73 @@
+package units
+
+import "strings"
+
+// Unidades base usadas para somar e subtrair quantidades de unidades diferentes
+const (
+	Gram       = "g"
+	Milliliter = "ml"
+	Piece      = "un"
+)
+
+// unitFactors converte unidades comuns nas receitas para a unidade base (massa em gramas, volume em mililitros)
+// Medidas caseiras seguem as equivalências usuais: xícara = 240ml, colher de sopa = 15ml, colher de chá = 5ml
+var unitFactors = map[string]struct {
+	base   string
+	factor float64
+}{
+	"g":                {Gram, 1},
+	"gr":               {Gram, 1},
+	"grama":            {Gram, 1},
+	"gramas":           {Gram, 1},
+	"mg":               {Gram, 0.001},
+	"kg":               {Gram, 1000},
+	"quilo":            {Gram, 1000},
+	"quilos":           {Gram, 1000},
+	"ml":               {Milliliter, 1},
+	"mililitro":        {Milliliter, 1},
+	"mililitros":       {Milliliter, 1},
+	"l":                {Milliliter, 1000},
+	"litro":            {Milliliter, 1000},
+	"litros":           {Milliliter, 1000},
+	"xícara":           {Milliliter, 240},
+	"xícaras":          {Milliliter, 240},
+	"xicara":           {Milliliter, 240},
+	"xicaras":          {Milliliter, 240},
+	"colher de sopa":   {Milliliter, 15},
+	"colheres de sopa": {Milliliter, 15},
+	"colher (sopa)":    {Milliliter, 15},
+	"colheres (sopa)":  {Milliliter, 15},
+	"cs":               {Milliliter, 15},
+	"colher de chá":    {Milliliter, 5},
+	"colheres de chá":  {Milliliter, 5},
+	"colher (chá)":     {Milliliter, 5},
+	"colheres (chá)":   {Milliliter, 5},
+	"colher de cha":    {Milliliter, 5},
+	"cc":               {Milliliter, 5},
+	"un":               {Piece, 1},
+	"und":              {Piece, 1},
+	"unid":             {Piece, 1},
+	"unidade":          {Piece, 1},
+	"unidades":         {Piece, 1},
+}
+
+// Normalize converte a quantidade para a unidade base
+// Unidades desconhecidas (ex.: "dente", "pitada") são mantidas, apenas padronizadas em minúsculas
+func Normalize(quantity float64, unit string) (float64, string) {
+	key := strings.Join(strings.Fields(strings.ToLower(unit)), " ")
+	if conversion, ok := unitFactors[key]; ok {
+		return quantity * conversion.factor, conversion.base
+	}
+	return quantity, key
+}
+
+// Display converte a quantidade na unidade base para a unidade mais legível (1500 g -> 1,5 kg)
+func Display(quantity float64, unit string) (float64, string) {
+	switch {
+	case unit == Gram && quantity >= 1000:
+		return quantity / 1000, "kg"
+	case unit == Milliliter && quantity >= 1000:
+		return quantity / 1000, "l"
+	}
+	return quantity, unit
+}
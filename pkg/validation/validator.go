@@ -66,15 +66,17 @@ func translateError(err validator.FieldError) string {
 // translateField traduz o nome do campo para português
 func translateField(field string) string {
 	translations := map[string]string{
-		"Title":       "título",
-		"Description": "descrição",
-		"PrepTime":    "tempo de preparo",
-		"Servings":    "número de porções",
-		"Difficulty":  "dificuldade",
-		"Name":        "nome",
-		"Email":       "e-mail",
-		"Password":    "senha",
-		"Role":        "papel",
+		"Title":        "título",
+		"Description":  "descrição",
+		"Instructions": "modo de preparo",
+		"PrepTime":     "tempo de preparo",
+		"Servings":     "número de porções",
+		"Difficulty":   "dificuldade",
+		"Name":         "nome",
+		"Email":        "e-mail",
+		"Password":     "senha",
+		"Role":         "papel",
+		"Scopes":       "escopos",
 	}
 
 	if translated, ok := translations[field]; ok {
@@ -0,0 +1,177 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// createTestAPIKey cria uma chave de API via endpoint e retorna a chave e o ID
+func createTestAPIKey(t *testing.T, router http.Handler, token string, scopes []string) (string, uint) {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":   "Script de automação",
+		"scopes": scopes,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/auth/api-keys", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp struct {
+		ID  uint   `json:"id"`
+		Key string `json:"key"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.Key)
+	return resp.Key, resp.ID
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	createTestUser(t, "apikey@test.com", "password123", "API Key User")
+	token := loginTestUser(t, router, "apikey@test.com", "password123")
+
+	key, id := createTestAPIKey(t, router, token, []string{"recipes:read"})
+	assert.Contains(t, key, "rk_")
+
+	// Chave não deve ser armazenada em texto puro
+	var stored models.APIKey
+	require.NoError(t, database.DB.First(&stored, id).Error)
+	assert.NotEqual(t, key, stored.KeyHash)
+	assert.Equal(t, "recipes:read", stored.Scopes)
+
+	// Listagem não retorna a chave
+	req := httptest.NewRequest(http.MethodGet, "/auth/api-keys", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), key)
+	assert.Contains(t, rec.Body.String(), "recipes:read")
+}
+
+func TestCreateAPIKey_InvalidScope(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	createTestUser(t, "apikey_scope@test.com", "password123", "API Key User")
+	token := loginTestUser(t, router, "apikey_scope@test.com", "password123")
+
+	// ingredients:write não existe: o catálogo só é alterado em /admin
+	for _, scope := range []string{"admin:write", "ingredients:write"} {
+		body, _ := json.Marshal(map[string]interface{}{
+			"name":   "Chave inválida",
+			"scopes": []string{scope},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/auth/api-keys", bytes.NewBuffer(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, scope)
+	}
+}
+
+func TestAPIKey_ScopeEnforcement(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "apikey_enforce@test.com", "password123", "API Key User")
+	token := loginTestUser(t, router, "apikey_enforce@test.com", "password123")
+	recipe := createTestRecipe(t, user.ID)
+
+	readKey, _ := createTestAPIKey(t, router, token, []string{"recipes:read"})
+	writeKey, _ := createTestAPIKey(t, router, token, []string{"recipes:read", "recipes:write"})
+
+	// Chave somente leitura não pode editar
+	body, _ := json.Marshal(map[string]interface{}{"title": "Título via API"})
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/recipes/%d", recipe.ID), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+readKey)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INSUFFICIENT_SCOPE")
+
+	// Chave com escopo de escrita pode editar
+	req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/recipes/%d", recipe.ID), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+writeKey)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Chaves de API não podem gerenciar outras chaves
+	req = httptest.NewRequest(http.MethodGet, "/auth/api-keys", nil)
+	req.Header.Set("Authorization", "Bearer "+writeKey)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Recursos sem escopo de chave não são acessíveis
+	for _, path := range []string{"/meal-plans", "/pantry", "/shopping-lists", "/users/me"} {
+		req = httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+writeKey)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code, path)
+	}
+
+	// last_used_at deve ser registrado
+	var stored models.APIKey
+	require.NoError(t, database.DB.Where("user_id = ? AND scopes = ?", user.ID, "recipes:read,recipes:write").First(&stored).Error)
+	assert.NotNil(t, stored.LastUsedAt)
+}
+
+func TestAPIKey_RevokeAndExpiry(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "apikey_revoke@test.com", "password123", "API Key User")
+	token := loginTestUser(t, router, "apikey_revoke@test.com", "password123")
+	recipe := createTestRecipe(t, user.ID)
+
+	key, id := createTestAPIKey(t, router, token, []string{"recipes:write"})
+
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/auth/api-keys/%d", id), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, _ := json.Marshal(map[string]interface{}{"title": "Título via API"})
+	req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/recipes/%d", recipe.ID), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+key)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "API_KEY_REVOKED")
+
+	// Chave expirada
+	expiredKey, expiredID := createTestAPIKey(t, router, token, []string{"recipes:write"})
+	database.DB.Model(&models.APIKey{}).Where("id = ?", expiredID).Update("expires_at", time.Now().Add(-time.Hour))
+
+	req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/recipes/%d", recipe.ID), bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+expiredKey)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "API_KEY_EXPIRED")
+}
@@ -0,0 +1,209 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/privacy"
+	"github.com/davidsonmarra/receitas-app/pkg/storage"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// listTestCookLogs busca uma listagem paginada de registros
+func listTestCookLogs(t *testing.T, router http.Handler, path, token string) ([]models.CookLog, int64) {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodGet, path, token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var body struct {
+		Data       []models.CookLog `json:"data"`
+		Pagination struct {
+			Total int64 `json:"total"`
+		} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body.Data, body.Pagination.Total
+}
+
+func TestCookLogs_GalleryHistoryAndCounts(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	author := createTestUser(t, "cooklog_author@test.com", "password123", "Chef")
+	authorToken := loginTestUser(t, router, "cooklog_author@test.com", "password123")
+	createTestUser(t, "cooklog_ana@test.com", "password123", "Ana")
+	anaToken := loginTestUser(t, router, "cooklog_ana@test.com", "password123")
+	createTestUser(t, "cooklog_bruno@test.com", "password123", "Bruno")
+	brunoToken := loginTestUser(t, router, "cooklog_bruno@test.com", "password123")
+
+	recipe := testdb.SeedRecipe(t, "Bolo de fubá", "Da vó", author.ID, false)
+	private := testdb.SeedRecipe(t, "Bolo secreto", "Privado", author.ID, false)
+	require.NoError(t, database.DB.Model(private).Update("visibility", models.RecipeVisibilityPrivate).Error)
+
+	yesterday := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	anaLog := postJSON[models.CookLog](t, router, "/recipes/"+itoa(recipe.ID)+"/cook-logs", anaToken, map[string]interface{}{
+		"notes":       "Ficou ótimo",
+		"adjustments": "Troquei o leite por leite de coco",
+		"cooked_at":   yesterday,
+	})
+	assert.Equal(t, "Troquei o leite por leite de coco", anaLog.Adjustments)
+	assert.True(t, yesterday.Equal(anaLog.CookedAt))
+	brunoLog := postJSON[models.CookLog](t, router, "/recipes/"+itoa(recipe.ID)+"/cook-logs", brunoToken, nil)
+	postJSON[models.CookLog](t, router, "/recipes/"+itoa(recipe.ID)+"/cook-logs", authorToken, map[string]interface{}{"notes": "Versão sem glúten"})
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/cook-logs", anaToken, map[string]interface{}{"cooked_at": time.Now().Add(time.Hour)})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/cook-logs", anaToken, map[string]interface{}{"notes": strings.Repeat("a", 2001)})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(private.ID)+"/cook-logs", anaToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/cook-logs", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Preparos de terceiros contam nas estatísticas; os do autor não
+	var stat models.RecipeDailyStat
+	require.NoError(t, database.DB.Where("recipe_id = ?", recipe.ID).First(&stat).Error)
+	assert.Equal(t, 2, stat.Cooks)
+
+	// Galeria pública: mais recentes primeiro, sem expor o e-mail de quem preparou
+	gallery, total := listTestCookLogs(t, router, "/recipes/"+itoa(recipe.ID)+"/cook-logs", "")
+	assert.Equal(t, int64(3), total)
+	require.Len(t, gallery, 3)
+	assert.Equal(t, anaLog.ID, gallery[2].ID)
+	require.NotNil(t, gallery[2].User)
+	assert.Equal(t, "Ana", gallery[2].User.Name)
+	assert.Empty(t, gallery[2].User.Email)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/cook-logs?with_photo=maybe", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(private.ID)+"/cook-logs", anaToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Histórico pessoal
+	history, total := listTestCookLogs(t, router, "/users/me/cook-logs", anaToken)
+	assert.Equal(t, int64(1), total)
+	require.NotNil(t, history[0].Recipe)
+	assert.Equal(t, "Bolo de fubá", history[0].Recipe.Title)
+
+	// Receita que ficou privada depois do preparo some do histórico; o registro continua
+	require.NoError(t, database.DB.Model(recipe).Update("visibility", models.RecipeVisibilityPrivate).Error)
+	history, total = listTestCookLogs(t, router, "/users/me/cook-logs", anaToken)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, history, 1)
+	assert.Nil(t, history[0].Recipe)
+	history, _ = listTestCookLogs(t, router, "/users/me/cook-logs", authorToken)
+	require.NotEmpty(t, history)
+	assert.NotNil(t, history[0].Recipe)
+	require.NoError(t, database.DB.Model(recipe).Update("visibility", models.RecipeVisibilityPublic).Error)
+
+	// cook_count nas listagens e no detalhe
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Data []models.Recipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, int64(3), list.Data[0].CookCount)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var detail models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+	assert.Equal(t, int64(3), detail.CookCount)
+
+	// Apenas quem registrou pode remover
+	rec = doAuthRequest(t, router, http.MethodDelete, "/cook-logs/"+itoa(brunoLog.ID), anaToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodDelete, "/cook-logs/"+itoa(brunoLog.ID), brunoToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/recipes", authorToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var mine struct {
+		Data []models.Recipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &mine))
+	for _, r := range mine.Data {
+		if r.ID == recipe.ID {
+			assert.Equal(t, int64(2), r.CookCount)
+		} else {
+			assert.Zero(t, r.CookCount)
+		}
+	}
+}
+
+func TestCookLogs_PhotoUploadAndErasure(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	originalFactory := storage.ServiceFactory
+	defer func() { storage.ServiceFactory = originalFactory }()
+
+	mockService := testdb.NewMockCloudinaryService()
+	storage.ServiceFactory = func() (storage.ImageService, error) {
+		return mockService, nil
+	}
+
+	author := createTestUser(t, "photo_author@test.com", "password123", "Chef")
+	cook := createTestUser(t, "photo_cook@test.com", "password123", "Cook")
+	cookToken := loginTestUser(t, router, "photo_cook@test.com", "password123")
+	createTestUser(t, "photo_other@test.com", "password123", "Other")
+	otherToken := loginTestUser(t, router, "photo_other@test.com", "password123")
+
+	recipe := testdb.SeedRecipe(t, "Pudim", "Clássico", author.ID, false)
+	cookLog := postJSON[models.CookLog](t, router, "/recipes/"+itoa(recipe.ID)+"/cook-logs", cookToken, map[string]interface{}{"notes": "Com calda de caramelo"})
+	logPath := "/cook-logs/" + itoa(cookLog.ID)
+
+	rec := doAuthRequest(t, router, http.MethodPost, logPath+"/photo/upload-url", cookToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var signature storage.UploadSignature
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &signature))
+	assert.True(t, strings.HasPrefix(signature.PublicID, "cooklog_"+itoa(cookLog.ID)+"_"))
+	assert.Equal(t, "cook-logs", signature.Folder)
+
+	rec = doAuthRequest(t, router, http.MethodPost, logPath+"/photo/upload-url", otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	confirm := map[string]interface{}{
+		"secure_url": "https://res.cloudinary.com/test/image/upload/v1/cook-logs/pudim.jpg",
+		"width":      800,
+		"height":     600,
+		"format":     "jpg",
+		"bytes":      1024,
+	}
+	confirm["public_id"] = "cook-logs/cooklog_999999_1700000000"
+	rec = doAuthRequest(t, router, http.MethodPost, logPath+"/photo/confirm", cookToken, confirm)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	confirm["public_id"] = "cook-logs/" + signature.PublicID
+	rec = doAuthRequest(t, router, http.MethodPost, logPath+"/photo/confirm", cookToken, confirm)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	withPhoto, total := listTestCookLogs(t, router, "/recipes/"+itoa(recipe.ID)+"/cook-logs?with_photo=true", "")
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, confirm["secure_url"], withPhoto[0].PhotoURL)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, logPath+"/photo", cookToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	_, total = listTestCookLogs(t, router, "/recipes/"+itoa(recipe.ID)+"/cook-logs?with_photo=true", "")
+	assert.Zero(t, total)
+	rec = doAuthRequest(t, router, http.MethodDelete, logPath+"/photo", cookToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Eliminação (LGPD) remove os registros de quem preparou
+	_, err := privacy.EraseUser(t.Context(), cook)
+	require.NoError(t, err)
+	var count int64
+	database.DB.Model(&models.CookLog{}).Where("user_id = ?", cook.ID).Count(&count)
+	assert.Zero(t, count)
+}
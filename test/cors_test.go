@@ -89,3 +89,25 @@ func TestCORS_ExposedHeaders(t *testing.T) {
 		t.Logf("✅ X-Request-ID present: %s", requestID)
 	}
 }
+
+func TestCORS_PreflightPatch(t *testing.T) {
+	router := routes.Setup()
+
+	// Rotas como PATCH /users/me dependem do preflight aceitar PATCH
+	req := httptest.NewRequest(http.MethodOptions, "/users/me", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+	req.Header.Set("Access-Control-Request-Headers", "Authorization, Content-Type")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK && w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 200 or 204, got %d", w.Code)
+	}
+
+	allowMethods := w.Header().Get("Access-Control-Allow-Methods")
+	if allowMethods != http.MethodPatch {
+		t.Errorf("Expected Access-Control-Allow-Methods to allow PATCH, got %q", allowMethods)
+	}
+}
@@ -0,0 +1,187 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+const (
+	iphoneUserAgent  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"
+	androidUserAgent = "Mozilla/5.0 (Linux; Android 14; Pixel 8)"
+)
+
+// deviceRequest executa uma requisição JSON identificando o dispositivo
+func deviceRequest(t *testing.T, router http.Handler, method, path, token, deviceID, userAgent, ip string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	bodyBytes, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Forwarded-For", ip)
+	if deviceID != "" {
+		req.Header.Set("X-Device-ID", deviceID)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// loginWithDevice faz login a partir de um dispositivo e retorna access e refresh tokens
+func loginWithDevice(t *testing.T, router http.Handler, email, password, deviceID, userAgent string) (string, string) {
+	t.Helper()
+
+	rec := deviceRequest(t, router, http.MethodPost, "/users/login", "", deviceID, userAgent, "200.1.1.1",
+		map[string]string{"email": email, "password": password})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.AccessToken, resp.RefreshToken
+}
+
+// listDevices retorna os dispositivos ativos vistos pelo dispositivo informado
+func listDevices(t *testing.T, router http.Handler, token, deviceID string) []deviceListItem {
+	t.Helper()
+
+	rec := deviceRequest(t, router, http.MethodGet, "/auth/devices", token, deviceID, iphoneUserAgent, "200.1.1.1", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp struct {
+		Devices []deviceListItem `json:"devices"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Devices
+}
+
+// deviceListItem representa um item de GET /auth/devices
+type deviceListItem struct {
+	ID         string `json:"id"`
+	DeviceName string `json:"device_name"`
+	IsCurrent  bool   `json:"is_current"`
+}
+
+func TestRefresh_StableDeviceIDSurvivesIPAndUserAgentChange(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	testdb.UseMockMailer(t)
+	router := setupRouter()
+
+	createTestUser(t, "device_stable@test.com", "password123", "Device User")
+	_, refreshToken := loginWithDevice(t, router, "device_stable@test.com", "password123", "install-abc-123", iphoneUserAgent)
+
+	// Mesmo device ID, IP e User-Agent diferentes (ex: troca de rede e atualização do app)
+	rec := deviceRequest(t, router, http.MethodPost, "/auth/refresh", "", "install-abc-123", iphoneUserAgent+" App/2.0", "177.9.9.9",
+		map[string]string{"refresh_token": refreshToken})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var refreshed struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &refreshed))
+
+	// Outro device ID não pode usar o token
+	rec = deviceRequest(t, router, http.MethodPost, "/auth/refresh", "", "install-xyz-999", iphoneUserAgent, "177.9.9.9",
+		map[string]string{"refresh_token": refreshed.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "DEVICE_MISMATCH")
+}
+
+func TestDevices_RenameAndRevoke(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	testdb.UseMockMailer(t)
+	router := setupRouter()
+
+	createTestUser(t, "device_manage@test.com", "password123", "Device User")
+	phoneToken, _ := loginWithDevice(t, router, "device_manage@test.com", "password123", "phone-1", iphoneUserAgent)
+	_, tabletRefresh := loginWithDevice(t, router, "device_manage@test.com", "password123", "tablet-1", androidUserAgent)
+
+	devices := listDevices(t, router, phoneToken, "phone-1")
+	require.Len(t, devices, 2)
+
+	var phoneID, tabletID string
+	for _, d := range devices {
+		if d.IsCurrent {
+			phoneID = d.ID
+		} else {
+			tabletID = d.ID
+		}
+	}
+	require.NotEmpty(t, phoneID)
+	require.NotEmpty(t, tabletID)
+
+	// Renomear o dispositivo atual
+	rec := deviceRequest(t, router, http.MethodPatch, "/auth/devices/"+phoneID, phoneToken, "phone-1", iphoneUserAgent, "200.1.1.1",
+		map[string]string{"device_name": "iPhone da cozinha"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Novo login no mesmo dispositivo mantém o nome e substitui a sessão anterior
+	phoneToken, _ = loginWithDevice(t, router, "device_manage@test.com", "password123", "phone-1", iphoneUserAgent)
+	devices = listDevices(t, router, phoneToken, "phone-1")
+	require.Len(t, devices, 2)
+	for _, d := range devices {
+		if d.IsCurrent {
+			assert.Equal(t, "iPhone da cozinha", d.DeviceName)
+		}
+	}
+
+	// Desconectar o tablet
+	rec = deviceRequest(t, router, http.MethodDelete, "/auth/devices/"+tabletID, phoneToken, "phone-1", iphoneUserAgent, "200.1.1.1", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = deviceRequest(t, router, http.MethodPost, "/auth/refresh", "", "tablet-1", androidUserAgent, "200.1.1.1",
+		map[string]string{"refresh_token": tabletRefresh})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "REFRESH_TOKEN_REVOKED")
+
+	// Sessão já encerrada ou de outro usuário não é encontrada
+	rec = deviceRequest(t, router, http.MethodDelete, "/auth/devices/"+tabletID, phoneToken, "phone-1", iphoneUserAgent, "200.1.1.1", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	createTestUser(t, "device_other@test.com", "password123", "Other User")
+	otherToken, _ := loginWithDevice(t, router, "device_other@test.com", "password123", "other-1", iphoneUserAgent)
+	rec = deviceRequest(t, router, http.MethodDelete, "/auth/devices/"+devices[0].ID, otherToken, "other-1", iphoneUserAgent, "200.1.1.1", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = deviceRequest(t, router, http.MethodDelete, "/auth/devices/nao-e-uuid", phoneToken, "phone-1", iphoneUserAgent, "200.1.1.1", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestLogin_NewDeviceNotification(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	mock := testdb.UseMockMailer(t)
+	router := setupRouter()
+
+	createTestUser(t, "device_notify@test.com", "password123", "Device User")
+
+	loginWithDevice(t, router, "device_notify@test.com", "password123", "phone-1", iphoneUserAgent)
+	require.Len(t, mock.Messages, 1)
+	assert.Equal(t, "device_notify@test.com", mock.Last().To)
+	assert.Contains(t, mock.Last().Body, "iPhone")
+
+	// Mesmo dispositivo não gera nova notificação
+	loginWithDevice(t, router, "device_notify@test.com", "password123", "phone-1", iphoneUserAgent)
+	assert.Len(t, mock.Messages, 1)
+
+	// Dispositivo novo gera notificação
+	loginWithDevice(t, router, "device_notify@test.com", "password123", "tablet-1", androidUserAgent)
+	require.Len(t, mock.Messages, 2)
+	assert.True(t, strings.Contains(mock.Last().Body, "Android"))
+}
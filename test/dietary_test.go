@@ -0,0 +1,160 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/dietary"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// seedFlaggedIngredient cria um ingrediente com as flags alimentares informadas
+func seedFlaggedIngredient(t *testing.T, name, category string, flags map[string]interface{}) *models.Ingredient {
+	t.Helper()
+
+	ingredient := testdb.SeedIngredient(t, name, category, 100)
+	if len(flags) > 0 {
+		require.NoError(t, database.DB.Model(ingredient).Updates(flags).Error)
+	}
+	return ingredient
+}
+
+// getTestRecipe busca a receita via API
+func getTestRecipe(t *testing.T, router http.Handler, recipeID uint) models.Recipe {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipeID), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var recipe models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &recipe))
+	return recipe
+}
+
+func TestDietary_ClassificationFollowsIngredients(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	admin := createTestUser(t, "diet_admin@test.com", "password123", "Admin")
+	database.DB.Model(&admin).Update("role", "admin")
+	adminToken := loginTestUser(t, router, "diet_admin@test.com", "password123")
+	owner := createTestUser(t, "diet_owner@test.com", "password123", "Chef")
+	ownerToken := loginTestUser(t, router, "diet_owner@test.com", "password123")
+
+	flour := seedFlaggedIngredient(t, "Farinha de trigo", "cereais", map[string]interface{}{"contains_gluten": true})
+	egg := seedFlaggedIngredient(t, "Ovo", "ovos", map[string]interface{}{"contains_egg": true, "animal_origin": true})
+	tomato := seedFlaggedIngredient(t, "Tomate", "vegetais", nil)
+
+	recipe := createTestRecipe(t, owner.ID)
+	assert.False(t, getTestRecipe(t, router, recipe.ID).IsVegan, "receita sem ingredientes não é classificada")
+
+	var flourLine models.RecipeIngredient
+	for _, ingredient := range []*models.Ingredient{flour, egg, tomato} {
+		rec := doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/ingredients", ownerToken, map[string]interface{}{
+			"ingredient_id": ingredient.ID,
+			"quantity":      100,
+			"unit":          "g",
+		})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		if ingredient.ID == flour.ID {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &flourLine))
+		}
+	}
+
+	resp := getTestRecipe(t, router, recipe.ID)
+	assert.False(t, resp.IsVegan)
+	assert.True(t, resp.IsVegetarian)
+	assert.False(t, resp.IsGlutenFree)
+	assert.True(t, resp.IsLactoseFree)
+	assert.Equal(t, []string{models.AllergenGluten, models.AllergenEgg}, resp.Allergens)
+
+	// Remover a farinha torna a receita sem glúten
+	rec := doAuthRequest(t, router, http.MethodDelete, "/recipes/"+itoa(recipe.ID)+"/ingredients/"+itoa(flourLine.ID), ownerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	resp = getTestRecipe(t, router, recipe.ID)
+	assert.True(t, resp.IsGlutenFree)
+	assert.Equal(t, []string{models.AllergenEgg}, resp.Allergens)
+
+	// Corrigir as flags de um ingrediente reclassifica as receitas que o usam
+	rec = doAuthRequest(t, router, http.MethodPut, "/admin/ingredients/"+itoa(tomato.ID), adminToken, map[string]interface{}{
+		"contains_lactose": true,
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	resp = getTestRecipe(t, router, recipe.ID)
+	assert.False(t, resp.IsLactoseFree)
+	assert.Contains(t, resp.Allergens, models.AllergenLactose)
+
+	// A classificação não pode ser enviada pelo cliente
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes", ownerToken, map[string]interface{}{
+		"title":     "Receita vazia",
+		"prep_time": 5,
+		"servings":  1,
+		"is_vegan":  true,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"is_vegan":false`)
+}
+
+func TestListRecipes_DietAndAllergenFilters(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "diet_filter@test.com", "password123", "Chef")
+
+	rice := seedFlaggedIngredient(t, "Arroz", "cereais", nil)
+	peanut := seedFlaggedIngredient(t, "Amendoim", "nozes e sementes", map[string]interface{}{"contains_nuts": true})
+	cheese := seedFlaggedIngredient(t, "Queijo", "laticínios", map[string]interface{}{"contains_lactose": true, "animal_origin": true})
+	chicken := seedFlaggedIngredient(t, "Frango", "carnes", map[string]interface{}{"contains_meat": true, "animal_origin": true})
+
+	withIngredients := func(ingredients ...*models.Ingredient) *models.Recipe {
+		recipe := createTestRecipe(t, user.ID)
+		for _, ingredient := range ingredients {
+			require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: ingredient.ID, Quantity: 100, Unit: "g"}).Error)
+		}
+		_, err := dietary.Recompute(database.DB, recipe.ID)
+		require.NoError(t, err)
+		return recipe
+	}
+
+	veganWithNuts := withIngredients(rice, peanut)
+	vegetarian := withIngredients(rice, cheese)
+	withIngredients(chicken, rice)
+
+	assert.Equal(t, []uint{veganWithNuts.ID}, listRecipeIDs(t, router, "?diet=vegan"))
+	assert.ElementsMatch(t, []uint{veganWithNuts.ID, vegetarian.ID}, listRecipeIDs(t, router, "?diet=vegetarian"))
+	assert.Len(t, listRecipeIDs(t, router, "?diet=vegetarian,lactose_free"), 1)
+	assert.Len(t, listRecipeIDs(t, router, "?exclude_allergens=nuts"), 2)
+	assert.Empty(t, listRecipeIDs(t, router, "?diet=vegetarian&exclude_allergens=nuts,lactose"))
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes?diet=paleo", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes?exclude_allergens=sesame", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// listRecipeIDs lista os IDs das receitas públicas retornadas para a query
+func listRecipeIDs(t *testing.T, router http.Handler, query string) []uint {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes"+query, "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp struct {
+		Data []models.Recipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+
+	ids := make([]uint, len(resp.Data))
+	for i, recipe := range resp.Data {
+		ids[i] = recipe.ID
+	}
+	return ids
+}
@@ -3,7 +3,9 @@ package test
 import (
 	"bytes"
 	"encoding/json"
+	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -77,3 +79,53 @@ func loginTestUser(t *testing.T, router *chi.Mux, email, password string) string
 	return ""
 }
 
+
+// doAuthRequest executa uma requisição JSON autenticada no router
+// body pode ser nil; token vazio envia a requisição sem Authorization
+func doAuthRequest(t *testing.T, router http.Handler, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader *bytes.Buffer
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal body: %v", err)
+		}
+		reader = bytes.NewBuffer(bodyBytes)
+	} else {
+		reader = bytes.NewBuffer(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test-agent")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// postJSON faz um POST autenticado que deve responder 201 e decodifica o recurso criado
+// Usado pelos testes para criar dados pela API (passos, preços, itens da despensa...)
+func postJSON[T any](t *testing.T, router http.Handler, path, token string, body interface{}) T {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodPost, path, token, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST %s failed with status %d: %s", path, rec.Code, rec.Body.String())
+	}
+
+	var created T
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to parse response of POST %s: %v", path, err)
+	}
+	return created
+}
+
+// itoa converte um ID para string (usado na montagem de paths)
+func itoa(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
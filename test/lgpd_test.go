@@ -0,0 +1,245 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/privacy"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// waitForExport aguarda a exportação sair do status processing
+func waitForExport(t *testing.T, router http.Handler, token string, exportID uint) string {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		rec := doAuthRequest(t, router, http.MethodGet, "/users/me/data-export/"+itoa(exportID), token, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var resp struct {
+			Export models.DataExport `json:"export"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		if resp.Export.Status != models.DataExportStatusProcessing {
+			return resp.Export.Status
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatal("exportação não ficou pronta a tempo")
+	return ""
+}
+
+func TestDataExport_DownloadOnce(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "lgpd_export@test.com", "password123", "Titular dos Dados")
+	token := loginTestUser(t, router, "lgpd_export@test.com", "password123")
+	recipe := createTestRecipe(t, user.ID)
+	ingredient := testdb.SeedIngredient(t, "Farinha de trigo", "cereais", 364)
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{
+		RecipeID: recipe.ID, IngredientID: ingredient.ID, Quantity: 200, Unit: "g",
+	}).Error)
+	require.NoError(t, database.DB.Create(&models.FoodAnalysis{
+		JobID: "job-lgpd-1", UserID: user.ID, Status: models.FoodAnalysisStatusCompleted, Result: `{"detected_foods":[]}`,
+	}).Error)
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/users/me/data-export", token, nil)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	var created struct {
+		ExportID uint `json:"export_id"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	assert.Equal(t, models.DataExportStatusReady, waitForExport(t, router, token, created.ExportID))
+
+	// Outro usuário não enxerga a exportação
+	createTestUser(t, "lgpd_other@test.com", "password123", "Outro Usuário")
+	otherToken := loginTestUser(t, router, "lgpd_other@test.com", "password123")
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/data-export/"+itoa(created.ExportID)+"/download", otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Primeiro download entrega o arquivo
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/data-export/"+itoa(created.ExportID)+"/download", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"perfil.json", "receitas.json", "avaliacoes.json", "dispositivos.json", "chaves_api.json", "analises.json"} {
+		assert.Contains(t, files, name)
+	}
+	assert.Contains(t, files["perfil.json"], "lgpd_export@test.com")
+	assert.Contains(t, files["receitas.json"], "Farinha de trigo")
+	assert.Contains(t, files["dispositivos.json"], "device_name")
+	assert.Contains(t, files["analises.json"], "job-lgpd-1")
+	assert.NotContains(t, files["perfil.json"], "argon2id")
+
+	// Segundo download é recusado e o arquivo foi descartado
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/data-export/"+itoa(created.ExportID)+"/download", token, nil)
+	assert.Equal(t, http.StatusGone, rec.Code)
+	assert.Contains(t, rec.Body.String(), "EXPORT_ALREADY_DOWNLOADED")
+
+	var stored models.DataExport
+	require.NoError(t, database.DB.First(&stored, created.ExportID).Error)
+	assert.Empty(t, stored.Archive)
+	assert.NotNil(t, stored.DownloadedAt)
+}
+
+func TestDataExport_ExpiredCleanup(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+
+	user := createTestUser(t, "lgpd_expired@test.com", "password123", "Titular dos Dados")
+	expiresAt := time.Now().Add(-time.Hour)
+	export := models.DataExport{
+		UserID:    user.ID,
+		Status:    models.DataExportStatusReady,
+		Archive:   []byte("zip"),
+		ExpiresAt: &expiresAt,
+	}
+	require.NoError(t, database.DB.Create(&export).Error)
+
+	require.NoError(t, privacy.CleanupExpiredExports())
+
+	var stored models.DataExport
+	require.NoError(t, database.DB.First(&stored, export.ID).Error)
+	assert.Equal(t, models.DataExportStatusExpired, stored.Status)
+	assert.Empty(t, stored.Archive)
+
+	_, err := privacy.ConsumeExport(user.ID, export.ID)
+	assert.ErrorIs(t, err, privacy.ErrExportExpired)
+}
+
+func TestDataExport_StaleProcessingDoesNotBlock(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+
+	user := createTestUser(t, "lgpd_stale@test.com", "password123", "Titular dos Dados")
+
+	// Geração interrompida (ex.: reinício do servidor) fica presa em processing
+	stale := models.DataExport{UserID: user.ID, Status: models.DataExportStatusProcessing}
+	require.NoError(t, database.DB.Create(&stale).Error)
+	require.NoError(t, database.DB.Model(&stale).UpdateColumn("created_at", time.Now().Add(-privacy.ExportProcessingTimeout-time.Minute)).Error)
+
+	export, err := privacy.RequestExport(user.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, stale.ID, export.ID)
+
+	var stored models.DataExport
+	require.NoError(t, database.DB.First(&stored, stale.ID).Error)
+	assert.Equal(t, models.DataExportStatusFailed, stored.Status)
+
+	// Gerações recentes não são afetadas pela limpeza
+	recent := models.DataExport{UserID: user.ID, Status: models.DataExportStatusProcessing}
+	require.NoError(t, database.DB.Create(&recent).Error)
+	require.NoError(t, privacy.CleanupExpiredExports())
+	var kept models.DataExport
+	require.NoError(t, database.DB.First(&kept, recent.ID).Error)
+	assert.Equal(t, models.DataExportStatusProcessing, kept.Status)
+	_, err = privacy.RequestExport(user.ID)
+	assert.ErrorIs(t, err, privacy.ErrExportInProgress)
+}
+
+func TestDataErasure_AppliesPolicy(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "lgpd_erase@test.com", "password123", "Titular dos Dados")
+	token := loginTestUser(t, router, "lgpd_erase@test.com", "password123")
+	author := createTestUser(t, "lgpd_author@test.com", "password123", "Outro Autor")
+
+	ownRecipe := createTestRecipe(t, user.ID)
+	otherRecipe := createTestRecipe(t, author.ID)
+	require.NoError(t, database.DB.Create(&models.Rating{RecipeID: ownRecipe.ID, UserID: author.ID, Score: 4}).Error)
+	require.NoError(t, database.DB.Create(&models.Rating{RecipeID: otherRecipe.ID, UserID: user.ID, Score: 5, Comment: "Ótima receita"}).Error)
+	createTestAPIKey(t, router, token, []string{"recipes:read"})
+
+	// Confirmação obrigatória
+	rec := doAuthRequest(t, router, http.MethodPost, "/users/me/erasure", token, map[string]string{"password": "password123"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/users/me/erasure", token, map[string]string{
+		"password": "password123", "confirmation": "ELIMINAR",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Conteúdo do usuário excluído definitivamente
+	var count int64
+	database.DB.Unscoped().Model(&models.Recipe{}).Where("id = ?", ownRecipe.ID).Count(&count)
+	assert.Zero(t, count)
+	database.DB.Unscoped().Model(&models.Rating{}).Where("recipe_id = ?", ownRecipe.ID).Count(&count)
+	assert.Zero(t, count)
+	database.DB.Unscoped().Model(&models.RefreshToken{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Zero(t, count)
+	database.DB.Unscoped().Model(&models.APIKey{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Zero(t, count)
+
+	// Avaliação em receita de terceiro mantida sem comentário
+	var rating models.Rating
+	require.NoError(t, database.DB.Where("recipe_id = ? AND user_id = ?", otherRecipe.ID, user.ID).First(&rating).Error)
+	assert.Equal(t, 5, rating.Score)
+	assert.Empty(t, rating.Comment)
+
+	// Usuário anonimizado
+	var erased models.User
+	require.NoError(t, database.DB.Unscoped().First(&erased, user.ID).Error)
+	assert.Equal(t, privacy.DeletedUserName, erased.Name)
+	assert.NotEqual(t, "lgpd_erase@test.com", erased.Email)
+	assert.True(t, erased.DeletedAt.Valid)
+
+	// Registro de conformidade sem dados pessoais
+	var record models.DataErasure
+	require.NoError(t, database.DB.Where("user_id = ?", user.ID).First(&record).Error)
+	assert.Equal(t, auth.HashString("lgpd_erase@test.com"), record.EmailHash)
+	assert.Equal(t, privacy.ErasurePolicyVersion, record.PolicyVersion)
+	assert.Contains(t, record.Summary, `"recipes":1`)
+	assert.NotContains(t, record.Summary, "lgpd_erase")
+
+	// Token da sessão não vale mais
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me", token, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAdminListDataErasures_FilterByEmail(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "lgpd_audit@test.com", "password123", "Titular dos Dados")
+	_, err := privacy.EraseUser(t.Context(), user)
+	require.NoError(t, err)
+
+	hashedPassword, _ := auth.HashPassword("password123")
+	testdb.SeedUser(t, "Admin", "lgpd_admin@test.com", hashedPassword, "admin")
+	adminToken := loginTestUser(t, router, "lgpd_admin@test.com", "password123")
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/admin/data-erasures?email=LGPD_AUDIT@test.com", adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"total":1`)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/admin/data-erasures?email=nunca@test.com", adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"total":0`)
+}
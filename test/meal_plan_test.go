@@ -0,0 +1,240 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/handlers"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// createTestMealPlan cria um plano via API e retorna o ID
+func createTestMealPlan(t *testing.T, router http.Handler, token, name string) uint {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/meal-plans", token, map[string]string{"name": name})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var plan models.MealPlan
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &plan))
+	return plan.ID
+}
+
+func TestMealPlan_SharingAndCopyWeek(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "plan_owner@test.com", "password123", "Owner")
+	member := createTestUser(t, "plan_member@test.com", "password123", "Member")
+	other := createTestUser(t, "plan_other@test.com", "password123", "Other")
+	ownerToken := loginTestUser(t, router, "plan_owner@test.com", "password123")
+	memberToken := loginTestUser(t, router, "plan_member@test.com", "password123")
+	otherToken := loginTestUser(t, router, "plan_other@test.com", "password123")
+
+	recipe := createTestRecipe(t, owner.ID)
+	planID := createTestMealPlan(t, router, ownerToken, "Semana da família")
+	planPath := "/meal-plans/" + itoa(planID)
+
+	// Sem acesso: o plano não existe para terceiros
+	rec := doAuthRequest(t, router, http.MethodGet, planPath, otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/members", ownerToken, map[string]string{"email": "plan_member@test.com"})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/members", ownerToken, map[string]string{"email": "plan_member@test.com"})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Membro edita refeições, mas não gerencia o plano
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/slots", memberToken, map[string]interface{}{
+		"date": "2026-10-19", "meal_type": "lunch", "recipe_id": recipe.ID, "servings": 2,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/slots", memberToken, map[string]interface{}{
+		"date": "2026-10-21", "meal_type": "dinner", "recipe_id": recipe.ID,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/slots", memberToken, map[string]interface{}{
+		"date": "19/10/2026", "meal_type": "lunch", "recipe_id": recipe.ID,
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/slots", memberToken, map[string]interface{}{
+		"date": "2026-10-19", "meal_type": "brunch", "recipe_id": recipe.ID,
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPut, planPath, memberToken, map[string]string{"name": "Meu plano"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodDelete, planPath, memberToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Plano compartilhado aparece na listagem do membro
+	rec = doAuthRequest(t, router, http.MethodGet, "/meal-plans", memberToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		MealPlans []models.MealPlan `json:"meal_plans"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.MealPlans, 1)
+	assert.Equal(t, planID, list.MealPlans[0].ID)
+
+	// Copiar a semana (datas normalizadas para a segunda-feira)
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/copy-week", memberToken, map[string]interface{}{
+		"from": "2026-10-22", "to": "2026-10-26",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var copyResp struct {
+		From   string `json:"from"`
+		To     string `json:"to"`
+		Copied int    `json:"copied"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &copyResp))
+	assert.Equal(t, "2026-10-19", copyResp.From)
+	assert.Equal(t, "2026-10-26", copyResp.To)
+	assert.Equal(t, 2, copyResp.Copied)
+
+	// Com replace, a semana de destino é substituída em vez de duplicada
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/copy-week", ownerToken, map[string]interface{}{
+		"from": "2026-10-19", "to": "2026-10-26", "replace": true,
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doAuthRequest(t, router, http.MethodGet, planPath+"?from=2026-10-26&to=2026-11-01", ownerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var plan models.MealPlan
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &plan))
+	require.Len(t, plan.Slots, 2)
+	assert.Equal(t, "2026-10-26", plan.Slots[0].Date)
+	assert.Equal(t, 2, plan.Slots[0].Servings)
+	assert.Equal(t, "2026-10-28", plan.Slots[1].Date)
+	assert.Equal(t, models.MealTypeDinner, plan.Slots[1].MealType)
+
+	// Receita de terceiro que fica privada depois de planejada não é exposta nem replanejada
+	secret := testdb.SeedRecipe(t, "Receita da vizinha", "Segredo de família", other.ID, false)
+	secretLine := testdb.SeedIngredient(t, "Cardamomo", "especiarias", 311)
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: secret.ID, IngredientID: secretLine.ID, Quantity: 5, Unit: "g"}).Error)
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/slots", memberToken, map[string]interface{}{
+		"date": "2026-11-02", "meal_type": "snack", "recipe_id": secret.ID,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NoError(t, database.DB.Model(secret).Update("visibility", models.RecipeVisibilityPrivate).Error)
+
+	rec = doAuthRequest(t, router, http.MethodGet, planPath+"?from=2026-11-02&to=2026-11-08", memberToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "Segredo de família")
+	var hidden models.MealPlan
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hidden))
+	require.Len(t, hidden.Slots, 1)
+	assert.Equal(t, secret.ID, hidden.Slots[0].RecipeID)
+	assert.Nil(t, hidden.Slots[0].Recipe)
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/shopping-lists", memberToken, map[string]interface{}{
+		"meal_plan_id": planID, "from": "2026-11-02", "to": "2026-11-08",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.NotContains(t, rec.Body.String(), "Cardamomo")
+
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/copy-week", memberToken, map[string]interface{}{
+		"from": "2026-11-02", "to": "2026-11-09",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var skippedResp struct {
+		Copied  int `json:"copied"`
+		Skipped int `json:"skipped"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &skippedResp))
+	assert.Zero(t, skippedResp.Copied)
+	assert.Equal(t, 1, skippedResp.Skipped)
+
+	// Receita não listada não é planejada nem com o link de compartilhamento, que não fica gravado no plano
+	shareToken := "vizinha-share-token"
+	unlisted := testdb.SeedRecipe(t, "Receita do link", "Só com link", other.ID, false)
+	require.NoError(t, database.DB.Model(unlisted).Updates(map[string]interface{}{
+		"visibility": models.RecipeVisibilityUnlisted, "share_token": shareToken,
+	}).Error)
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(unlisted.ID)+"?share_token="+shareToken, memberToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = doAuthRequest(t, router, http.MethodPost, planPath+"/slots?share_token="+shareToken, memberToken, map[string]interface{}{
+		"date": "2026-11-03", "meal_type": "lunch", "recipe_id": unlisted.ID,
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Membro pode sair do plano; depois perde o acesso
+	rec = doAuthRequest(t, router, http.MethodDelete, planPath+"/members/"+itoa(member.ID), memberToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = doAuthRequest(t, router, http.MethodGet, planPath, memberToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, planPath, ownerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var slots int64
+	database.DB.Model(&models.MealPlanSlot{}).Where("meal_plan_id = ?", planID).Count(&slots)
+	assert.Zero(t, slots)
+}
+
+func TestMealPlan_NutritionRollups(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "plan_nutri@test.com", "password123", "Nutri")
+	token := loginTestUser(t, router, "plan_nutri@test.com", "password123")
+
+	// Receita de 4 porções: 400g de arroz (128 kcal/100g) = 512 kcal, 128 kcal por porção
+	recipe := createTestRecipe(t, user.ID)
+	require.NoError(t, database.DB.Model(recipe).Update("servings", 4).Error)
+	rice := testdb.SeedIngredient(t, "Arroz", "cereais", 128)
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: rice.ID, Quantity: 400, Unit: "g"}).Error)
+
+	deleted := testdb.SeedRecipe(t, "Removida", "Receita removida", user.ID, false)
+
+	planID := createTestMealPlan(t, router, token, "Dieta")
+	planPath := "/meal-plans/" + itoa(planID)
+	for _, slot := range []map[string]interface{}{
+		{"date": "2026-10-19", "meal_type": "lunch", "recipe_id": recipe.ID, "servings": 2},
+		{"date": "2026-10-19", "meal_type": "dinner", "recipe_id": recipe.ID},
+		{"date": "2026-10-21", "meal_type": "lunch", "recipe_id": recipe.ID, "servings": 1},
+		{"date": "2026-10-21", "meal_type": "snack", "recipe_id": deleted.ID},
+		{"date": "2026-10-27", "meal_type": "lunch", "recipe_id": recipe.ID}, // Outra semana
+	} {
+		rec := doAuthRequest(t, router, http.MethodPost, planPath+"/slots", token, slot)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	require.NoError(t, database.DB.Delete(deleted).Error)
+
+	rec := doAuthRequest(t, router, http.MethodGet, planPath+"/nutrition?week=2026-10-23", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp struct {
+		WeekStart    string                 `json:"week_start"`
+		WeekEnd      string                 `json:"week_end"`
+		Days         []handlers.MealPlanDay `json:"days"`
+		Total        nutrition.Macros       `json:"total"`
+		DailyAverage nutrition.Macros       `json:"daily_average"`
+		PlannedDays  int                    `json:"planned_days"`
+		SkippedSlots []uint                 `json:"skipped_slots"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+
+	assert.Equal(t, "2026-10-19", resp.WeekStart)
+	assert.Equal(t, "2026-10-25", resp.WeekEnd)
+	require.Len(t, resp.Days, 7)
+
+	// Segunda: 2 + 1 porções = 384 kcal; quarta: 1 porção = 128 kcal (receita removida ignorada)
+	assert.InDelta(t, 384.0, resp.Days[0].Total.Calories, 0.001)
+	assert.Equal(t, 2, resp.Days[0].Slots)
+	assert.Zero(t, resp.Days[1].Total.Calories)
+	assert.InDelta(t, 128.0, resp.Days[2].Total.Calories, 0.001)
+	assert.Equal(t, 1, resp.Days[2].Slots)
+
+	assert.InDelta(t, 512.0, resp.Total.Calories, 0.001)
+	assert.Equal(t, 2, resp.PlannedDays)
+	assert.InDelta(t, 256.0, resp.DailyAverage.Calories, 0.001)
+	assert.Len(t, resp.SkippedSlots, 1)
+}
@@ -0,0 +1,180 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// addRecipeIngredientViaAPI adiciona um ingrediente em gramas pela API (recalcula a nutrição por porção)
+func addRecipeIngredientViaAPI(t *testing.T, router http.Handler, token string, recipeID, ingredientID uint, grams float64) models.RecipeIngredient {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipeID)+"/ingredients", token, map[string]interface{}{
+		"ingredient_id": ingredientID, "quantity": grams, "unit": "g",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var line models.RecipeIngredient
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &line))
+	return line
+}
+
+// storedRecipe relê a receita do banco com os valores por porção gravados
+func storedRecipe(t *testing.T, recipeID uint) models.Recipe {
+	t.Helper()
+
+	var recipe models.Recipe
+	require.NoError(t, database.DB.First(&recipe, recipeID).Error)
+	return recipe
+}
+
+func TestRecipeNutrition_PerServingIsPrecomputed(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	admin := createTestUser(t, "goal_admin@test.com", "password123", "Admin")
+	database.DB.Model(&admin).Update("role", "admin")
+	adminToken := loginTestUser(t, router, "goal_admin@test.com", "password123")
+	owner := createTestUser(t, "goal_owner@test.com", "password123", "Chef")
+	ownerToken := loginTestUser(t, router, "goal_owner@test.com", "password123")
+
+	// 200 kcal e 1g de proteína por 100g; receita de 4 porções
+	beans := testdb.SeedIngredient(t, "Feijão", "leguminosas", 200)
+	recipe := createTestRecipe(t, owner.ID)
+
+	line := addRecipeIngredientViaAPI(t, router, ownerToken, recipe.ID, beans.ID, 400)
+	stored := storedRecipe(t, recipe.ID)
+	assert.InDelta(t, 200.0, stored.CaloriesPerServing, 0.001)
+	assert.InDelta(t, 1.0, stored.ProteinPerServing, 0.001)
+
+	// Quantidade alterada
+	rec := doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID)+"/ingredients/"+itoa(line.ID), ownerToken, map[string]float64{"quantity": 800})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.InDelta(t, 400.0, storedRecipe(t, recipe.ID).CaloriesPerServing, 0.001)
+
+	// Porções alteradas
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID), ownerToken, map[string]int{"servings": 8})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.InDelta(t, 200.0, updated.CaloriesPerServing, 0.001)
+	assert.InDelta(t, 200.0, storedRecipe(t, recipe.ID).CaloriesPerServing, 0.001)
+
+	// Macronutrientes do ingrediente alterados no catálogo
+	rec = doAuthRequest(t, router, http.MethodPut, "/admin/ingredients/"+itoa(beans.ID), adminToken, map[string]float64{"protein": 10})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.InDelta(t, 10.0, storedRecipe(t, recipe.ID).ProteinPerServing, 0.001)
+
+	// Valores enviados pelo cliente são ignorados na criação
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes", ownerToken, map[string]interface{}{
+		"title": "Receita nova", "prep_time": 10, "servings": 2, "calories_per_serving": 999,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Zero(t, storedRecipe(t, created.ID).CaloriesPerServing)
+
+	// Removido o ingrediente, a receita fica sem nutrição
+	rec = doAuthRequest(t, router, http.MethodDelete, "/recipes/"+itoa(recipe.ID)+"/ingredients/"+itoa(line.ID), ownerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Zero(t, storedRecipe(t, recipe.ID).CaloriesPerServing)
+}
+
+func TestNutritionGoals_FiltersAndCoverage(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "goal_chef@test.com", "password123", "Chef")
+	ownerToken := loginTestUser(t, router, "goal_chef@test.com", "password123")
+	createTestUser(t, "goal_user@test.com", "password123", "User")
+	token := loginTestUser(t, router, "goal_user@test.com", "password123")
+
+	chicken := testdb.SeedIngredient(t, "Frango", "carnes", 160)
+	require.NoError(t, database.DB.Model(chicken).Update("protein", 30).Error)
+	pasta := testdb.SeedIngredient(t, "Macarrão", "cereais", 370)
+
+	// Receitas de 4 porções: 160 kcal/30g de proteína, 370 kcal/1g de proteína e uma sem ingredientes
+	lean := testdb.SeedRecipe(t, "Frango grelhado", "Leve", owner.ID, false)
+	addRecipeIngredientViaAPI(t, router, ownerToken, lean.ID, chicken.ID, 400)
+	heavy := testdb.SeedRecipe(t, "Macarronada", "Pesada", owner.ID, false)
+	addRecipeIngredientViaAPI(t, router, ownerToken, heavy.ID, pasta.ID, 400)
+	testdb.SeedRecipe(t, "Sem ingredientes", "Vazia", owner.ID, false)
+
+	listIDs := func(path, token string) []uint {
+		t.Helper()
+		rec := doAuthRequest(t, router, http.MethodGet, path, token, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var body struct {
+			Data []models.Recipe `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		ids := make([]uint, 0, len(body.Data))
+		for _, recipe := range body.Data {
+			ids = append(ids, recipe.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []uint{lean.ID}, listIDs("/recipes?max_calories=200", ""))
+	assert.Equal(t, []uint{lean.ID}, listIDs("/recipes?min_protein=25", ""))
+	assert.ElementsMatch(t, []uint{lean.ID, heavy.ID}, listIDs("/recipes?max_calories=370", ""))
+	assert.Empty(t, listIDs("/recipes?max_calories=200&min_carbs=5", ""))
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes?max_calories=abc", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Metas: preset e personalização
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/nutrition-goals", token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPut, "/users/me/nutrition-goals", token, map[string]string{"preset": "unknown"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPut, "/users/me/nutrition-goals", token, map[string]string{"preset": "weight_loss"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var goal models.NutritionGoal
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &goal))
+	assert.Equal(t, "weight_loss", goal.Preset)
+	assert.Equal(t, 1500.0, goal.Calories)
+
+	rec = doAuthRequest(t, router, http.MethodPut, "/users/me/nutrition-goals", token, map[string]interface{}{"preset": "weight_loss", "protein": 120})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	goal = models.NutritionGoal{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &goal))
+	assert.Empty(t, goal.Preset)
+	assert.Equal(t, 120.0, goal.Protein)
+	assert.Equal(t, 1500.0, goal.Calories)
+
+	// Cobertura por porção: 160/1500 kcal = 11%, 30/120g de proteína = 25%
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes?min_protein=25", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Data []models.Recipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Data, 1)
+	require.NotNil(t, body.Data[0].GoalCoverage)
+	assert.Equal(t, 11, *body.Data[0].GoalCoverage.Calories)
+	assert.Equal(t, 25, *body.Data[0].GoalCoverage.Protein)
+
+	// Sem autenticação não há cobertura
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes?min_protein=25", "", nil)
+	var anonymous struct {
+		Data []models.Recipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &anonymous))
+	require.Len(t, anonymous.Data, 1)
+	assert.Nil(t, anonymous.Data[0].GoalCoverage)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, "/users/me/nutrition-goals", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/nutrition-goals", token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,234 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/nutrition"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// measured cria uma medição de nutriente com valor
+func measured(nutrient string, amount float64) models.IngredientNutrient {
+	return models.IngredientNutrient{Nutrient: nutrient, Amount: &amount, Status: models.NutrientStatusMeasured}
+}
+
+// withoutAmount cria uma medição de traço ou não medida
+func withoutAmount(nutrient, status string) models.IngredientNutrient {
+	return models.IngredientNutrient{Nutrient: nutrient, Status: status}
+}
+
+// findNutrient busca um nutriente no perfil da receita
+func findNutrient(t *testing.T, profile []nutrition.NutrientAmount, key string) nutrition.NutrientAmount {
+	t.Helper()
+
+	for _, amount := range profile {
+		if amount.Nutrient == key {
+			return amount
+		}
+	}
+	t.Fatalf("nutriente %s ausente do perfil", key)
+	return nutrition.NutrientAmount{}
+}
+
+func TestRecipeNutrition_ExtendedProfile(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "nutri@test.com", "password123", "Nutri")
+	recipe := createTestRecipe(t, user.ID)
+	require.NoError(t, database.DB.Model(recipe).Update("servings", 2).Error)
+
+	rice := testdb.SeedIngredient(t, "Arroz", "cereais", 128)
+	bean := testdb.SeedIngredient(t, "Feijão", "leguminosas", 76)
+	seedNutrient := func(ingredientID uint, n models.IngredientNutrient) {
+		n.IngredientID = ingredientID
+		require.NoError(t, database.DB.Create(&n).Error)
+	}
+	seedNutrient(rice.ID, measured(models.NutrientSodium, 1))
+	seedNutrient(rice.ID, measured(models.NutrientIron, 0))
+	seedNutrient(rice.ID, withoutAmount(models.NutrientVitaminC, models.NutrientStatusTrace))
+	seedNutrient(rice.ID, withoutAmount(models.NutrientCholesterol, models.NutrientStatusNotMeasured))
+	seedNutrient(bean.ID, measured(models.NutrientSodium, 2))
+	seedNutrient(bean.ID, measured(models.NutrientIron, 1.5))
+	seedNutrient(bean.ID, withoutAmount(models.NutrientVitaminC, models.NutrientStatusTrace))
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: rice.ID, Quantity: 200, Unit: "g"}).Error)
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: bean.ID, Quantity: 100, Unit: "g"}).Error)
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/nutrition", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp struct {
+		Nutrients []nutrition.NutrientAmount `json:"nutrients"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Nutrients, len(models.Nutrients))
+
+	// Medido em todos os ingredientes: 200g * 1mg + 100g * 2mg (por 100g)
+	sodium := findNutrient(t, resp.Nutrients, models.NutrientSodium)
+	assert.Equal(t, models.NutrientStatusMeasured, sodium.Status)
+	require.NotNil(t, sodium.Total)
+	assert.InDelta(t, 4.0, *sodium.Total, 0.001)
+	assert.InDelta(t, 2.0, *sodium.PerServing, 0.001)
+
+	// Zero medido continua sendo valor medido
+	iron := findNutrient(t, resp.Nutrients, models.NutrientIron)
+	assert.Equal(t, models.NutrientStatusMeasured, iron.Status)
+	assert.InDelta(t, 1.5, *iron.Total, 0.001)
+
+	// Apenas traços: sem total, mas sinalizado
+	vitaminC := findNutrient(t, resp.Nutrients, models.NutrientVitaminC)
+	assert.Equal(t, models.NutrientStatusTrace, vitaminC.Status)
+	assert.Nil(t, vitaminC.Total)
+	assert.True(t, vitaminC.ContainsTrace)
+
+	// Nenhuma medição: não medido, nunca zero
+	cholesterol := findNutrient(t, resp.Nutrients, models.NutrientCholesterol)
+	assert.Equal(t, models.NutrientStatusNotMeasured, cholesterol.Status)
+	assert.Nil(t, cholesterol.Total)
+
+	// Medido em um ingrediente e ausente no outro: parcial (limite inferior)
+	seedNutrient(bean.ID, measured(models.NutrientCalcium, 27))
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/nutrition", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	calcium := findNutrient(t, resp.Nutrients, models.NutrientCalcium)
+	assert.Equal(t, nutrition.StatusPartial, calcium.Status)
+	assert.InDelta(t, 27.0, *calcium.Total, 0.001)
+}
+
+func TestAdminIngredient_NutrientProfile(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	admin := createTestUser(t, "nutri_admin@test.com", "password123", "Admin")
+	database.DB.Model(&admin).Update("role", "admin")
+	adminToken := loginTestUser(t, router, "nutri_admin@test.com", "password123")
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/admin/ingredients", adminToken, map[string]interface{}{
+		"name":     "Laranja",
+		"calories": 37,
+		"nutrients": []models.IngredientNutrient{
+			measured(models.NutrientVitaminC, 53.7),
+			withoutAmount(models.NutrientSodium, models.NutrientStatusTrace),
+		},
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created models.Ingredient
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/ingredients/"+itoa(created.ID), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var ingredient models.Ingredient
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ingredient))
+	require.Len(t, ingredient.Nutrients, 2)
+
+	// Traço com valor e nutriente desconhecido são recusados
+	for _, nutrients := range [][]map[string]interface{}{
+		{{"nutrient": models.NutrientSodium, "amount": 0, "status": models.NutrientStatusTrace}},
+		{{"nutrient": "omega_9", "amount": 1}},
+	} {
+		rec = doAuthRequest(t, router, http.MethodPut, "/admin/ingredients/"+itoa(created.ID), adminToken, map[string]interface{}{
+			"nutrients": nutrients,
+		})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+
+	// A lista enviada substitui o perfil atual
+	rec = doAuthRequest(t, router, http.MethodPut, "/admin/ingredients/"+itoa(created.ID), adminToken, map[string]interface{}{
+		"calories":  40,
+		"nutrients": []models.IngredientNutrient{withoutAmount(models.NutrientCholesterol, models.NutrientStatusNotMeasured)},
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated models.Ingredient
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, 40.0, updated.Calories)
+	require.Len(t, updated.Nutrients, 1)
+	assert.Equal(t, models.NutrientStatusNotMeasured, updated.Nutrients[0].Status)
+	assert.Nil(t, updated.Nutrients[0].Amount)
+}
+
+func TestRecipeNutrition_AnvisaLabel(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "rotulo@test.com", "password123", "Rótulo")
+	recipe := createTestRecipe(t, user.ID)
+	require.NoError(t, database.DB.Model(recipe).Update("servings", 2).Error)
+
+	sugar := models.Ingredient{Name: "Açúcar refinado", Calories: 387, Carbs: 99.5, Nutrients: []models.IngredientNutrient{
+		measured(models.NutrientTotalSugars, 99.5),
+		measured(models.NutrientAddedSugars, 99.5),
+		withoutAmount(models.NutrientSodium, models.NutrientStatusTrace),
+	}}
+	flour := models.Ingredient{Name: "Farinha de trigo", Calories: 360, Protein: 9.8, Carbs: 75.1, Fat: 1.4, Fiber: 2.3, Nutrients: []models.IngredientNutrient{
+		measured(models.NutrientTotalSugars, 0),
+		measured(models.NutrientAddedSugars, 0),
+		measured(models.NutrientSodium, 1),
+		measured(models.NutrientSaturatedFat, 0.3),
+		withoutAmount(models.NutrientTransFat, models.NutrientStatusNotMeasured),
+	}}
+	require.NoError(t, database.DB.Create(&sugar).Error)
+	require.NoError(t, database.DB.Create(&flour).Error)
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: sugar.ID, Quantity: 100, Unit: "g"}).Error)
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: flour.ID, Quantity: 400, Unit: "g"}).Error)
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/nutrition", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp struct {
+		Label nutrition.Label `json:"label"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	label := resp.Label
+	assert.Equal(t, 250.0, label.PortionGrams)
+
+	rows := make(map[string]nutrition.LabelRow)
+	for _, row := range label.Rows {
+		rows[row.Nutrient] = row
+	}
+	require.Len(t, rows, 10)
+
+	// 1827 kcal em 500g: 365,4 por 100g (inteiro) e 913,5 por porção
+	energy := rows[nutrition.LabelEnergy]
+	assert.Equal(t, 365.0, *energy.Per100g)
+	assert.Equal(t, 914.0, *energy.PerPortion)
+	assert.Equal(t, 46, *energy.DailyValue)
+
+	// Abaixo de 10: uma casa decimal; não significativo: zero
+	assert.Equal(t, 7.8, *rows[nutrition.LabelProtein].Per100g)
+	assert.Equal(t, 0.0, *rows[models.NutrientSodium].Per100g)
+	assert.Equal(t, 0.2, *rows[models.NutrientSaturatedFat].Per100g)
+
+	// Açúcares totais e gorduras trans não têm %VD
+	assert.Nil(t, rows[models.NutrientTotalSugars].DailyValue)
+	assert.Equal(t, 100, *rows[models.NutrientAddedSugars].DailyValue)
+
+	// Sem medição não vira zero
+	assert.Nil(t, rows[models.NutrientTransFat].Per100g)
+	assert.ElementsMatch(t, []string{models.NutrientSaturatedFat, models.NutrientTransFat}, label.Incomplete)
+
+	// 19,9g de açúcar adicionado por 100g: "alto em"
+	assert.Equal(t, []string{models.NutrientAddedSugars}, label.HighIn)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/nutrition/label.svg", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "image/svg+xml")
+	svg := rec.Body.String()
+	assert.Contains(t, svg, "<svg")
+	assert.Contains(t, svg, "INFORMAÇÃO NUTRICIONAL")
+	assert.Contains(t, svg, "ALTO EM")
+	assert.Contains(t, svg, "AÇÚCAR ADICIONADO")
+	assert.Contains(t, svg, "7,8")
+
+	// Receitas não visíveis não têm tabela
+	require.NoError(t, database.DB.Model(recipe).Update("visibility", models.RecipeVisibilityPrivate).Error)
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/nutrition/label.svg", "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,195 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/handlers"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/pantry"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// inDays retorna a data daqui a n dias no formato das validades
+func inDays(n int) string {
+	return time.Now().AddDate(0, 0, n).Format(models.DateLayout)
+}
+
+// pantryQuantities soma as quantidades da despensa do usuário por ingrediente
+func pantryQuantities(t *testing.T, userID uint) map[uint]float64 {
+	t.Helper()
+
+	var items []models.PantryItem
+	require.NoError(t, database.DB.Where("user_id = ?", userID).Find(&items).Error)
+
+	totals := make(map[uint]float64)
+	for _, item := range items {
+		totals[item.IngredientID] += item.Quantity
+	}
+	return totals
+}
+
+func TestPantry_ConsumeExpiringAndCook(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "pantry@test.com", "password123", "Pantry")
+	token := loginTestUser(t, router, "pantry@test.com", "password123")
+
+	rice := testdb.SeedIngredient(t, "Arroz", "cereais", 128)
+	milk := testdb.SeedIngredient(t, "Leite", "laticínios", 61)
+
+	// Unidade normalizada na entrada
+	late := postJSON[models.PantryItem](t, router, "/pantry", token, map[string]interface{}{
+		"ingredient_id": rice.ID, "quantity": 1, "unit": "kg", "expires_on": inDays(20),
+	})
+	assert.Equal(t, 1000.0, late.Quantity)
+	assert.Equal(t, "g", late.Unit)
+	soon := postJSON[models.PantryItem](t, router, "/pantry", token, map[string]interface{}{
+		"ingredient_id": rice.ID, "quantity": 300, "unit": "g", "expires_on": inDays(2),
+	})
+	postJSON[models.PantryItem](t, router, "/pantry", token, map[string]interface{}{
+		"ingredient_id": milk.ID, "quantity": 1, "unit": "l", "expires_on": inDays(-1),
+	})
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/pantry", token, map[string]interface{}{
+		"ingredient_id": rice.ID, "quantity": 1, "unit": "kg", "expires_on": "20/10/2026",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Vencidos e vencendo em até 3 dias
+	rec = doAuthRequest(t, router, http.MethodGet, "/pantry/expiring", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var expiring struct {
+		Items []handlers.ExpiringPantryItem `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &expiring))
+	require.Len(t, expiring.Items, 2)
+	assert.Equal(t, milk.ID, expiring.Items[0].IngredientID)
+	assert.True(t, expiring.Items[0].Expired)
+	assert.Equal(t, -1, expiring.Items[0].DaysLeft)
+	assert.Equal(t, soon.ID, expiring.Items[1].ID)
+	assert.Equal(t, 2, expiring.Items[1].DaysLeft)
+
+	// O lote que vence antes é consumido primeiro e removido quando zera
+	rec = doAuthRequest(t, router, http.MethodPost, "/pantry/consume", token, map[string]interface{}{
+		"ingredient_id": rice.ID, "quantity": 0.5, "unit": "kg",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var consumption pantry.Consumption
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &consumption))
+	assert.Equal(t, 500.0, consumption.Consumed)
+	assert.Zero(t, consumption.Missing)
+
+	var remaining []models.PantryItem
+	database.DB.Where("user_id = ? AND ingredient_id = ?", user.ID, rice.ID).Find(&remaining)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, late.ID, remaining[0].ID)
+	assert.Equal(t, 800.0, remaining[0].Quantity)
+
+	// Preparar a receita (4 porções) em dobro: 2 x 300g de arroz e 2 x 1 xícara de leite
+	recipe := createTestRecipe(t, user.ID)
+	addTestRecipeIngredient(t, recipe.ID, rice.ID, 300, "g")
+	addTestRecipeIngredient(t, recipe.ID, milk.ID, 1, "xícara")
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/cook", token, map[string]int{"servings": 8})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var cooked struct {
+		Servings int                  `json:"servings"`
+		Pantry   []pantry.Consumption `json:"pantry"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cooked))
+	assert.Equal(t, 8, cooked.Servings)
+	require.Len(t, cooked.Pantry, 2)
+
+	totals := pantryQuantities(t, user.ID)
+	assert.InDelta(t, 200.0, totals[rice.ID], 0.001)
+	assert.InDelta(t, 520.0, totals[milk.ID], 0.001)
+
+	// Sem corpo, usa as porções da receita; o que falta aparece em missing
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/cook", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cooked))
+	assert.Equal(t, 4, cooked.Servings)
+	assert.Equal(t, 100.0, cooked.Pantry[0].Missing)
+
+	_, hasRice := pantryQuantities(t, user.ID)[rice.ID]
+	assert.False(t, hasRice)
+
+	// Lotes de outros usuários não são acessíveis
+	createTestUser(t, "pantry_other@test.com", "password123", "Other")
+	otherToken := loginTestUser(t, router, "pantry_other@test.com", "password123")
+	rec = doAuthRequest(t, router, http.MethodDelete, "/pantry/"+itoa(late.ID), otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPantry_RanksRecipesUsingExpiringItems(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	createTestUser(t, "pantry_rank@test.com", "password123", "Ranker")
+	author := createTestUser(t, "pantry_author@test.com", "password123", "Author")
+	token := loginTestUser(t, router, "pantry_rank@test.com", "password123")
+
+	spinach := testdb.SeedIngredient(t, "Espinafre", "vegetais", 23)
+	cream := testdb.SeedIngredient(t, "Creme de leite", "laticínios", 242)
+	pasta := testdb.SeedIngredient(t, "Macarrão", "cereais", 371)
+	yogurt := testdb.SeedIngredient(t, "Iogurte", "laticínios", 61)
+
+	postJSON[models.PantryItem](t, router, "/pantry", token, map[string]interface{}{"ingredient_id": spinach.ID, "quantity": 200, "unit": "g", "expires_on": inDays(1)})
+	postJSON[models.PantryItem](t, router, "/pantry", token, map[string]interface{}{"ingredient_id": cream.ID, "quantity": 200, "unit": "g", "expires_on": inDays(4)})
+	postJSON[models.PantryItem](t, router, "/pantry", token, map[string]interface{}{"ingredient_id": pasta.ID, "quantity": 500, "unit": "g"})
+	postJSON[models.PantryItem](t, router, "/pantry", token, map[string]interface{}{"ingredient_id": yogurt.ID, "quantity": 170, "unit": "g", "expires_on": inDays(-2)})
+
+	// Usa os dois itens vencendo
+	both := createTestRecipe(t, author.ID)
+	addTestRecipeIngredient(t, both.ID, spinach.ID, 100, "g")
+	addTestRecipeIngredient(t, both.ID, cream.ID, 100, "g")
+	// Usa só o creme de leite, mas tudo está na despensa
+	creamy := createTestRecipe(t, author.ID)
+	addTestRecipeIngredient(t, creamy.ID, cream.ID, 100, "g")
+	addTestRecipeIngredient(t, creamy.ID, pasta.ID, 250, "g")
+	// Usa só o espinafre, que vence antes
+	salad := createTestRecipe(t, author.ID)
+	addTestRecipeIngredient(t, salad.ID, spinach.ID, 100, "g")
+	// Iogurte vencido não gera sugestão
+	smoothie := createTestRecipe(t, author.ID)
+	addTestRecipeIngredient(t, smoothie.ID, yogurt.ID, 170, "g")
+	// Rascunho de outro autor não aparece
+	draft := testdb.SeedRecipe(t, "Rascunho", "Privado", author.ID, false)
+	require.NoError(t, database.DB.Model(draft).Updates(map[string]interface{}{"status": models.RecipeStatusDraft}).Error)
+	addTestRecipeIngredient(t, draft.ID, spinach.ID, 100, "g")
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/pantry/recipes", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var result struct {
+		Recipes []pantry.RecipeMatch `json:"recipes"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+
+	ids := make([]uint, 0, len(result.Recipes))
+	for _, match := range result.Recipes {
+		ids = append(ids, match.Recipe.ID)
+	}
+	assert.Equal(t, []uint{both.ID, salad.ID, creamy.ID}, ids)
+	assert.Equal(t, []string{"Espinafre", "Creme de leite"}, result.Recipes[0].ExpiringIngredients)
+	assert.Equal(t, inDays(1), result.Recipes[0].SoonestExpiry)
+	assert.Equal(t, 1.0, result.Recipes[2].PantryCoverage)
+
+	// Janela menor deixa o creme de leite de fora
+	rec = doAuthRequest(t, router, http.MethodGet, "/pantry/recipes?days=2", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	require.Len(t, result.Recipes, 2)
+	assert.Equal(t, []string{"Espinafre"}, result.Recipes[0].ExpiringIngredients)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/pantry/recipes?days=90", token, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+}
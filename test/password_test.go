@@ -1,8 +1,11 @@
 package test
 
 import (
+	"strings"
 	"testing"
 
+	"golang.org/x/crypto/bcrypt"
+
 	"github.com/davidsonmarra/receitas-app/pkg/auth"
 )
 
@@ -77,3 +80,82 @@ func TestHashPassword_DifferentHashes(t *testing.T) {
 		t.Error("segundo hash deveria validar a senha")
 	}
 }
+
+func TestHashPassword_Argon2idFormat(t *testing.T) {
+	hash, err := auth.HashPassword("tempero-secreto-42")
+	if err != nil {
+		t.Fatalf("erro ao gerar hash: %v", err)
+	}
+
+	if !strings.HasPrefix(hash, "$argon2id$v=19$") {
+		t.Errorf("hash deveria usar formato argon2id versionado, obteve %s", hash)
+	}
+
+	if auth.NeedsRehash(hash) {
+		t.Error("hash recém-gerado não deveria precisar de rehash")
+	}
+}
+
+func TestCheckPassword_LegacyBcrypt(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("senha-antiga"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("erro ao gerar hash bcrypt: %v", err)
+	}
+
+	if !auth.CheckPassword(string(legacy), "senha-antiga") {
+		t.Error("hash bcrypt legado deveria continuar válido")
+	}
+
+	if auth.CheckPassword(string(legacy), "senha-errada") {
+		t.Error("senha incorreta não deveria ser válida para hash legado")
+	}
+
+	if !auth.NeedsRehash(string(legacy)) {
+		t.Error("hash bcrypt legado deveria precisar de rehash")
+	}
+}
+
+func TestNeedsRehash_CostChanged(t *testing.T) {
+	hash, err := auth.HashPassword("tempero-secreto-42")
+	if err != nil {
+		t.Fatalf("erro ao gerar hash: %v", err)
+	}
+
+	original := auth.PasswordHashParams
+	defer func() { auth.PasswordHashParams = original }()
+
+	auth.PasswordHashParams.Iterations = original.Iterations + 1
+	if !auth.NeedsRehash(hash) {
+		t.Error("hash com custo antigo deveria precisar de rehash")
+	}
+
+	// Hash antigo continua verificável mesmo após mudança de custo
+	if !auth.CheckPassword(hash, "tempero-secreto-42") {
+		t.Error("hash com custo antigo deveria continuar válido")
+	}
+}
+
+func TestValidatePasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		expected error
+	}{
+		{"senha válida", "tempero-secreto-42", nil},
+		{"muito curta", "abc12", auth.ErrPasswordTooShort},
+		{"muito longa", strings.Repeat("a", 129), auth.ErrPasswordTooLong},
+		{"senha comum", "senha123", auth.ErrPasswordTooCommon},
+		{"senha comum com maiúsculas", "Password123", auth.ErrPasswordTooCommon},
+		{"contém nome", "mariana2024!", auth.ErrPasswordContainsPersonalInfo},
+		{"contém e-mail", "xx-cozinheira-xx", auth.ErrPasswordContainsPersonalInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := auth.ValidatePasswordPolicy(tt.password, "Mariana Souza", "cozinheira@test.com")
+			if err != tt.expected {
+				t.Errorf("esperado %v, obteve %v", tt.expected, err)
+			}
+		})
+	}
+}
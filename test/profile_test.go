@@ -0,0 +1,171 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+func TestGetAndUpdateMe(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	createTestUser(t, "me@test.com", "password123", "Profile User")
+	token := loginTestUser(t, router, "me@test.com", "password123")
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/users/me", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "me@test.com")
+
+	rec = doAuthRequest(t, router, http.MethodPatch, "/users/me", token, map[string]string{
+		"name": "Nome Atualizado",
+		"bio":  "Cozinheira de fim de semana",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var user models.User
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &user))
+	assert.Equal(t, "Nome Atualizado", user.Name)
+	assert.Equal(t, "Cozinheira de fim de semana", user.Bio)
+
+	// Sem autenticação
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestChangePassword(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "changepw@test.com", "password123", "Password User")
+	token := loginTestUser(t, router, "changepw@test.com", "password123")
+
+	// Senha atual incorreta
+	rec := doAuthRequest(t, router, http.MethodPost, "/users/me/password", token, map[string]string{
+		"current_password": "errada123",
+		"new_password":     "tempero-secreto-42",
+	})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Nova senha comum é rejeitada
+	rec = doAuthRequest(t, router, http.MethodPost, "/users/me/password", token, map[string]string{
+		"current_password": "password123",
+		"new_password":     "senha12345",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/users/me/password", token, map[string]string{
+		"current_password": "password123",
+		"new_password":     "tempero-secreto-42",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp["access_token"])
+
+	// Apenas a nova sessão permanece ativa
+	tokens, err := auth.GetUserActiveTokens(user.ID)
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	// Token antigo deixa de funcionar
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me", token, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	loginTestUser(t, router, "changepw@test.com", "tempero-secreto-42")
+}
+
+func TestChangeEmail_RequiresVerification(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+	mail := testdb.UseMockMailer(t)
+
+	user := createTestUser(t, "old@test.com", "password123", "Email User")
+	token := loginTestUser(t, router, "old@test.com", "password123")
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/users/me/email", token, map[string]string{
+		"new_email": "new@test.com",
+		"password":  "password123",
+	})
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	// E-mail ainda não foi alterado
+	var stored models.User
+	database.DB.First(&stored, user.ID)
+	assert.Equal(t, "old@test.com", stored.Email)
+	assert.Equal(t, "new@test.com", stored.PendingEmail)
+
+	msg := mail.Last()
+	require.NotNil(t, msg)
+	assert.Equal(t, "new@test.com", msg.To)
+	verificationToken := regexp.MustCompile(`(?m)^\s*([A-Za-z0-9_=-]{40,})\s*$`).FindStringSubmatch(msg.Body)
+	require.Len(t, verificationToken, 2)
+
+	// Token inválido
+	rec = doAuthRequest(t, router, http.MethodPost, "/users/me/email/verify", token, map[string]string{"token": "invalido"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/users/me/email/verify", token, map[string]string{"token": verificationToken[1]})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	database.DB.First(&stored, user.ID)
+	assert.Equal(t, "new@test.com", stored.Email)
+	assert.Empty(t, stored.PendingEmail)
+
+	// Endereço antigo é avisado
+	assert.Equal(t, "old@test.com", mail.Last().To)
+}
+
+func TestDeleteMe_AnonymizesRatings(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "owner@test.com", "password123", "Recipe Owner")
+	recipe := createTestRecipe(t, owner.ID)
+
+	user := createTestUser(t, "leaving@test.com", "password123", "Leaving User")
+	token := loginTestUser(t, router, "leaving@test.com", "password123")
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/ratings", token, map[string]interface{}{
+		"score":   4,
+		"comment": "Comentário pessoal",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, "/users/me", token, map[string]string{"password": "errada123"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, "/users/me", token, map[string]string{"password": "password123"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Usuário soft-deleted e anonimizado
+	var deleted models.User
+	require.NoError(t, database.DB.Unscoped().First(&deleted, user.ID).Error)
+	assert.True(t, deleted.DeletedAt.Valid)
+	assert.NotEqual(t, "leaving@test.com", deleted.Email)
+	assert.NotEqual(t, "Leaving User", deleted.Name)
+
+	// Avaliação mantida (nota), sem comentário
+	var rating models.Rating
+	require.NoError(t, database.DB.Where("user_id = ?", user.ID).First(&rating).Error)
+	assert.Equal(t, 4, rating.Score)
+	assert.Empty(t, rating.Comment)
+
+	// E-mail liberado para novo cadastro e login antigo não funciona mais
+	rec = doAuthRequest(t, router, http.MethodPost, "/users/login", "", map[string]string{
+		"email":    "leaving@test.com",
+		"password": "password123",
+	})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	createTestUser(t, "leaving@test.com", "password123", "Returning User")
+}
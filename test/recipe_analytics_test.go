@@ -0,0 +1,204 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/analytics"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/privacy"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// postRecipeEvent envia um evento de engajamento a partir do IP informado
+func postRecipeEvent(t *testing.T, router http.Handler, recipeID uint, token, ip, eventType string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]string{"type": eventType})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/recipes/"+itoa(recipeID)+"/events", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", ip)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// eventRecorded indica se o evento contou (false quando duplicado na janela)
+func eventRecorded(t *testing.T, rec *httptest.ResponseRecorder) bool {
+	t.Helper()
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	var body struct {
+		Recorded bool `json:"recorded"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body.Recorded
+}
+
+func TestRecipeEvents_DeduplicatedAndReportedToAuthor(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	author := createTestUser(t, "events_author@test.com", "password123", "Chef")
+	authorToken := loginTestUser(t, router, "events_author@test.com", "password123")
+	reader := createTestUser(t, "events_reader@test.com", "password123", "Reader")
+	readerToken := loginTestUser(t, router, "events_reader@test.com", "password123")
+
+	recipe := testdb.SeedRecipe(t, "Pão de queijo", "Receita mineira", author.ID, false)
+	quiet := testdb.SeedRecipe(t, "Broa de milho", "Sem visitas", author.ID, false)
+	private := testdb.SeedRecipe(t, "Receita secreta", "Privada", author.ID, false)
+	require.NoError(t, database.DB.Model(private).Update("visibility", models.RecipeVisibilityPrivate).Error)
+
+	// httptest usa 192.0.2.1 como endereço da conexão; aqui ele faz o papel do proxy
+	t.Setenv("TRUSTED_PROXIES", "192.0.2.1")
+
+	// Anônimos contam uma vez por IP na janela
+	assert.True(t, eventRecorded(t, postRecipeEvent(t, router, recipe.ID, "", "203.0.113.1", models.RecipeEventView)))
+	assert.False(t, eventRecorded(t, postRecipeEvent(t, router, recipe.ID, "", "203.0.113.1", models.RecipeEventView)))
+	assert.True(t, eventRecorded(t, postRecipeEvent(t, router, recipe.ID, "", "203.0.113.2", models.RecipeEventView)))
+
+	// Entradas adicionadas pelo cliente à esquerda do header são ignoradas
+	assert.False(t, eventRecorded(t, postRecipeEvent(t, router, recipe.ID, "", "198.51.100.7, 203.0.113.1", models.RecipeEventView)))
+
+	// Usuários autenticados contam uma vez por conta, independente do IP
+	assert.True(t, eventRecorded(t, postRecipeEvent(t, router, recipe.ID, readerToken, "203.0.113.3", models.RecipeEventView)))
+	assert.False(t, eventRecorded(t, postRecipeEvent(t, router, recipe.ID, readerToken, "203.0.113.4", models.RecipeEventView)))
+	assert.True(t, eventRecorded(t, postRecipeEvent(t, router, recipe.ID, readerToken, "203.0.113.3", models.RecipeEventCook)))
+	assert.False(t, eventRecorded(t, postRecipeEvent(t, router, recipe.ID, readerToken, "203.0.113.3", models.RecipeEventCook)))
+
+	// O autor não infla as próprias estatísticas
+	assert.False(t, eventRecorded(t, postRecipeEvent(t, router, recipe.ID, authorToken, "203.0.113.5", models.RecipeEventView)))
+
+	// Sem proxy confiável, X-Forwarded-For é ignorado: trocar o header não burla a janela
+	t.Setenv("TRUSTED_PROXIES", "")
+	assert.True(t, eventRecorded(t, postRecipeEvent(t, router, quiet.ID, "", "198.51.100.1", models.RecipeEventCook)))
+	assert.False(t, eventRecorded(t, postRecipeEvent(t, router, quiet.ID, "", "198.51.100.2", models.RecipeEventCook)))
+
+	rec := postRecipeEvent(t, router, recipe.ID, "", "203.0.113.1", "share")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = postRecipeEvent(t, router, private.ID, readerToken, "203.0.113.3", models.RecipeEventView)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Preparar pela despensa também conta como "fiz esta receita"
+	createTestUser(t, "events_cook@test.com", "password123", "Cook")
+	cookToken := loginTestUser(t, router, "events_cook@test.com", "password123")
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/cook", cookToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	require.NoError(t, database.DB.Create(&models.Rating{UserID: reader.ID, RecipeID: recipe.ID, Score: 4}).Error)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/recipes/analytics?days=7", authorToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var report analytics.AuthorReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+
+	today := time.Now().UTC().Format(analytics.DayFormat)
+	assert.Equal(t, today, report.To)
+	assert.Equal(t, 3, report.Totals.Views)
+	assert.Equal(t, 3, report.Totals.Cooks)
+	assert.Equal(t, 1, report.Totals.Ratings)
+	assert.Equal(t, 4.0, report.Totals.AverageRating)
+
+	require.Len(t, report.Daily, 7)
+	assert.Equal(t, today, report.Daily[6].Date)
+	assert.Equal(t, 3, report.Daily[6].Views)
+	assert.Zero(t, report.Daily[0].Views)
+
+	require.Len(t, report.Recipes, 3)
+	assert.Equal(t, recipe.ID, report.Recipes[0].RecipeID)
+	assert.Equal(t, "Pão de queijo", report.Recipes[0].Title)
+	assert.Equal(t, 2, report.Recipes[0].Cooks)
+	assert.ElementsMatch(t, []uint{quiet.ID, private.ID}, []uint{report.Recipes[1].RecipeID, report.Recipes[2].RecipeID})
+
+	// Leitores não veem as receitas de outros autores no relatório
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/recipes/analytics", readerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var empty analytics.AuthorReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &empty))
+	assert.Empty(t, empty.Recipes)
+	assert.Len(t, empty.Daily, 30)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/recipes/analytics?days=91", authorToken, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Eliminação (LGPD) remove os eventos do leitor, mas mantém os totais da receita de terceiros
+	_, err := privacy.EraseUser(t.Context(), reader)
+	require.NoError(t, err)
+	var events, stats int64
+	database.DB.Model(&models.RecipeEvent{}).Where("actor = ?", analytics.UserActor(reader.ID)).Count(&events)
+	database.DB.Model(&models.RecipeDailyStat{}).Where("recipe_id = ?", recipe.ID).Count(&stats)
+	assert.Zero(t, events)
+	assert.Equal(t, int64(1), stats)
+}
+
+func TestRecipeEvents_TrendingSortDecaysOverTime(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	author := createTestUser(t, "trending_author@test.com", "password123", "Chef")
+	reader := createTestUser(t, "trending_reader@test.com", "password123", "Reader")
+
+	fresh := testdb.SeedRecipe(t, "Em alta hoje", "Poucas visitas recentes", author.ID, false)
+	faded := testdb.SeedRecipe(t, "Sucesso antigo", "Muitas visitas há 10 dias", author.ID, false)
+	rated := testdb.SeedRecipe(t, "Recém avaliada", "Só uma avaliação", author.ID, false)
+	forgotten := testdb.SeedRecipe(t, "Esquecida", "Visitas fora da janela", author.ID, false)
+
+	now := time.Now().UTC()
+	day := func(daysAgo int) string {
+		return now.AddDate(0, 0, -daysAgo).Format(analytics.DayFormat)
+	}
+	for _, stat := range []models.RecipeDailyStat{
+		{RecipeID: fresh.ID, Day: day(0), Views: 8, Cooks: 1},
+		{RecipeID: faded.ID, Day: day(10), Views: 200},
+		{RecipeID: forgotten.ID, Day: day(20), Views: 1000},
+	} {
+		require.NoError(t, database.DB.Create(&stat).Error)
+	}
+	require.NoError(t, database.DB.Create(&models.Rating{UserID: reader.ID, RecipeID: rated.ID, Score: 5}).Error)
+
+	before := storedRecipe(t, faded.ID).UpdatedAt
+	count, err := analytics.RefreshTrending(database.DB, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// 200 visitas há 10 dias valem menos que 13 pontos de hoje (meia-vida de 48 h)
+	assert.Greater(t, storedRecipe(t, fresh.ID).TrendingScore, storedRecipe(t, faded.ID).TrendingScore)
+	assert.Greater(t, storedRecipe(t, faded.ID).TrendingScore, storedRecipe(t, rated.ID).TrendingScore)
+	assert.Zero(t, storedRecipe(t, forgotten.ID).TrendingScore)
+	assert.Equal(t, before, storedRecipe(t, faded.ID).UpdatedAt)
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes?sort_by=trending", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body struct {
+		Data []models.Recipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Data, 4)
+	assert.Equal(t, []uint{fresh.ID, faded.ID, rated.ID, forgotten.ID}, []uint{body.Data[0].ID, body.Data[1].ID, body.Data[2].ID, body.Data[3].ID})
+
+	// Sem engajamento recente, o score volta a zero no recálculo
+	_, err = analytics.RefreshTrending(database.DB, now.AddDate(0, 0, 30))
+	require.NoError(t, err)
+	assert.Zero(t, storedRecipe(t, fresh.ID).TrendingScore)
+
+	// Eventos cujas janelas terminaram são descartados
+	require.NoError(t, database.DB.Create(&models.RecipeEvent{
+		RecipeID: fresh.ID, Type: models.RecipeEventView, Actor: analytics.IPActor("203.0.113.9"), WindowStart: now.Add(-48 * time.Hour),
+	}).Error)
+	purged, err := analytics.PurgeEvents(database.DB, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
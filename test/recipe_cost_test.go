@@ -0,0 +1,200 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/cost"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+func TestRecipeCost_UnitConversionRegionAndMissingPrices(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	admin := createTestUser(t, "cost_admin@test.com", "password123", "Admin")
+	database.DB.Model(&admin).Update("role", "admin")
+	adminToken := loginTestUser(t, router, "cost_admin@test.com", "password123")
+	owner := createTestUser(t, "cost_owner@test.com", "password123", "Chef")
+
+	rice := testdb.SeedIngredient(t, "Arroz", "cereais", 130)
+	eggs := testdb.SeedIngredient(t, "Ovo", "ovos", 150)
+	garlic := testdb.SeedIngredient(t, "Alho", "temperos", 100)
+	salt := testdb.SeedIngredient(t, "Sal", "temperos", 0)
+
+	// Receita de 4 porções: 500g de arroz, 4 ovos, 2 dentes de alho e sal sem preço
+	recipe := createTestRecipe(t, owner.ID)
+	addTestRecipeIngredient(t, recipe.ID, rice.ID, 500, "g")
+	addTestRecipeIngredient(t, recipe.ID, eggs.ID, 4, "un")
+	addTestRecipeIngredient(t, recipe.ID, garlic.ID, 2, "dente")
+	addTestRecipeIngredient(t, recipe.ID, salt.ID, 5, "g")
+
+	// Arroz: R$ 6,00/kg no país e R$ 8,00/kg em SP; ovos: R$ 12,00 a dúzia; alho vendido por kg
+	price := postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(rice.ID)+"/prices", adminToken, map[string]interface{}{"price": 6, "quantity": 1, "unit": "kg"})
+	assert.Equal(t, "BR", price.Region)
+	assert.Equal(t, "g", price.BaseUnit)
+	assert.InDelta(t, 0.006, price.UnitPrice, 0.000001)
+	postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(rice.ID)+"/prices", adminToken, map[string]interface{}{"region": "sp", "price": 8, "quantity": 1, "unit": "kg"})
+	postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(eggs.ID)+"/prices", adminToken, map[string]interface{}{"price": 12, "quantity": 12, "unit": "un"})
+	postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(garlic.ID)+"/prices", adminToken, map[string]interface{}{"price": 30, "quantity": 1, "unit": "kg"})
+
+	// Preço futuro ainda não vale
+	postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(eggs.ID)+"/prices", adminToken, map[string]interface{}{
+		"price": 24, "quantity": 12, "unit": "un", "effective_from": time.Now().AddDate(0, 0, 7).Format(models.DateLayout),
+	})
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/admin/ingredients/"+itoa(rice.ID)+"/prices", adminToken, map[string]interface{}{"price": 0, "unit": "kg"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/admin/ingredients/"+itoa(rice.ID)+"/prices", adminToken, map[string]interface{}{"price": 5, "unit": "kg", "effective_from": "amanhã"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	estimate := func(path string) cost.Estimate {
+		t.Helper()
+		rec := doAuthRequest(t, router, http.MethodGet, path, "", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var body cost.Estimate
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+
+	// Nacional: 3,00 de arroz + 4,00 de ovos = 7,00 (1,75 por porção)
+	national := estimate("/recipes/" + itoa(recipe.ID) + "/cost")
+	assert.Equal(t, "BR", national.Region)
+	assert.Equal(t, "BRL", national.Currency)
+	assert.InDelta(t, 7.0, national.Total, 0.001)
+	assert.InDelta(t, 1.75, national.PerServing, 0.001)
+	assert.False(t, national.Complete)
+	require.Len(t, national.Items, 4)
+	require.NotNil(t, national.Items[0].Cost)
+	assert.InDelta(t, 3.0, *national.Items[0].Cost, 0.001)
+	assert.Nil(t, national.Items[2].Cost)
+
+	missing := map[uint]string{}
+	for _, item := range national.Missing {
+		missing[item.IngredientID] = item.Reason
+	}
+	assert.Equal(t, map[uint]string{garlic.ID: cost.ReasonUnitMismatch, salt.ID: cost.ReasonNoPrice}, missing)
+
+	// SP: arroz com o preço da região e ovos com o nacional
+	regional := estimate("/recipes/" + itoa(recipe.ID) + "/cost?region=sp")
+	assert.Equal(t, "SP", regional.Region)
+	assert.InDelta(t, 8.0, regional.Total, 0.001)
+	assert.Equal(t, "SP", regional.Items[0].PriceRegion)
+	assert.Equal(t, "BR", regional.Items[1].PriceRegion)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/cost?region=S1", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Histórico de preços do arroz, filtrado por região
+	rec = doAuthRequest(t, router, http.MethodGet, "/admin/ingredients/"+itoa(rice.ID)+"/prices?region=sp", adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var history struct {
+		Prices []models.IngredientPrice `json:"prices"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history.Prices, 1)
+	assert.Equal(t, 8.0, history.Prices[0].Price)
+
+	// Não-admin não acessa a tabela de preços
+	ownerToken := loginTestUser(t, router, "cost_owner@test.com", "password123")
+	rec = doAuthRequest(t, router, http.MethodGet, "/admin/ingredients/"+itoa(rice.ID)+"/prices", ownerToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestRecipeCost_BudgetFilterAndRecompute(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	admin := createTestUser(t, "budget_admin@test.com", "password123", "Admin")
+	database.DB.Model(&admin).Update("role", "admin")
+	adminToken := loginTestUser(t, router, "budget_admin@test.com", "password123")
+	owner := createTestUser(t, "budget_owner@test.com", "password123", "Chef")
+	ownerToken := loginTestUser(t, router, "budget_owner@test.com", "password123")
+
+	beans := testdb.SeedIngredient(t, "Feijão", "leguminosas", 340)
+	steak := testdb.SeedIngredient(t, "Picanha", "carnes", 290)
+	saffron := testdb.SeedIngredient(t, "Açafrão", "temperos", 300)
+	postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(beans.ID)+"/prices", adminToken, map[string]interface{}{"price": 8, "quantity": 1, "unit": "kg"})
+	steakPrice := postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(steak.ID)+"/prices", adminToken, map[string]interface{}{"price": 80, "quantity": 1, "unit": "kg"})
+
+	// 4 porções: R$ 1,00, R$ 16,00 e sem custo (açafrão sem preço)
+	cheap := testdb.SeedRecipe(t, "Feijoada simples", "Barata", owner.ID, false)
+	addRecipeIngredientViaAPI(t, router, ownerToken, cheap.ID, beans.ID, 500)
+	pricey := testdb.SeedRecipe(t, "Picanha na brasa", "Cara", owner.ID, false)
+	addRecipeIngredientViaAPI(t, router, ownerToken, pricey.ID, steak.ID, 800)
+	unknown := testdb.SeedRecipe(t, "Risoto de açafrão", "Sem preço", owner.ID, false)
+	addRecipeIngredientViaAPI(t, router, ownerToken, unknown.ID, saffron.ID, 10)
+
+	require.NotNil(t, storedRecipe(t, cheap.ID).CostPerServing)
+	assert.InDelta(t, 1.0, *storedRecipe(t, cheap.ID).CostPerServing, 0.001)
+	assert.InDelta(t, 16.0, *storedRecipe(t, pricey.ID).CostPerServing, 0.001)
+	assert.Nil(t, storedRecipe(t, unknown.ID).CostPerServing)
+
+	listIDs := func(path string) []uint {
+		t.Helper()
+		rec := doAuthRequest(t, router, http.MethodGet, path, "", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var body struct {
+			Data []models.Recipe `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		ids := make([]uint, 0, len(body.Data))
+		for _, recipe := range body.Data {
+			ids = append(ids, recipe.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []uint{cheap.ID}, listIDs("/recipes?max_cost=5"))
+	assert.ElementsMatch(t, []uint{cheap.ID, pricey.ID}, listIDs("/recipes?max_cost=20"))
+	assert.Equal(t, []uint{cheap.ID, pricey.ID, unknown.ID}, listIDs("/recipes?sort_by=cost"))
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes?max_cost=-1", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Novo preço do açafrão completa a estimativa da receita
+	postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(saffron.ID)+"/prices", adminToken, map[string]interface{}{"price": 20, "quantity": 1, "unit": "g"})
+	require.NotNil(t, storedRecipe(t, unknown.ID).CostPerServing)
+	assert.InDelta(t, 50.0, *storedRecipe(t, unknown.ID).CostPerServing, 0.001)
+	assert.Equal(t, []uint{cheap.ID, pricey.ID, unknown.ID}, listIDs("/recipes?sort_by=cost"))
+
+	// Preço regional não altera o custo de referência
+	postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(beans.ID)+"/prices", adminToken, map[string]interface{}{"region": "RJ", "price": 100, "quantity": 1, "unit": "kg"})
+	assert.InDelta(t, 1.0, *storedRecipe(t, cheap.ID).CostPerServing, 0.001)
+
+	// Removido o preço da picanha, a receita volta a ficar sem custo
+	rec = doAuthRequest(t, router, http.MethodDelete, "/admin/ingredients/"+itoa(steak.ID)+"/prices/"+itoa(steakPrice.ID), adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Nil(t, storedRecipe(t, pricey.ID).CostPerServing)
+	assert.Equal(t, []uint{cheap.ID}, listIDs("/recipes?max_cost=20"))
+
+	// Preço agendado fica pendente até entrar em vigor
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(models.DateLayout)
+	scheduled := postJSON[models.IngredientPrice](t, router, "/admin/ingredients/"+itoa(beans.ID)+"/prices", adminToken, map[string]interface{}{"price": 16, "quantity": 1, "unit": "kg", "effective_from": tomorrow})
+	require.NoError(t, cost.ApplyEffectivePrices(database.DB, time.Now()))
+	var pending models.IngredientPrice
+	require.NoError(t, database.DB.First(&pending, scheduled.ID).Error)
+	assert.Nil(t, pending.AppliedAt)
+	assert.InDelta(t, 1.0, *storedRecipe(t, cheap.ID).CostPerServing, 0.001)
+	require.NoError(t, database.DB.Delete(&pending).Error)
+
+	// Preço agendado que entrou em vigor hoje, com o servidor parado ou reiniciado, é aplicado pelo job
+	overdue := models.IngredientPrice{
+		IngredientID: beans.ID, Region: "BR", Price: 16, Quantity: 1, Unit: "kg",
+		UnitPrice: 0.016, BaseUnit: "g", EffectiveFrom: time.Now().Format(models.DateLayout),
+	}
+	require.NoError(t, database.DB.Create(&overdue).Error)
+	assert.InDelta(t, 1.0, *storedRecipe(t, cheap.ID).CostPerServing, 0.001)
+	require.NoError(t, cost.ApplyEffectivePrices(database.DB, time.Now()))
+	assert.InDelta(t, 2.0, *storedRecipe(t, cheap.ID).CostPerServing, 0.001)
+	var applied models.IngredientPrice
+	require.NoError(t, database.DB.First(&applied, overdue.ID).Error)
+	assert.NotNil(t, applied.AppliedAt)
+}
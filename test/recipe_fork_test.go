@@ -0,0 +1,185 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// forkTestRecipe cria um fork via API e retorna a cópia
+func forkTestRecipe(t *testing.T, router http.Handler, token string, recipeID uint) models.Recipe {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipeID)+"/fork", token, nil)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var fork models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fork))
+	return fork
+}
+
+func TestRecipeFork_DeepCopyAndAttribution(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	ana := createTestUser(t, "fork_ana@test.com", "password123", "Ana")
+	anaToken := loginTestUser(t, router, "fork_ana@test.com", "password123")
+	createTestUser(t, "fork_bia@test.com", "password123", "Bia")
+	biaToken := loginTestUser(t, router, "fork_bia@test.com", "password123")
+	createTestUser(t, "fork_caio@test.com", "password123", "Caio")
+	caioToken := loginTestUser(t, router, "fork_caio@test.com", "password123")
+
+	original := createTestRecipe(t, ana.ID)
+	require.NoError(t, database.DB.Model(original).Update("image_url", "https://res.cloudinary.com/test/image/upload/bolo.jpg").Error)
+	flour := testdb.SeedIngredient(t, "Farinha", "cereais", 364)
+	egg := testdb.SeedIngredient(t, "Ovo", "ovos", 143)
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: original.ID, IngredientID: flour.ID, Quantity: 300, Unit: "g", Order: 1}).Error)
+	eggLine := models.RecipeIngredient{RecipeID: original.ID, IngredientID: egg.ID, Quantity: 3, Unit: "unidade", Order: 2}
+	require.NoError(t, database.DB.Create(&eggLine).Error)
+	postJSON[models.RecipeStep](t, router, "/recipes/"+itoa(original.ID)+"/steps", anaToken, map[string]interface{}{
+		"text":           "Bata os ovos",
+		"ingredient_ids": []uint{eggLine.ID},
+	})
+
+	fork := forkTestRecipe(t, router, biaToken, original.ID)
+
+	require.NotNil(t, fork.ForkedFromID)
+	assert.Equal(t, original.ID, *fork.ForkedFromID)
+	assert.Equal(t, original.Title, fork.Title)
+	assert.Equal(t, models.RecipeStatusDraft, fork.Status)
+	assert.Empty(t, fork.ImageURL, "imagens não são copiadas")
+
+	// Ingredientes e passos são linhas novas da cópia
+	require.Len(t, fork.Ingredients, 2)
+	assert.Equal(t, flour.ID, fork.Ingredients[0].IngredientID)
+	assert.NotEqual(t, eggLine.ID, fork.Ingredients[1].ID)
+	require.Len(t, fork.Steps, 1)
+	require.Len(t, fork.Steps[0].Ingredients, 1)
+	assert.Equal(t, fork.Ingredients[1].ID, fork.Steps[0].Ingredients[0].ID)
+
+	require.Len(t, fork.ForkChain, 1)
+	assert.Equal(t, "Ana", fork.ForkChain[0].AuthorName)
+	assert.True(t, fork.ForkChain[0].Available)
+
+	// Rascunhos não contam como forks públicos
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(original.ID)+"/forks", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"total":0`)
+
+	require.NoError(t, database.DB.Model(&models.Recipe{}).Where("id = ?", fork.ID).Update("status", models.RecipeStatusPublished).Error)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(original.ID), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var originalResp models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &originalResp))
+	assert.Equal(t, int64(1), originalResp.ForkCount)
+	assert.Empty(t, originalResp.ForkChain)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(original.ID)+"/forks", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var forks struct {
+		Data []models.Recipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &forks))
+	require.Len(t, forks.Data, 1)
+	assert.Equal(t, fork.ID, forks.Data[0].ID)
+
+	// Fork de fork forma a cadeia de atribuição
+	second := forkTestRecipe(t, router, caioToken, fork.ID)
+	require.Len(t, second.ForkChain, 2)
+	assert.Equal(t, fork.ID, second.ForkChain[0].RecipeID)
+	assert.Equal(t, "Bia", second.ForkChain[0].AuthorName)
+	assert.Equal(t, original.ID, second.ForkChain[1].RecipeID)
+
+	// Excluir a original preserva a atribuição
+	rec = doAuthRequest(t, router, http.MethodDelete, "/recipes/"+itoa(original.ID), anaToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(fork.ID), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var forkResp models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &forkResp))
+	require.Len(t, forkResp.ForkChain, 1)
+	assert.Equal(t, "Test Recipe", forkResp.ForkChain[0].Title)
+	assert.Equal(t, "Ana", forkResp.ForkChain[0].AuthorName)
+	assert.False(t, forkResp.ForkChain[0].Available)
+}
+
+func TestRecipeFork_Permissions(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "fork_perm_owner@test.com", "password123", "Dona")
+	ownerToken := loginTestUser(t, router, "fork_perm_owner@test.com", "password123")
+	createTestUser(t, "fork_perm_other@test.com", "password123", "Outro")
+	otherToken := loginTestUser(t, router, "fork_perm_other@test.com", "password123")
+
+	recipe := createTestRecipe(t, owner.ID)
+	require.NoError(t, database.DB.Model(recipe).Update("visibility", models.RecipeVisibilityPrivate).Error)
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/fork", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Receita privada não pode ser copiada por terceiros, nem tem forks listados
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/fork", otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/forks", otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// O dono pode copiar a própria receita, e a cópia mantém a visibilidade da original
+	fork := forkTestRecipe(t, router, ownerToken, recipe.ID)
+	assert.Equal(t, models.RecipeVisibilityPrivate, fork.Visibility)
+
+	// O link de uma receita não listada permite ler, mas não copiar e republicar
+	unlisted := createTestRecipe(t, owner.ID)
+	rec = doAuthRequest(t, router, http.MethodPatch, "/recipes/"+itoa(unlisted.ID)+"/visibility", ownerToken, map[string]interface{}{
+		"visibility": models.RecipeVisibilityUnlisted,
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var share struct {
+		ShareToken string `json:"share_token"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &share))
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(unlisted.ID)+"?share_token="+share.ShareToken, otherToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(unlisted.ID)+"/fork?share_token="+share.ShareToken, otherToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	unlistedFork := forkTestRecipe(t, router, ownerToken, unlisted.ID)
+	assert.Equal(t, models.RecipeVisibilityUnlisted, unlistedFork.Visibility)
+
+	// A cadeia de atribuição não revela originais que deixaram de ser visíveis
+	public := createTestRecipe(t, owner.ID)
+	publicFork := forkTestRecipe(t, router, otherToken, public.ID)
+	require.NoError(t, database.DB.Model(&models.Recipe{}).Where("id = ?", publicFork.ID).Update("status", models.RecipeStatusPublished).Error)
+	require.NoError(t, database.DB.Model(public).Update("visibility", models.RecipeVisibilityPrivate).Error)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(publicFork.ID), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var forkResp models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &forkResp))
+	require.Len(t, forkResp.ForkChain, 1)
+	assert.Equal(t, public.ID, forkResp.ForkChain[0].RecipeID)
+	assert.Empty(t, forkResp.ForkChain[0].Title)
+	assert.Empty(t, forkResp.ForkChain[0].AuthorName)
+	assert.Nil(t, forkResp.ForkChain[0].AuthorID)
+	assert.False(t, forkResp.ForkChain[0].Available)
+
+	// forked_from_id do body é ignorado na criação comum
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes", otherToken, map[string]interface{}{
+		"title":          "Cópia manual",
+		"prep_time":      10,
+		"servings":       1,
+		"forked_from_id": recipe.ID,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "forked_from_id")
+}
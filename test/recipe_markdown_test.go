@@ -0,0 +1,98 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/markdown"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+func TestCreateRecipe_RejectsDisallowedMarkdown(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	createTestUser(t, "markdown_create@test.com", "password123", "Cozinheiro")
+	token := loginTestUser(t, router, "markdown_create@test.com", "password123")
+
+	cases := map[string]string{
+		"html":       "1. Misture tudo\n2. <script>alert('xss')</script>",
+		"javascript": "Veja [a técnica](javascript:alert(1)) antes de começar",
+		"imagem":     "1. Asse\n\n![foto](https://exemplo.com/a.png)",
+		"código":     "```\nrm -rf /\n```\n1. Asse",
+	}
+
+	for name, instructions := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := doAuthRequest(t, router, http.MethodPost, "/recipes", token, map[string]interface{}{
+				"title":        "Bolo",
+				"instructions": instructions,
+				"prep_time":    30,
+				"servings":     4,
+			})
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), "Modo de preparo inválido")
+		})
+	}
+
+	// Markdown permitido é normalizado e aceito
+	rec := doAuthRequest(t, router, http.MethodPost, "/recipes", token, map[string]interface{}{
+		"title":        "Bolo",
+		"instructions": "## Modo de Preparo\r\n\r\n\r\n\r\n1. Bata os ovos   \r\n2. Asse por **40 minutos**‮",
+		"prep_time":    30,
+		"servings":     4,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "## Modo de Preparo\n\n1. Bata os ovos\n2. Asse por **40 minutos**", created.Instructions)
+
+	// Atualização também é validada
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(created.ID), token, map[string]interface{}{
+		"instructions": "1. Misture <img src=x onerror=alert(1)>",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetRecipe_RenderHTML(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "markdown_render@test.com", "password123", "Cozinheiro")
+	recipe := createTestRecipe(t, user.ID)
+
+	// Conteúdo gravado antes da sanitização continua seguro na renderização
+	recipe.Instructions = "## Massa\n\n1. Misture:\n   - 2 ovos\n   - *1 xícara* de açúcar\n2. Veja [o vídeo](https://exemplo.com/v)\n\n<script>alert(1)</script>"
+	require.NoError(t, database.DB.Save(recipe).Error)
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "instructions_html")
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"?render=html", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, recipe.Instructions, resp.Instructions)
+	assert.Equal(t, "<h2>Massa</h2>\n"+
+		"<ol>\n<li>Misture:\n<ul>\n<li>2 ovos</li>\n<li><em>1 xícara</em> de açúcar</li>\n</ul></li>\n"+
+		"<li>Veja <a href=\"https://exemplo.com/v\" rel=\"nofollow noopener noreferrer\">o vídeo</a></li>\n</ol>\n"+
+		"<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>", resp.InstructionsHTML)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"?render=pdf", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestMarkdownToHTML_UnsafeLinksRenderedAsText(t *testing.T) {
+	out := markdown.ToHTML(`Clique [aqui](javascript:alert("x")) ou **[aqui](https://ok.com)**`)
+	assert.NotContains(t, out, `href="javascript`)
+	assert.Contains(t, out, `<strong><a href="https://ok.com" rel="nofollow noopener noreferrer">aqui</a></strong>`)
+}
@@ -0,0 +1,249 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/auth"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// revisionListItem representa um item de GET /recipes/{id}/revisions
+type revisionListItem struct {
+	Number       int    `json:"number"`
+	Action       string `json:"action"`
+	AuthorName   string `json:"author_name"`
+	RestoredFrom *int   `json:"restored_from"`
+}
+
+// listTestRevisions lista as revisões da receita via API
+func listTestRevisions(t *testing.T, router http.Handler, token string, recipeID uint) []revisionListItem {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipeID)+"/revisions", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp struct {
+		Data []revisionListItem `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestRecipeRevisions_UpdateDiffAndRestore(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "revisions_owner@test.com", "password123", "Dona da Receita")
+	token := loginTestUser(t, router, "revisions_owner@test.com", "password123")
+	recipe := createTestRecipe(t, user.ID)
+
+	sugar := testdb.SeedIngredient(t, "Açúcar", "açúcares", 387)
+	flour := testdb.SeedIngredient(t, "Farinha", "cereais", 364)
+	sugarLine := models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: sugar.ID, Quantity: 200, Unit: "g"}
+	require.NoError(t, database.DB.Create(&sugarLine).Error)
+
+	// Primeira edição grava o estado anterior (original) e o novo
+	rec := doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID), token, map[string]interface{}{
+		"title":    "Bolo de Cenoura",
+		"servings": 8,
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Edição sem mudança de conteúdo não gera revisão
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID), token, map[string]interface{}{
+		"title": "Bolo de Cenoura",
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// Cada alteração de ingrediente grava a própria revisão
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID)+"/ingredients/"+itoa(sugarLine.ID), token, map[string]interface{}{
+		"quantity": 150,
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/ingredients", token, map[string]interface{}{
+		"ingredient_id": flour.ID,
+		"quantity":      300,
+		"unit":          "g",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID), token, map[string]interface{}{
+		"description": "Fofinho",
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	revisions := listTestRevisions(t, router, token, recipe.ID)
+	require.Len(t, revisions, 5)
+	assert.Equal(t, 5, revisions[0].Number)
+	assert.Equal(t, models.RecipeRevisionActionUpdate, revisions[0].Action)
+	assert.Equal(t, "Dona da Receita", revisions[0].AuthorName)
+	assert.Equal(t, models.RecipeRevisionActionOriginal, revisions[4].Action)
+
+	// A revisão 4 contém apenas a inclusão da farinha
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/revisions/diff?to=4", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var step struct {
+		From        int                      `json:"from"`
+		Fields      []map[string]interface{} `json:"fields"`
+		Ingredients []map[string]interface{} `json:"ingredients"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &step))
+	assert.Equal(t, 3, step.From)
+	assert.Empty(t, step.Fields)
+	require.Len(t, step.Ingredients, 1)
+	assert.Equal(t, "added", step.Ingredients[0]["change"])
+
+	// Diff entre a original e a mais recente
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/revisions/diff?from=1", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var diff struct {
+		From        int                      `json:"from"`
+		To          int                      `json:"to"`
+		Fields      []map[string]interface{} `json:"fields"`
+		Ingredients []map[string]interface{} `json:"ingredients"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &diff))
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 5, diff.To)
+
+	changedFields := make(map[string]bool)
+	for _, f := range diff.Fields {
+		changedFields[f["field"].(string)] = true
+	}
+	assert.Equal(t, map[string]bool{"title": true, "description": true, "servings": true}, changedFields)
+
+	changes := make(map[string]string)
+	for _, c := range diff.Ingredients {
+		changes[c["name"].(string)] = c["change"].(string)
+	}
+	assert.Equal(t, map[string]string{"Açúcar": "changed", "Farinha": "added"}, changes)
+
+	// Restaurar a original
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/revisions/1/restore", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var restored models.Recipe
+	require.NoError(t, database.DB.Preload("Ingredients").First(&restored, recipe.ID).Error)
+	assert.Equal(t, "Test Recipe", restored.Title)
+	assert.Equal(t, recipe.Servings, restored.Servings)
+	require.Len(t, restored.Ingredients, 1)
+	assert.Equal(t, sugarLine.ID, restored.Ingredients[0].ID, "linha existente deve ser reaproveitada")
+	assert.Equal(t, 200.0, restored.Ingredients[0].Quantity)
+
+	revisions = listTestRevisions(t, router, token, recipe.ID)
+	require.Len(t, revisions, 6)
+	assert.Equal(t, models.RecipeRevisionActionRestore, revisions[0].Action)
+	require.NotNil(t, revisions[0].RestoredFrom)
+	assert.Equal(t, 1, *revisions[0].RestoredFrom)
+}
+
+func TestRecipeRevisions_AdminEditAndPermissions(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "revisions_perm_owner@test.com", "password123", "Dono")
+	ownerToken := loginTestUser(t, router, "revisions_perm_owner@test.com", "password123")
+	createTestUser(t, "revisions_perm_other@test.com", "password123", "Outro")
+	otherToken := loginTestUser(t, router, "revisions_perm_other@test.com", "password123")
+
+	hashedPassword, _ := auth.HashPassword("password123")
+	testdb.SeedUser(t, "Moderador", "revisions_admin@test.com", hashedPassword, "admin")
+	adminToken := loginTestUser(t, router, "revisions_admin@test.com", "password123")
+
+	recipe := createTestRecipe(t, owner.ID)
+
+	rec := doAuthRequest(t, router, http.MethodPut, "/admin/recipes/"+itoa(recipe.ID), adminToken, map[string]interface{}{
+		"title": "Título alterado pelo admin",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// O dono vê a edição do admin e pode desfazê-la
+	revisions := listTestRevisions(t, router, ownerToken, recipe.ID)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "Moderador", revisions[0].AuthorName)
+	assert.Equal(t, "Dono", revisions[1].AuthorName)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/revisions/1", ownerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"title":"Test Recipe"`)
+
+	// Outros usuários e anônimos não acessam o histórico
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/revisions", otherToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/revisions/1/restore", otherToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/revisions", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Revisão inexistente ou número inválido
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/revisions/99", ownerToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/revisions/diff?from=abc", ownerToken, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRecipeRevisions_IngredientBaselineAndEdgeCases(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "revisions_edge@test.com", "password123", "Dona da Receita")
+	token := loginTestUser(t, router, "revisions_edge@test.com", "password123")
+	recipe := createTestRecipe(t, user.ID)
+
+	// Sem histórico, o diff não tem o que comparar
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/revisions/diff", token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// A primeira edição sendo uma remoção: a versão original ainda tem o ingrediente
+	egg := testdb.SeedIngredient(t, "Ovo", "ovos", 143)
+	eggLine := models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: egg.ID, Quantity: 3, Unit: "unidade"}
+	require.NoError(t, database.DB.Create(&eggLine).Error)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, "/recipes/"+itoa(recipe.ID)+"/ingredients/"+itoa(eggLine.ID), token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	revisions := listTestRevisions(t, router, token, recipe.ID)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, models.RecipeRevisionActionOriginal, revisions[1].Action)
+
+	// Diff da revisão 1 com ela mesma é vazio
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/revisions/diff?to=1", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var diff struct {
+		From        int           `json:"from"`
+		Fields      []interface{} `json:"fields"`
+		Ingredients []interface{} `json:"ingredients"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &diff))
+	assert.Equal(t, 1, diff.From)
+	assert.Empty(t, diff.Fields)
+	assert.Empty(t, diff.Ingredients)
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/revisions/1/restore", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var restored models.Recipe
+	require.NoError(t, database.DB.Preload("Ingredients").First(&restored, recipe.ID).Error)
+	require.Len(t, restored.Ingredients, 1)
+	assert.Equal(t, egg.ID, restored.Ingredients[0].IngredientID)
+
+	// Modo de preparo gravado antes da validação de Markdown não volta pela restauração
+	legacy := createTestRecipe(t, user.ID)
+	require.NoError(t, database.DB.Model(legacy).UpdateColumn("instructions", "<script>alert(1)</script>").Error)
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(legacy.ID), token, map[string]interface{}{
+		"instructions": "Misture tudo",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(legacy.ID)+"/revisions/1/restore", token, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var stored models.Recipe
+	require.NoError(t, database.DB.First(&stored, legacy.ID).Error)
+	assert.Equal(t, "Misture tudo", stored.Instructions)
+}
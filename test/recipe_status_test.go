@@ -0,0 +1,207 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/publishing"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// listPublicRecipesTotal retorna o total de GET /recipes (anônimo)
+func listPublicRecipesTotal(t *testing.T, router http.Handler) int64 {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Pagination struct {
+			Total int64 `json:"total"`
+		} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Pagination.Total
+}
+
+// createCompleteDraft cria um rascunho que atende aos requisitos de publicação
+func createCompleteDraft(t *testing.T, userID uint) *models.Recipe {
+	t.Helper()
+
+	recipe := createTestRecipe(t, userID)
+	ingredient := testdb.SeedIngredient(t, "Ovo", "ovos", 143)
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: ingredient.ID, Quantity: 2, Unit: "unidade"}).Error)
+	require.NoError(t, database.DB.Model(recipe).Updates(map[string]interface{}{
+		"status":       models.RecipeStatusDraft,
+		"instructions": "Bata os ovos e leve ao forno.",
+		"image_url":    "https://res.cloudinary.com/test/image/upload/ovo.jpg",
+	}).Error)
+	return recipe
+}
+
+func TestRecipeStatus_DraftVisibilityAndPublish(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	createTestUser(t, "status_owner@test.com", "password123", "Dona")
+	ownerToken := loginTestUser(t, router, "status_owner@test.com", "password123")
+	createTestUser(t, "status_other@test.com", "password123", "Outro")
+	otherToken := loginTestUser(t, router, "status_other@test.com", "password123")
+
+	// Receita nova nasce como rascunho, mesmo se o cliente enviar outro status
+	rec := doAuthRequest(t, router, http.MethodPost, "/recipes", ownerToken, map[string]interface{}{
+		"title":        "Pão de Queijo",
+		"instructions": "Misture tudo e asse por 25 minutos.",
+		"prep_time":    40,
+		"servings":     6,
+		"status":       "published",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, models.RecipeStatusDraft, created.Status)
+	assert.Equal(t, int64(0), listPublicRecipesTotal(t, router))
+
+	recipePath := "/recipes/" + itoa(created.ID)
+
+	// Rascunho só é visível para o dono
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, recipePath, "", nil).Code)
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, recipePath, otherToken, nil).Code)
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, recipePath+"/ingredients", "", nil).Code)
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, recipePath+"/ratings", "", nil).Code)
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodPost, recipePath+"/ratings", otherToken, map[string]interface{}{"score": 5}).Code)
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, router, http.MethodGet, recipePath, ownerToken, nil).Code)
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, router, http.MethodGet, recipePath+"/nutrition", ownerToken, nil).Code)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/recipes?status=draft", ownerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"title":"Pão de Queijo"`)
+
+	// Publicar exige ingrediente e imagem
+	rec = doAuthRequest(t, router, http.MethodPatch, recipePath+"/status", ownerToken, map[string]interface{}{"status": "published"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ingredientes, imagem")
+
+	ingredient := testdb.SeedIngredient(t, "Polvilho", "cereais", 351)
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{RecipeID: created.ID, IngredientID: ingredient.ID, Quantity: 500, Unit: "g"}).Error)
+	require.NoError(t, database.DB.Model(&models.Recipe{}).Where("id = ?", created.ID).
+		Update("image_url", "https://res.cloudinary.com/test/image/upload/pao.jpg").Error)
+
+	// Outro usuário não pode publicar
+	rec = doAuthRequest(t, router, http.MethodPatch, recipePath+"/status", otherToken, map[string]interface{}{"status": "published"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPatch, recipePath+"/status", ownerToken, map[string]interface{}{"status": "published"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var published models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &published))
+	assert.Equal(t, models.RecipeStatusPublished, published.Status)
+	assert.NotNil(t, published.PublishedAt)
+	assert.Equal(t, int64(1), listPublicRecipesTotal(t, router))
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, router, http.MethodGet, recipePath, "", nil).Code)
+
+	// Arquivar tira a receita de circulação
+	rec = doAuthRequest(t, router, http.MethodPatch, recipePath+"/status", ownerToken, map[string]interface{}{"status": "archived"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(0), listPublicRecipesTotal(t, router))
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, recipePath, "", nil).Code)
+
+	rec = doAuthRequest(t, router, http.MethodPatch, recipePath+"/status", ownerToken, map[string]interface{}{"status": "deleted"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRecipeStatus_ScheduledPublishing(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "status_schedule@test.com", "password123", "Agendadora")
+	token := loginTestUser(t, router, "status_schedule@test.com", "password123")
+
+	recipe := createCompleteDraft(t, owner.ID)
+	statusPath := "/recipes/" + itoa(recipe.ID) + "/status"
+
+	// Agendamento precisa ser no futuro e apenas com status published
+	rec := doAuthRequest(t, router, http.MethodPatch, statusPath, token, map[string]interface{}{
+		"status":     "published",
+		"publish_at": time.Now().Add(-time.Hour),
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPatch, statusPath, token, map[string]interface{}{
+		"status":     "archived",
+		"publish_at": time.Now().Add(time.Hour),
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPatch, statusPath, token, map[string]interface{}{
+		"status":     "published",
+		"publish_at": time.Now().Add(time.Hour),
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var scheduled models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &scheduled))
+	assert.Equal(t, models.RecipeStatusDraft, scheduled.Status)
+	require.NotNil(t, scheduled.PublishAt)
+
+	// Antes do horário nada muda
+	require.NoError(t, publishing.PublishScheduledRecipes())
+	var reloaded models.Recipe
+	require.NoError(t, database.DB.First(&reloaded, recipe.ID).Error)
+	assert.Equal(t, models.RecipeStatusDraft, reloaded.Status)
+
+	// Rascunho incompleto com agendamento vencido tem o agendamento cancelado
+	incomplete := createTestRecipe(t, owner.ID)
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, database.DB.Model(incomplete).Updates(map[string]interface{}{
+		"status":     models.RecipeStatusDraft,
+		"publish_at": past,
+	}).Error)
+	require.NoError(t, database.DB.Model(&reloaded).Update("publish_at", past).Error)
+
+	require.NoError(t, publishing.PublishScheduledRecipes())
+
+	var published models.Recipe
+	require.NoError(t, database.DB.First(&published, recipe.ID).Error)
+	assert.Equal(t, models.RecipeStatusPublished, published.Status)
+	assert.Nil(t, published.PublishAt)
+	assert.NotNil(t, published.PublishedAt)
+
+	var skipped models.Recipe
+	require.NoError(t, database.DB.First(&skipped, incomplete.ID).Error)
+	assert.Equal(t, models.RecipeStatusDraft, skipped.Status)
+	assert.Nil(t, skipped.PublishAt)
+
+	// Receita publicada não pode ser agendada
+	rec = doAuthRequest(t, router, http.MethodPatch, statusPath, token, map[string]interface{}{
+		"status":     "published",
+		"publish_at": time.Now().Add(time.Hour),
+	})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Receita arquivada depois de carregada pelo job não é publicada
+	require.NoError(t, database.DB.Model(incomplete).Update("publish_at", past).Error)
+	var stale models.Recipe
+	require.NoError(t, database.DB.First(&stale, incomplete.ID).Error)
+	rec = doAuthRequest(t, router, http.MethodPatch, "/recipes/"+itoa(incomplete.ID)+"/status", token, map[string]interface{}{
+		"status": "archived",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	ok, err := publishing.PublishScheduled(database.DB, &stale, time.Now())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	var kept models.Recipe
+	require.NoError(t, database.DB.First(&kept, incomplete.ID).Error)
+	assert.Equal(t, models.RecipeStatusArchived, kept.Status)
+	assert.Nil(t, kept.PublishedAt)
+}
@@ -0,0 +1,227 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/markdown"
+	"github.com/davidsonmarra/receitas-app/pkg/storage"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// listTestSteps lista os passos da receita via API
+func listTestSteps(t *testing.T, router http.Handler, recipeID uint) []models.RecipeStep {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipeID)+"/steps", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var steps []models.RecipeStep
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &steps))
+	return steps
+}
+
+func TestRecipeSteps_CRUDAndReorder(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "steps_owner@test.com", "password123", "Cozinheiro")
+	token := loginTestUser(t, router, "steps_owner@test.com", "password123")
+	recipe := createTestRecipe(t, user.ID)
+	ingredient := testdb.SeedIngredient(t, "Ovo", "ovos", 143)
+	recipeIng := models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: ingredient.ID, Quantity: 3, Unit: "unidade"}
+	require.NoError(t, database.DB.Create(&recipeIng).Error)
+
+	first := postJSON[models.RecipeStep](t, router, "/recipes/"+itoa(recipe.ID)+"/steps", token, map[string]interface{}{
+		"text":           "Bata os ovos com o açúcar",
+		"ingredient_ids": []uint{recipeIng.ID},
+	})
+	assert.Equal(t, 1, first.Order)
+	require.Len(t, first.Ingredients, 1)
+	assert.Equal(t, "Ovo", first.Ingredients[0].Ingredient.Name)
+
+	second := postJSON[models.RecipeStep](t, router, "/recipes/"+itoa(recipe.ID)+"/steps", token, map[string]interface{}{
+		"text":             "Asse por 40 minutos",
+		"duration_seconds": 2400,
+	})
+	assert.Equal(t, 2, second.Order)
+	require.NotNil(t, second.DurationSeconds)
+	assert.Equal(t, 2400, *second.DurationSeconds)
+
+	// Atualizar texto e remover timer
+	rec := doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID)+"/steps/"+itoa(second.ID), token, map[string]interface{}{
+		"text":             "Asse até dourar",
+		"duration_seconds": 0,
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated models.RecipeStep
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, "Asse até dourar", updated.Text)
+	assert.Nil(t, updated.DurationSeconds)
+
+	// Reordenar
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID)+"/steps/reorder", token, map[string]interface{}{
+		"step_ids": []uint{second.ID, first.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	steps := listTestSteps(t, router, recipe.ID)
+	require.Len(t, steps, 2)
+	assert.Equal(t, second.ID, steps[0].ID)
+	assert.Equal(t, first.ID, steps[1].ID)
+	assert.Equal(t, 2, steps[1].Order)
+
+	// Reordenação incompleta é recusada
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID)+"/steps/reorder", token, map[string]interface{}{
+		"step_ids": []uint{first.ID, first.ID},
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// GET /recipes/{id} inclui os passos ordenados
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var full models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &full))
+	require.Len(t, full.Steps, 2)
+	assert.Equal(t, second.ID, full.Steps[0].ID)
+
+	// Remover ingrediente da receita remove a referência no passo
+	rec = doAuthRequest(t, router, http.MethodDelete, "/recipes/"+itoa(recipe.ID)+"/ingredients/"+itoa(recipeIng.ID), token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	steps = listTestSteps(t, router, recipe.ID)
+	assert.Empty(t, steps[1].Ingredients)
+
+	// Remover passo
+	rec = doAuthRequest(t, router, http.MethodDelete, "/recipes/"+itoa(recipe.ID)+"/steps/"+itoa(first.ID), token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, listTestSteps(t, router, recipe.ID), 1)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, "/recipes/"+itoa(recipe.ID)+"/steps/"+itoa(first.ID), token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRecipeSteps_ValidationAndPermissions(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "steps_perm_owner@test.com", "password123", "Dono")
+	ownerToken := loginTestUser(t, router, "steps_perm_owner@test.com", "password123")
+	other := createTestUser(t, "steps_perm_other@test.com", "password123", "Outro")
+	otherToken := loginTestUser(t, router, "steps_perm_other@test.com", "password123")
+
+	recipe := createTestRecipe(t, owner.ID)
+	otherRecipe := createTestRecipe(t, other.ID)
+	ingredient := testdb.SeedIngredient(t, "Leite", "laticínios", 61)
+	foreignIng := models.RecipeIngredient{RecipeID: otherRecipe.ID, IngredientID: ingredient.ID, Quantity: 200, Unit: "ml"}
+	require.NoError(t, database.DB.Create(&foreignIng).Error)
+
+	// Sem texto
+	rec := doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/steps", ownerToken, map[string]interface{}{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Ingrediente de outra receita
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/steps", ownerToken, map[string]interface{}{
+		"text":           "Adicione o leite",
+		"ingredient_ids": []uint{foreignIng.ID},
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Outro usuário não pode modificar
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/steps", otherToken, map[string]interface{}{
+		"text": "Passo indevido",
+	})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Sem autenticação
+	rec = doAuthRequest(t, router, http.MethodPost, "/recipes/"+itoa(recipe.ID)+"/steps", "", map[string]interface{}{
+		"text": "Passo anônimo",
+	})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Passo de outra receita não é encontrado pela URL desta receita
+	step := postJSON[models.RecipeStep](t, router, "/recipes/"+itoa(otherRecipe.ID)+"/steps", otherToken, map[string]interface{}{"text": "Ferva o leite"})
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID)+"/steps/"+itoa(step.ID), ownerToken, map[string]interface{}{
+		"text": "Alterado",
+	})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRecipeSteps_ImageConfirmRequiresStepPublicID(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	originalFactory := storage.ServiceFactory
+	defer func() { storage.ServiceFactory = originalFactory }()
+
+	mockService := testdb.NewMockCloudinaryService()
+	storage.ServiceFactory = func() (storage.ImageService, error) {
+		return mockService, nil
+	}
+
+	user := createTestUser(t, "steps_image@test.com", "password123", "Cozinheiro")
+	token := loginTestUser(t, router, "steps_image@test.com", "password123")
+	recipe := createTestRecipe(t, user.ID)
+	step := postJSON[models.RecipeStep](t, router, "/recipes/"+itoa(recipe.ID)+"/steps", token, map[string]interface{}{"text": "Decore o bolo"})
+
+	stepPath := "/recipes/" + itoa(recipe.ID) + "/steps/" + itoa(step.ID)
+	confirm := map[string]interface{}{
+		"secure_url": "https://res.cloudinary.com/test/image/upload/v1/recipes/step.jpg",
+		"width":      800,
+		"height":     600,
+		"format":     "jpg",
+		"bytes":      1024,
+	}
+
+	confirm["public_id"] = "recipes/recipe_" + itoa(recipe.ID) + "_1700000000"
+	rec := doAuthRequest(t, router, http.MethodPost, stepPath+"/image/confirm", token, confirm)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	confirm["public_id"] = "recipes/recipe_" + itoa(recipe.ID) + "_step_" + itoa(step.ID) + "_1700000000"
+	rec = doAuthRequest(t, router, http.MethodPost, stepPath+"/image/confirm", token, confirm)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	steps := listTestSteps(t, router, recipe.ID)
+	require.Len(t, steps, 1)
+	assert.Equal(t, confirm["secure_url"], steps[0].ImageURL)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, stepPath+"/image", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Empty(t, listTestSteps(t, router, recipe.ID)[0].ImageURL)
+}
+
+func TestExtractSteps_FromMarkdown(t *testing.T) {
+	instructions := "## Modo de Preparo\n\n" +
+		"### Massa\n" +
+		"1. Bata no liquidificador as cenouras com os ovos\n" +
+		"2. Misture os secos:\n" +
+		"   - 2 xícaras de farinha\n" +
+		"   - 1 xícara de açúcar\n" +
+		"3. Asse a 180°C por 40-45 minutos\n\n" +
+		"### Cobertura\n" +
+		"1) Leve ao fogo por 1 hora\n\n" +
+		"Dica: sirva gelado."
+
+	steps := markdown.ExtractSteps(instructions)
+	require.Len(t, steps, 4)
+
+	assert.Equal(t, "Bata no liquidificador as cenouras com os ovos", steps[0].Text)
+	assert.Nil(t, steps[0].DurationSeconds)
+
+	assert.Contains(t, steps[1].Text, "Misture os secos:")
+	assert.Contains(t, steps[1].Text, "- 1 xícara de açúcar")
+
+	require.NotNil(t, steps[2].DurationSeconds)
+	assert.Equal(t, 45*60, *steps[2].DurationSeconds)
+
+	assert.Equal(t, "Leve ao fogo por 1 hora", steps[3].Text)
+	require.NotNil(t, steps[3].DurationSeconds)
+	assert.Equal(t, 3600, *steps[3].DurationSeconds)
+
+	assert.Empty(t, markdown.ExtractSteps("Misture tudo e sirva."))
+}
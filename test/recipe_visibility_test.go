@@ -0,0 +1,149 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// shareResponse representa a resposta de /recipes/{id}/share e /visibility
+type shareResponse struct {
+	Visibility string  `json:"visibility"`
+	ShareToken *string `json:"share_token"`
+}
+
+func decodeShareResponse(t *testing.T, body []byte) shareResponse {
+	t.Helper()
+
+	var resp shareResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	return resp
+}
+
+func TestRecipeVisibility_PrivateAndListings(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "visibility_owner@test.com", "password123", "Dona")
+	ownerToken := loginTestUser(t, router, "visibility_owner@test.com", "password123")
+	createTestUser(t, "visibility_other@test.com", "password123", "Outro")
+	otherToken := loginTestUser(t, router, "visibility_other@test.com", "password123")
+
+	public := createTestRecipe(t, owner.ID)
+	secret := createTestRecipe(t, owner.ID)
+	unlisted := createTestRecipe(t, owner.ID)
+
+	// Avaliação alta na receita secreta não pode influenciar a ordenação pública
+	require.NoError(t, database.DB.Create(&models.Rating{UserID: owner.ID, RecipeID: secret.ID, Score: 5}).Error)
+
+	rec := doAuthRequest(t, router, http.MethodPatch, "/recipes/"+itoa(secret.ID)+"/visibility", ownerToken, map[string]string{"visibility": "private"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Nil(t, decodeShareResponse(t, rec.Body.Bytes()).ShareToken)
+
+	rec = doAuthRequest(t, router, http.MethodPatch, "/recipes/"+itoa(unlisted.ID)+"/visibility", ownerToken, map[string]string{"visibility": "unlisted"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, decodeShareResponse(t, rec.Body.Bytes()).ShareToken)
+
+	// Apenas a receita pública aparece, em qualquer ordenação
+	for _, path := range []string{"/recipes", "/recipes?sort_by=rating"} {
+		rec = doAuthRequest(t, router, http.MethodGet, path, ownerToken, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var list struct {
+			Data       []models.Recipe `json:"data"`
+			Pagination struct {
+				Total int64 `json:"total"`
+			} `json:"pagination"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		require.Len(t, list.Data, 1, path)
+		assert.Equal(t, public.ID, list.Data[0].ID)
+		assert.Equal(t, int64(1), list.Pagination.Total)
+	}
+
+	// Receita privada: 404 para todos exceto o dono, em todas as rotas de leitura
+	secretPath := "/recipes/" + itoa(secret.ID)
+	for _, path := range []string{"", "/ingredients", "/steps", "/nutrition", "/image/variants", "/ratings", "/ratings/stats"} {
+		assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, secretPath+path, "", nil).Code, path)
+		assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, secretPath+path, otherToken, nil).Code, path)
+	}
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, router, http.MethodGet, secretPath, ownerToken, nil).Code)
+
+	// Token de compartilhamento não vaza no JSON da receita
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(unlisted.ID), ownerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "share_token")
+
+	// Apenas o dono gerencia a visibilidade
+	rec = doAuthRequest(t, router, http.MethodPatch, secretPath+"/visibility", otherToken, map[string]string{"visibility": "public"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPatch, secretPath+"/visibility", ownerToken, map[string]string{"visibility": "hidden"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRecipeVisibility_UnlistedShareLink(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "share_owner@test.com", "password123", "Dona")
+	ownerToken := loginTestUser(t, router, "share_owner@test.com", "password123")
+	createTestUser(t, "share_other@test.com", "password123", "Amiga")
+	otherToken := loginTestUser(t, router, "share_other@test.com", "password123")
+
+	recipe := createTestRecipe(t, owner.ID)
+	recipePath := "/recipes/" + itoa(recipe.ID)
+
+	// Receita pública não tem link para regenerar
+	rec := doAuthRequest(t, router, http.MethodPost, recipePath+"/share", ownerToken, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPatch, recipePath+"/visibility", ownerToken, map[string]string{"visibility": "unlisted"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	firstToken := *decodeShareResponse(t, rec.Body.Bytes()).ShareToken
+
+	withToken := func(path, token string) string {
+		return path + "?share_token=" + url.QueryEscape(token)
+	}
+
+	// Com o link, qualquer pessoa lê a receita e as sub-rotas
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, recipePath, "", nil).Code)
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, router, http.MethodGet, withToken(recipePath, firstToken), "", nil).Code)
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, router, http.MethodGet, withToken(recipePath+"/ingredients", firstToken), "", nil).Code)
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, router, http.MethodGet, withToken(recipePath+"/nutrition", firstToken), "", nil).Code)
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, withToken(recipePath, "token-errado"), "", nil).Code)
+
+	rec = doAuthRequest(t, router, http.MethodPost, withToken(recipePath+"/ratings", firstToken), otherToken, map[string]interface{}{"score": 4})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// Alterar para unlisted de novo mantém o link
+	rec = doAuthRequest(t, router, http.MethodPatch, recipePath+"/visibility", ownerToken, map[string]string{"visibility": "unlisted"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, firstToken, *decodeShareResponse(t, rec.Body.Bytes()).ShareToken)
+
+	// Regenerar invalida o link anterior
+	rec = doAuthRequest(t, router, http.MethodPost, recipePath+"/share", ownerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	secondToken := *decodeShareResponse(t, rec.Body.Bytes()).ShareToken
+	assert.NotEqual(t, firstToken, secondToken)
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, withToken(recipePath, firstToken), "", nil).Code)
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, router, http.MethodGet, withToken(recipePath, secondToken), "", nil).Code)
+
+	// Revogar desativa o link, mas a receita continua não listada
+	rec = doAuthRequest(t, router, http.MethodDelete, recipePath+"/share", ownerToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	revoked := decodeShareResponse(t, rec.Body.Bytes())
+	assert.Equal(t, models.RecipeVisibilityUnlisted, revoked.Visibility)
+	assert.Nil(t, revoked.ShareToken)
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, router, http.MethodGet, withToken(recipePath, secondToken), "", nil).Code)
+
+	rec = doAuthRequest(t, router, http.MethodGet, recipePath+"/share", otherToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
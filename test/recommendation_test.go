@@ -0,0 +1,157 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/handlers"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/recommendation"
+	"github.com/davidsonmarra/receitas-app/pkg/similarity"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// myRecommendations busca as recomendações do usuário pela API
+func myRecommendations(t *testing.T, router http.Handler, token string) []handlers.RecommendedRecipe {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/users/me/recommendations", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var body struct {
+		Data []handlers.RecommendedRecipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body.Data
+}
+
+// rateRecipes grava as avaliações do usuário diretamente no banco
+func rateRecipes(t *testing.T, userID uint, scores map[uint]int) {
+	t.Helper()
+	for recipeID, score := range scores {
+		require.NoError(t, database.DB.Create(&models.Rating{UserID: userID, RecipeID: recipeID, Score: score}).Error)
+	}
+}
+
+func TestRecommendations_CollaborativeFromRatings(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	author := createTestUser(t, "reco_author@test.com", "password123", "Chef")
+	user := createTestUser(t, "reco_user@test.com", "password123", "User")
+	token := loginTestUser(t, router, "reco_user@test.com", "password123")
+
+	liked := testdb.SeedRecipe(t, "Moqueca", "A", author.ID, false)
+	together := testdb.SeedRecipe(t, "Bobó de camarão", "B", author.ID, false)
+	opposite := testdb.SeedRecipe(t, "Salada verde", "C", author.ID, false)
+	disliked := testdb.SeedRecipe(t, "Sopa de legumes", "D", author.ID, false)
+	own := testdb.SeedRecipe(t, "Receita do usuário", "E", user.ID, false)
+
+	// Quem gosta de moqueca gosta de bobó e não gosta de salada
+	for i, scores := range []map[uint]int{
+		{liked.ID: 5, together.ID: 5, opposite.ID: 1},
+		{liked.ID: 4, together.ID: 5, opposite.ID: 2},
+		{liked.ID: 5, together.ID: 4, opposite.ID: 1, disliked.ID: 3},
+	} {
+		rater := createTestUser(t, "reco_rater"+itoa(uint(i))+"@test.com", "password123", "Rater")
+		rateRecipes(t, rater.ID, scores)
+	}
+	rateRecipes(t, user.ID, map[uint]int{liked.ID: 5, disliked.ID: 2})
+
+	pairs, err := recommendation.Rebuild(database.DB)
+	require.NoError(t, err)
+	assert.Positive(t, pairs)
+
+	var negative int64
+	database.DB.Model(&models.RecipeRatingSimilarity{}).Where("recipe_id = ? AND similar_id = ?", liked.ID, opposite.ID).Count(&negative)
+	assert.Zero(t, negative)
+
+	recipes := myRecommendations(t, router, token)
+	require.NotEmpty(t, recipes)
+	assert.Equal(t, together.ID, recipes[0].ID)
+	assert.Equal(t, "Bobó de camarão", recipes[0].Title)
+	assert.Equal(t, recommendation.SourceCollaborative, recipes[0].Recommendation.Source)
+	require.NotNil(t, recipes[0].Recommendation.BecauseOf)
+	assert.Equal(t, liked.ID, *recipes[0].Recommendation.BecauseOf)
+
+	// Receitas avaliadas ou criadas pelo usuário nunca são recomendadas; a salada só entra como popular
+	for _, recipe := range recipes {
+		assert.NotContains(t, []uint{liked.ID, disliked.ID, own.ID}, recipe.ID)
+		if recipe.ID == opposite.ID {
+			assert.Equal(t, recommendation.SourcePopular, recipe.Recommendation.Source)
+		}
+	}
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/users/me/recommendations?limit=1", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var limited struct {
+		Data []handlers.RecommendedRecipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &limited))
+	require.Len(t, limited.Data, 1)
+	assert.Equal(t, together.ID, limited.Data[0].ID)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/recommendations?limit=0", token, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/users/me/recommendations", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRecommendations_ColdStartUsesContentAndPopular(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	author := createTestUser(t, "cold_author@test.com", "password123", "Chef")
+	user := createTestUser(t, "cold_user@test.com", "password123", "User")
+	token := loginTestUser(t, router, "cold_user@test.com", "password123")
+
+	rice := testdb.SeedIngredient(t, "Arroz", "cereais", 130)
+	beans := testdb.SeedIngredient(t, "Feijão", "leguminosas", 340)
+	pasta := testdb.SeedIngredient(t, "Macarrão", "cereais", 370)
+
+	seed := func(title string, userID uint, ingredients ...*models.Ingredient) *models.Recipe {
+		recipe := testdb.SeedRecipe(t, title, title, userID, false)
+		for _, ing := range ingredients {
+			addTestRecipeIngredient(t, recipe.ID, ing.ID, 100, "g")
+		}
+		return recipe
+	}
+
+	own := seed("Meu arroz com feijão", user.ID, rice, beans)
+	similar := seed("Feijão com arroz", author.ID, rice, beans)
+	popular := seed("Macarrão da casa", author.ID, pasta)
+	unpopular := seed("Macarrão sem graça", author.ID, pasta)
+	private := seed("Arroz privado", author.ID, rice, beans)
+	require.NoError(t, database.DB.Model(private).Update("visibility", models.RecipeVisibilityPrivate).Error)
+	draft := seed("Rascunho de feijão", author.ID, rice, beans)
+	require.NoError(t, database.DB.Model(draft).Update("status", models.RecipeStatusDraft).Error)
+
+	for i, scores := range []map[uint]int{{popular.ID: 5}, {popular.ID: 5}, {unpopular.ID: 1}} {
+		rater := createTestUser(t, "cold_rater"+itoa(uint(i))+"@test.com", "password123", "Rater")
+		rateRecipes(t, rater.ID, scores)
+	}
+
+	_, err := similarity.Rebuild(database.DB)
+	require.NoError(t, err)
+	_, err = recommendation.Rebuild(database.DB)
+	require.NoError(t, err)
+
+	// Sem avaliações, a receita criada pelo usuário serve de semente para a similaridade de conteúdo
+	recipes := myRecommendations(t, router, token)
+	ids := make([]uint, len(recipes))
+	for i, recipe := range recipes {
+		ids[i] = recipe.ID
+	}
+	assert.Equal(t, []uint{similar.ID, popular.ID, unpopular.ID}, ids)
+	assert.Equal(t, recommendation.SourceContent, recipes[0].Recommendation.Source)
+	require.NotNil(t, recipes[0].Recommendation.BecauseOf)
+	assert.Equal(t, own.ID, *recipes[0].Recommendation.BecauseOf)
+	assert.Equal(t, recommendation.SourcePopular, recipes[1].Recommendation.Source)
+	assert.Nil(t, recipes[1].Recommendation.BecauseOf)
+}
@@ -0,0 +1,175 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/handlers"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// addTestRecipeIngredient vincula um ingrediente à receita com a quantidade e unidade informadas
+func addTestRecipeIngredient(t *testing.T, recipeID, ingredientID uint, quantity float64, unit string) {
+	t.Helper()
+	require.NoError(t, database.DB.Create(&models.RecipeIngredient{
+		RecipeID: recipeID, IngredientID: ingredientID, Quantity: quantity, Unit: unit,
+	}).Error)
+}
+
+// decodeShoppingList decodifica a resposta da lista e indexa os itens por nome
+func decodeShoppingList(t *testing.T, body []byte) (handlers.ShoppingListResponse, map[string]models.ShoppingListItem) {
+	t.Helper()
+
+	var list handlers.ShoppingListResponse
+	require.NoError(t, json.Unmarshal(body, &list))
+
+	items := make(map[string]models.ShoppingListItem)
+	for _, group := range list.Groups {
+		for _, item := range group.Items {
+			items[item.Name] = item
+		}
+	}
+	return list, items
+}
+
+func TestShoppingList_AggregatesRecipesAndMealPlan(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "shopper@test.com", "password123", "Shopper")
+	token := loginTestUser(t, router, "shopper@test.com", "password123")
+
+	rice := testdb.SeedIngredient(t, "Arroz", "cereais", 128)
+	milk := testdb.SeedIngredient(t, "Leite", "laticínios", 61)
+	garlic := testdb.SeedIngredient(t, "Alho", "vegetais", 113)
+	egg := testdb.SeedIngredient(t, "Ovo", "ovos", 146)
+
+	// Receitas de 4 porções
+	risotto := testdb.SeedRecipe(t, "Risoto", "Risoto cremoso", user.ID, false)
+	addTestRecipeIngredient(t, risotto.ID, rice.ID, 500, "g")
+	addTestRecipeIngredient(t, risotto.ID, milk.ID, 1, "xícara")
+	addTestRecipeIngredient(t, risotto.ID, garlic.ID, 2, "dentes")
+	pudding := testdb.SeedRecipe(t, "Arroz doce", "Sobremesa", user.ID, false)
+	addTestRecipeIngredient(t, pudding.ID, rice.ID, 0.5, "kg")
+	addTestRecipeIngredient(t, pudding.ID, milk.ID, 260, "ml")
+	addTestRecipeIngredient(t, pudding.ID, egg.ID, 3, "unidades")
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/shopping-lists", token, map[string]interface{}{
+		"name":    "Feira",
+		"recipes": []map[string]interface{}{{"recipe_id": risotto.ID, "servings": 8}, {"recipe_id": pudding.ID}},
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	list, items := decodeShoppingList(t, rec.Body.Bytes())
+
+	// Risoto em dobro: 1000g + 500g de arroz; 2 xícaras (480ml) + 260ml de leite
+	require.Len(t, items, 4)
+	assert.InDelta(t, 1.5, *items["Arroz"].Quantity, 0.001)
+	assert.Equal(t, "kg", items["Arroz"].Unit)
+	assert.InDelta(t, 740.0, *items["Leite"].Quantity, 0.001)
+	assert.Equal(t, "ml", items["Leite"].Unit)
+	assert.InDelta(t, 4.0, *items["Alho"].Quantity, 0.001)
+	assert.Equal(t, "dentes", items["Alho"].Unit)
+	assert.Equal(t, "un", items["Ovo"].Unit)
+
+	categories := make([]string, 0, len(list.Groups))
+	for _, group := range list.Groups {
+		categories = append(categories, group.Category)
+	}
+	assert.Equal(t, []string{"cereais", "laticínios", "ovos", "vegetais"}, categories)
+
+	// A partir do planejamento: soma as porções das refeições do período
+	planID := createTestMealPlan(t, router, token, "Semana")
+	for _, date := range []string{"2026-10-19", "2026-10-20", "2026-10-27"} {
+		rec = doAuthRequest(t, router, http.MethodPost, "/meal-plans/"+itoa(planID)+"/slots", token, map[string]interface{}{
+			"date": date, "meal_type": "lunch", "recipe_id": risotto.ID, "servings": 2,
+		})
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/shopping-lists", token, map[string]interface{}{
+		"meal_plan_id": planID, "from": "2026-10-19", "to": "2026-10-25",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	list, items = decodeShoppingList(t, rec.Body.Bytes())
+	require.NotNil(t, list.MealPlanID)
+	assert.Equal(t, "Lista de compras", list.Name)
+	assert.InDelta(t, 500.0, *items["Arroz"].Quantity, 0.001)
+	assert.Equal(t, "g", items["Arroz"].Unit)
+	assert.InDelta(t, 240.0, *items["Leite"].Quantity, 0.001)
+
+	// Exatamente uma origem
+	rec = doAuthRequest(t, router, http.MethodPost, "/shopping-lists", token, map[string]interface{}{"name": "Vazia"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/shopping-lists", token, map[string]interface{}{
+		"recipes": []map[string]interface{}{{"recipe_id": risotto.ID}}, "meal_plan_id": planID, "from": "2026-10-19", "to": "2026-10-25",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestShoppingList_ManualItemsSharingAndExport(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "shopper2@test.com", "password123", "Shopper")
+	token := loginTestUser(t, router, "shopper2@test.com", "password123")
+
+	rice := testdb.SeedIngredient(t, "Arroz", "cereais", 128)
+	recipe := createTestRecipe(t, user.ID)
+	addTestRecipeIngredient(t, recipe.ID, rice.ID, 500, "g")
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/shopping-lists", token, map[string]interface{}{
+		"name": "Mercado", "recipes": []map[string]interface{}{{"recipe_id": recipe.ID}},
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	list, items := decodeShoppingList(t, rec.Body.Bytes())
+	listPath := "/shopping-lists/" + itoa(list.ID)
+
+	rec = doAuthRequest(t, router, http.MethodPost, listPath+"/items", token, map[string]interface{}{"name": "Papel toalha"})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// Sem link, a lista não existe para terceiros
+	rec = doAuthRequest(t, router, http.MethodGet, listPath, "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPost, listPath+"/share", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var share struct {
+		ShareToken string `json:"share_token"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &share))
+	require.NotEmpty(t, share.ShareToken)
+
+	// Com o link: ver e marcar itens, sem conta
+	rec = doAuthRequest(t, router, http.MethodPatch, listPath+"/items/"+itoa(items["Arroz"].ID)+"?share_token="+share.ShareToken, "",
+		map[string]bool{"checked": true})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = doAuthRequest(t, router, http.MethodPost, listPath+"/items?share_token="+share.ShareToken, "", map[string]interface{}{"name": "Sal"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodGet, listPath+"?format=checklist&share_token="+share.ShareToken, "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Equal(t, "*Mercado*\n\n*Cereais*\n☑ Arroz: 500 g\n\n*Outros*\n☐ Papel toalha\n", rec.Body.String())
+
+	rec = doAuthRequest(t, router, http.MethodGet, listPath+"?format=text", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Mercado\n\nCEREAIS\n- Arroz: 500 g\n\nOUTROS\n- Papel toalha\n", rec.Body.String())
+
+	// Link revogado deixa de funcionar
+	rec = doAuthRequest(t, router, http.MethodDelete, listPath+"/share", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodGet, listPath+"?share_token="+share.ShareToken, "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, listPath, token, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var remaining int64
+	database.DB.Model(&models.ShoppingListItem{}).Where("shopping_list_id = ?", list.ID).Count(&remaining)
+	assert.Zero(t, remaining)
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/handlers"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/similarity"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// similarRecipes busca as receitas semelhantes pela API
+func similarRecipes(t *testing.T, router http.Handler, recipeID uint, token string) []handlers.SimilarRecipe {
+	t.Helper()
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipeID)+"/similar", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var body struct {
+		Data []handlers.SimilarRecipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body.Data
+}
+
+func similarIDs(recipes []handlers.SimilarRecipe) []uint {
+	ids := make([]uint, len(recipes))
+	for i, recipe := range recipes {
+		ids[i] = recipe.ID
+	}
+	return ids
+}
+
+func TestSimilarRecipes_IngredientOverlapWithIDF(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "similar_owner@test.com", "password123", "Chef")
+	ownerToken := loginTestUser(t, router, "similar_owner@test.com", "password123")
+	createTestUser(t, "similar_other@test.com", "password123", "Other")
+	otherToken := loginTestUser(t, router, "similar_other@test.com", "password123")
+
+	salt := testdb.SeedIngredient(t, "Sal", "temperos", 0)
+	oil := testdb.SeedIngredient(t, "Óleo", "óleos", 880)
+	rice := testdb.SeedIngredient(t, "Arroz", "cereais", 130)
+	beans := testdb.SeedIngredient(t, "Feijão", "leguminosas", 340)
+	pasta := testdb.SeedIngredient(t, "Macarrão", "cereais", 370)
+	tomato := testdb.SeedIngredient(t, "Tomate", "hortaliças", 20)
+
+	seed := func(title string, ingredients ...*models.Ingredient) *models.Recipe {
+		recipe := testdb.SeedRecipe(t, title, title, owner.ID, false)
+		for _, ing := range ingredients {
+			addTestRecipeIngredient(t, recipe.ID, ing.ID, 100, "g")
+		}
+		return recipe
+	}
+
+	// Sal está em todas as receitas e não aproxima ninguém; óleo pesa pouco
+	riceAndBeans := seed("Arroz com feijão", rice, beans, salt, oil)
+	beansAndRice := seed("Feijão com arroz", rice, beans, salt, oil)
+	pastaWithOil := seed("Macarrão ao alho e óleo", pasta, tomato, salt, oil)
+	pastaWithSalt := seed("Macarrão simples", pasta, tomato, salt)
+
+	// Rascunho e receita privada não são sugeridos, mas a privada recebe vizinhos
+	draft := seed("Rascunho de arroz", rice, beans)
+	require.NoError(t, database.DB.Model(draft).Update("status", models.RecipeStatusDraft).Error)
+	private := seed("Arroz privado", rice, beans, salt)
+	require.NoError(t, database.DB.Model(private).Update("visibility", models.RecipeVisibilityPrivate).Error)
+
+	count, err := similarity.Rebuild(database.DB)
+	require.NoError(t, err)
+	assert.Positive(t, count)
+
+	neighbors := similarRecipes(t, router, riceAndBeans.ID, "")
+	assert.Equal(t, []uint{beansAndRice.ID, pastaWithOil.ID}, similarIDs(neighbors))
+	assert.InDelta(t, 0.8, neighbors[0].Similarity, 0.001)
+	assert.Less(t, neighbors[1].Similarity, 0.3)
+	assert.Equal(t, "Feijão com arroz", neighbors[0].Title)
+
+	// Só compartilha o sal: nenhuma receita de arroz é vizinha
+	assert.Equal(t, []uint{pastaWithOil.ID}, similarIDs(similarRecipes(t, router, pastaWithSalt.ID, "")))
+
+	assert.Equal(t, []uint{riceAndBeans.ID, beansAndRice.ID}, similarIDs(similarRecipes(t, router, private.ID, ownerToken)))
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(private.ID)+"/similar", otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(riceAndBeans.ID)+"/similar?limit=1", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var limited struct {
+		Data []handlers.SimilarRecipe `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &limited))
+	assert.Equal(t, []uint{beansAndRice.ID}, similarIDs(limited.Data))
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(riceAndBeans.ID)+"/similar?limit=50", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSimilarRecipes_RefreshedWhenRecipesChange(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	owner := createTestUser(t, "refresh_owner@test.com", "password123", "Chef")
+	ownerToken := loginTestUser(t, router, "refresh_owner@test.com", "password123")
+
+	rice := testdb.SeedIngredient(t, "Arroz", "cereais", 130)
+	beans := testdb.SeedIngredient(t, "Feijão", "leguminosas", 340)
+	corn := testdb.SeedIngredient(t, "Milho", "cereais", 100)
+
+	base := testdb.SeedRecipe(t, "Arroz com feijão", "Base", owner.ID, false)
+	addTestRecipeIngredient(t, base.ID, rice.ID, 100, "g")
+	addTestRecipeIngredient(t, base.ID, beans.ID, 100, "g")
+	other := testdb.SeedRecipe(t, "Milho cozido", "Outra", owner.ID, false)
+	addTestRecipeIngredient(t, other.ID, corn.ID, 100, "g")
+	tagged := testdb.SeedRecipe(t, "Feijão tropeiro", "Tag", owner.ID, false)
+
+	_, err := similarity.Rebuild(database.DB)
+	require.NoError(t, err)
+	assert.Empty(t, similarRecipes(t, router, base.ID, ""))
+
+	// Adicionar ingrediente pela API conta como alteração da receita
+	builtAt := time.Now()
+	changed, err := similarity.ChangedSince(database.DB, builtAt)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	addRecipeIngredientViaAPI(t, router, ownerToken, other.ID, rice.ID, 100)
+	changed, err = similarity.ChangedSince(database.DB, builtAt)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	// Tags em comum também aproximam receitas
+	tag := models.Tag{Name: "Brasileira", Slug: "brasileira", Type: models.TagTypeCuisine, Status: models.TagStatusApproved}
+	require.NoError(t, database.DB.Create(&tag).Error)
+	for _, recipeID := range []uint{base.ID, tagged.ID} {
+		rec := doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipeID)+"/tags", ownerToken, map[string][]uint{"tag_ids": {tag.ID}})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+	assert.True(t, storedRecipe(t, tagged.ID).UpdatedAt.After(builtAt))
+
+	_, err = similarity.Rebuild(database.DB)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uint{other.ID, tagged.ID}, similarIDs(similarRecipes(t, router, base.ID, "")))
+
+	// Vizinho despublicado sai da lista antes da próxima reconstrução
+	rec := doAuthRequest(t, router, http.MethodPatch, "/recipes/"+itoa(tagged.ID)+"/status", ownerToken, map[string]string{"status": models.RecipeStatusArchived})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []uint{other.ID}, similarIDs(similarRecipes(t, router, base.ID, "")))
+	changed, err = similarity.ChangedSince(database.DB, time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	assert.True(t, changed)
+}
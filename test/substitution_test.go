@@ -0,0 +1,171 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/handlers"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/pkg/substitution"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+func TestIngredientSubstitutes_CuratedAndSimilar(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	admin := createTestUser(t, "sub_admin@test.com", "password123", "Admin")
+	database.DB.Model(&admin).Update("role", "admin")
+	adminToken := loginTestUser(t, router, "sub_admin@test.com", "password123")
+	createTestUser(t, "sub_user@test.com", "password123", "User")
+	userToken := loginTestUser(t, router, "sub_user@test.com", "password123")
+
+	// Mesmo perfil de macronutrientes; a similaridade depende das calorias
+	butter := testdb.SeedIngredient(t, "Manteiga", "laticínios", 720)
+	ghee := testdb.SeedIngredient(t, "Manteiga clarificada", "laticínios", 700)
+	cheese := testdb.SeedIngredient(t, "Queijo", "laticínios", 300)
+	oil := testdb.SeedIngredient(t, "Óleo de soja", "óleos", 880)
+	margarine := testdb.SeedIngredient(t, "Margarina", "óleos", 600)
+	require.NoError(t, database.DB.Model(butter).Updates(map[string]interface{}{"contains_lactose": true, "animal_origin": true}).Error)
+	require.NoError(t, database.DB.Model(ghee).Updates(map[string]interface{}{"contains_lactose": true, "animal_origin": true}).Error)
+	require.NoError(t, database.DB.Model(margarine).Update("contains_soy", true).Error)
+
+	sub := postJSON[models.IngredientSubstitution](t, router, "/admin/ingredients/"+itoa(butter.ID)+"/substitutes", adminToken, map[string]interface{}{
+		"substitute_id": oil.ID, "ratio": 0.8, "notes": "Em bolos",
+	})
+	assert.Equal(t, oil.ID, sub.Substitute.ID)
+	postJSON[models.IngredientSubstitution](t, router, "/admin/ingredients/"+itoa(butter.ID)+"/substitutes", adminToken, map[string]interface{}{
+		"substitute_id": margarine.ID, "ratio": 0,
+	})
+
+	// Validações do cadastro curado
+	rec := doAuthRequest(t, router, http.MethodPost, "/admin/ingredients/"+itoa(butter.ID)+"/substitutes", adminToken, map[string]interface{}{"substitute_id": oil.ID})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/admin/ingredients/"+itoa(butter.ID)+"/substitutes", adminToken, map[string]interface{}{"substitute_id": butter.ID})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/admin/ingredients/"+itoa(butter.ID)+"/substitutes", adminToken, map[string]interface{}{"substitute_id": cheese.ID, "ratio": -1})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/admin/ingredients/"+itoa(butter.ID)+"/substitutes", userToken, map[string]interface{}{"substitute_id": cheese.ID})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	list := func(path string) []substitution.Substitute {
+		t.Helper()
+		rec := doAuthRequest(t, router, http.MethodGet, path, "", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var body struct {
+			Substitutes []substitution.Substitute `json:"substitutes"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body.Substitutes
+	}
+
+	// Curadas primeiro, depois a similar da mesma categoria; o queijo tem calorias distantes demais
+	subs := list("/ingredients/" + itoa(butter.ID) + "/substitutes")
+	require.Len(t, subs, 3)
+	assert.Equal(t, oil.ID, subs[0].Ingredient.ID)
+	assert.Equal(t, substitution.SourceCurated, subs[0].Source)
+	assert.Equal(t, 0.8, subs[0].Ratio)
+	assert.Equal(t, "Em bolos", subs[0].Notes)
+	assert.Equal(t, margarine.ID, subs[1].Ingredient.ID)
+	assert.Equal(t, 1.0, subs[1].Ratio)
+	assert.Equal(t, ghee.ID, subs[2].Ingredient.ID)
+	assert.Equal(t, substitution.SourceSimilar, subs[2].Source)
+	assert.InDelta(t, 0.97, subs[2].Similarity, 0.001)
+
+	// Restrições filtram os substitutos
+	subs = list("/ingredients/" + itoa(butter.ID) + "/substitutes?exclude_allergens=lactose,soy")
+	require.Len(t, subs, 1)
+	assert.Equal(t, oil.ID, subs[0].Ingredient.ID)
+	subs = list("/ingredients/" + itoa(butter.ID) + "/substitutes?diet=vegan")
+	assert.Len(t, subs, 2)
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/ingredients/"+itoa(butter.ID)+"/substitutes?diet=keto", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodGet, "/ingredients/999999/substitutes", "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Remoção da curada
+	rec = doAuthRequest(t, router, http.MethodDelete, "/admin/ingredients/"+itoa(butter.ID)+"/substitutes/"+itoa(sub.ID), adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	subs = list("/ingredients/" + itoa(butter.ID) + "/substitutes")
+	require.Len(t, subs, 2)
+	assert.Equal(t, margarine.ID, subs[0].Ingredient.ID)
+}
+
+func TestRecipeSubstitutions_DietaryConstraint(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	admin := createTestUser(t, "swap_admin@test.com", "password123", "Admin")
+	database.DB.Model(&admin).Update("role", "admin")
+	adminToken := loginTestUser(t, router, "swap_admin@test.com", "password123")
+	owner := createTestUser(t, "swap_owner@test.com", "password123", "Chef")
+
+	butter := testdb.SeedIngredient(t, "Manteiga", "laticínios", 720)
+	oil := testdb.SeedIngredient(t, "Óleo de soja", "óleos", 880)
+	egg := testdb.SeedIngredient(t, "Ovo", "ovos", 150)
+	banana := testdb.SeedIngredient(t, "Banana", "frutas", 90)
+	flour := testdb.SeedIngredient(t, "Farinha de trigo", "cereais", 360)
+	require.NoError(t, database.DB.Model(butter).Updates(map[string]interface{}{"contains_lactose": true, "animal_origin": true}).Error)
+	require.NoError(t, database.DB.Model(egg).Updates(map[string]interface{}{"contains_egg": true, "animal_origin": true}).Error)
+	require.NoError(t, database.DB.Model(flour).Update("contains_gluten", true).Error)
+
+	postJSON[models.IngredientSubstitution](t, router, "/admin/ingredients/"+itoa(butter.ID)+"/substitutes", adminToken, map[string]interface{}{
+		"substitute_id": oil.ID, "ratio": 0.8,
+	})
+	postJSON[models.IngredientSubstitution](t, router, "/admin/ingredients/"+itoa(egg.ID)+"/substitutes", adminToken, map[string]interface{}{
+		"substitute_id": banana.ID, "ratio": 1, "notes": "Amassada",
+	})
+
+	recipe := createTestRecipe(t, owner.ID)
+	addTestRecipeIngredient(t, recipe.ID, flour.ID, 200, "g")
+	addTestRecipeIngredient(t, recipe.ID, butter.ID, 100, "g")
+	addTestRecipeIngredient(t, recipe.ID, egg.ID, 2, "un")
+
+	type swapsResponse struct {
+		Swaps       []handlers.RecipeSwap `json:"swaps"`
+		Satisfiable bool                  `json:"satisfiable"`
+	}
+	swaps := func(query string) swapsResponse {
+		t.Helper()
+		rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/substitutions?"+query, "", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var body swapsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+
+	// Vegana: manteiga e ovo precisam de troca; a farinha já atende
+	vegan := swaps("diet=vegan")
+	assert.True(t, vegan.Satisfiable)
+	require.Len(t, vegan.Swaps, 2)
+	assert.Equal(t, butter.ID, vegan.Swaps[0].Ingredient.ID)
+	assert.Equal(t, []string{"vegan"}, vegan.Swaps[0].Violations)
+	require.Len(t, vegan.Swaps[0].Substitutes, 1)
+	assert.Equal(t, oil.ID, vegan.Swaps[0].Substitutes[0].Ingredient.ID)
+	assert.InDelta(t, 80.0, vegan.Swaps[0].Substitutes[0].Quantity, 0.001)
+	assert.Equal(t, egg.ID, vegan.Swaps[1].Ingredient.ID)
+	assert.Equal(t, banana.ID, vegan.Swaps[1].Substitutes[0].Ingredient.ID)
+	assert.InDelta(t, 2.0, vegan.Swaps[1].Substitutes[0].Quantity, 0.001)
+
+	// Sem glúten: a farinha não tem substituto cadastrado nem similar
+	glutenFree := swaps("diet=gluten_free&exclude_allergens=egg")
+	assert.False(t, glutenFree.Satisfiable)
+	require.Len(t, glutenFree.Swaps, 2)
+	assert.Equal(t, flour.ID, glutenFree.Swaps[0].Ingredient.ID)
+	assert.Empty(t, glutenFree.Swaps[0].Substitutes)
+	assert.Equal(t, []string{"egg"}, glutenFree.Swaps[1].Violations)
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/substitutions", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Receita privada de outro usuário não é exposta
+	require.NoError(t, database.DB.Model(&models.Recipe{}).Where("id = ?", recipe.ID).Update("visibility", models.RecipeVisibilityPrivate).Error)
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID)+"/substitutions?diet=vegan", "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
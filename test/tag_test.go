@@ -0,0 +1,146 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/davidsonmarra/receitas-app/internal/http/handlers"
+	"github.com/davidsonmarra/receitas-app/internal/models"
+	"github.com/davidsonmarra/receitas-app/pkg/database"
+	"github.com/davidsonmarra/receitas-app/test/testdb"
+)
+
+// seedTag cria uma tag aprovada diretamente no banco
+func seedTag(t *testing.T, name, slug, tagType string) models.Tag {
+	t.Helper()
+
+	tag := models.Tag{Name: name, Slug: slug, Type: tagType, Status: models.TagStatusApproved}
+	require.NoError(t, database.DB.Create(&tag).Error)
+	return tag
+}
+
+func TestTags_SuggestionAndModeration(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	admin := createTestUser(t, "tag_admin@test.com", "password123", "Admin")
+	database.DB.Model(&admin).Update("role", "admin")
+	adminToken := loginTestUser(t, router, "tag_admin@test.com", "password123")
+	user := createTestUser(t, "tag_user@test.com", "password123", "Usuária")
+	userToken := loginTestUser(t, router, "tag_user@test.com", "password123")
+
+	rec := doAuthRequest(t, router, http.MethodPost, "/tags/suggestions", userToken, map[string]interface{}{
+		"name": "Festa Junina",
+		"type": "occasion",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var suggestion models.Tag
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &suggestion))
+	assert.Equal(t, "festa-junina", suggestion.Slug)
+	assert.Equal(t, models.TagStatusPending, suggestion.Status)
+
+	// Sugestão duplicada e tipo inválido
+	rec = doAuthRequest(t, router, http.MethodPost, "/tags/suggestions", userToken, map[string]interface{}{
+		"name": "festa junina",
+		"type": "occasion",
+	})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodPost, "/tags/suggestions", userToken, map[string]interface{}{
+		"name": "Picante",
+		"type": "flavor",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Pendente não aparece na listagem pública nem pode ser usada
+	rec = doAuthRequest(t, router, http.MethodGet, "/tags", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "festa-junina")
+
+	recipe := createTestRecipe(t, user.ID)
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID)+"/tags", userToken, map[string]interface{}{
+		"tag_ids": []uint{suggestion.ID},
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Apenas admins moderam
+	rec = doAuthRequest(t, router, http.MethodPost, "/admin/tags/"+itoa(suggestion.ID)+"/approve", userToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodPost, "/admin/tags/"+itoa(suggestion.ID)+"/approve", adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doAuthRequest(t, router, http.MethodPut, "/recipes/"+itoa(recipe.ID)+"/tags", userToken, map[string]interface{}{
+		"tag_ids": []uint{suggestion.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes/"+itoa(recipe.ID), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var recipeResp models.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &recipeResp))
+	require.Len(t, recipeResp.Tags, 1)
+	assert.Equal(t, "Festa Junina", recipeResp.Tags[0].Name)
+
+	// Tag em uso não pode ser rejeitada; excluir remove das receitas
+	rec = doAuthRequest(t, router, http.MethodPost, "/admin/tags/"+itoa(suggestion.ID)+"/reject", adminToken, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = doAuthRequest(t, router, http.MethodDelete, "/admin/tags/"+itoa(suggestion.ID), adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var links int64
+	database.DB.Table("recipe_tags").Where("recipe_id = ?", recipe.ID).Count(&links)
+	assert.Equal(t, int64(0), links)
+}
+
+func TestListRecipes_TagFiltersAndFacets(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+	router := setupRouter()
+
+	user := createTestUser(t, "tag_filter@test.com", "password123", "Chef")
+
+	italian := seedTag(t, "Italiana", "italiana", models.TagTypeCuisine)
+	lunch := seedTag(t, "Almoço", "almoco", models.TagTypeMealType)
+	dinner := seedTag(t, "Jantar", "jantar", models.TagTypeMealType)
+
+	lasagna := createTestRecipe(t, user.ID)
+	risotto := createTestRecipe(t, user.ID)
+	soup := createTestRecipe(t, user.ID)
+	require.NoError(t, database.DB.Model(lasagna).Association("Tags").Append([]models.Tag{italian, lunch}))
+	require.NoError(t, database.DB.Model(risotto).Association("Tags").Append([]models.Tag{italian, dinner}))
+	require.NoError(t, database.DB.Model(soup).Association("Tags").Append([]models.Tag{dinner}))
+
+	list := func(query string) handlers.RecipeListResponse {
+		t.Helper()
+		rec := doAuthRequest(t, router, http.MethodGet, "/recipes"+query, "", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp handlers.RecipeListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+
+	// AND: italiana e jantar
+	resp := list("?tags=" + itoa(italian.ID) + "," + itoa(dinner.ID))
+	assert.Equal(t, int64(1), resp.Pagination.Total)
+
+	// OR: almoço ou jantar
+	resp = list("?tags=" + itoa(lunch.ID) + "," + itoa(dinner.ID) + "&tag_match=any")
+	assert.Equal(t, int64(3), resp.Pagination.Total)
+
+	// Facetas refletem o resultado filtrado, agrupadas por tipo
+	resp = list("?tags=" + itoa(italian.ID))
+	assert.Equal(t, int64(2), resp.Pagination.Total)
+	require.Len(t, resp.Facets[models.TagTypeCuisine], 1)
+	assert.Equal(t, int64(2), resp.Facets[models.TagTypeCuisine][0].Count)
+	assert.Len(t, resp.Facets[models.TagTypeMealType], 2)
+	assert.Empty(t, resp.Facets[models.TagTypeOccasion])
+
+	rec := doAuthRequest(t, router, http.MethodGet, "/recipes?tags=abc", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doAuthRequest(t, router, http.MethodGet, "/recipes?tags=1&tag_match=xor", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,51 @@
+package testdb
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/davidsonmarra/receitas-app/pkg/mailer"
+)
+
+// MockMailer captura e-mails enviados durante os testes
+// Implementa a interface mailer.Sender
+type MockMailer struct {
+	mu       sync.Mutex
+	Messages []mailer.Message
+}
+
+// Garantir que MockMailer implementa mailer.Sender
+var _ mailer.Sender = (*MockMailer)(nil)
+
+// Send armazena a mensagem em memória
+func (m *MockMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Messages = append(m.Messages, msg)
+	return nil
+}
+
+// Last retorna a última mensagem enviada (ou nil)
+func (m *MockMailer) Last() *mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.Messages) == 0 {
+		return nil
+	}
+	msg := m.Messages[len(m.Messages)-1]
+	return &msg
+}
+
+// UseMockMailer substitui o mailer padrão por um mock durante o teste
+func UseMockMailer(t *testing.T) *MockMailer {
+	t.Helper()
+
+	original := mailer.DefaultSender
+	mock := &MockMailer{}
+	mailer.DefaultSender = mock
+	t.Cleanup(func() {
+		mailer.DefaultSender = original
+	})
+	return mock
+}
@@ -44,9 +44,31 @@ func Setup(t *testing.T) func() {
 		&models.User{},
 		&models.Recipe{},
 		&models.Ingredient{},
+		&models.IngredientNutrient{},
 		&models.RecipeIngredient{},
+		&models.RecipeStep{},
+		&models.RecipeRevision{},
+		&models.Tag{},
 		&models.Rating{},
+		&models.MealPlan{},
+		&models.MealPlanSlot{},
+		&models.MealPlanMember{},
+		&models.ShoppingList{},
+		&models.ShoppingListItem{},
+		&models.PantryItem{},
+		&models.NutritionGoal{},
+		&models.IngredientPrice{},
+		&models.IngredientSubstitution{},
+		&models.RecipeSimilarity{},
+		&models.RecipeRatingSimilarity{},
+		&models.RecipeEvent{},
+		&models.RecipeDailyStat{},
+		&models.CookLog{},
 		&models.RefreshToken{},
+		&models.APIKey{},
+		&models.FoodAnalysis{},
+		&models.DataExport{},
+		&models.DataErasure{},
 	); err != nil {
 		testMutex.Unlock()
 		t.Fatalf("falha ao executar migrations: %v", err)
@@ -55,10 +77,34 @@ func Setup(t *testing.T) func() {
 	// Retornar função de cleanup
 	return func() {
 		// Limpar todas as tabelas
+		db.Exec("DELETE FROM data_erasures")
+		db.Exec("DELETE FROM data_exports")
+		db.Exec("DELETE FROM food_analyses")
+		db.Exec("DELETE FROM api_keys")
 		db.Exec("DELETE FROM refresh_tokens")
+		db.Exec("DELETE FROM nutrition_goals")
+		db.Exec("DELETE FROM pantry_items")
+		db.Exec("DELETE FROM shopping_list_items")
+		db.Exec("DELETE FROM shopping_lists")
+		db.Exec("DELETE FROM meal_plan_members")
+		db.Exec("DELETE FROM meal_plan_slots")
+		db.Exec("DELETE FROM meal_plans")
+		db.Exec("DELETE FROM recipe_similarities")
+		db.Exec("DELETE FROM recipe_rating_similarities")
+		db.Exec("DELETE FROM recipe_events")
+		db.Exec("DELETE FROM recipe_daily_stats")
+		db.Exec("DELETE FROM cook_logs")
 		db.Exec("DELETE FROM ratings")
+		db.Exec("DELETE FROM recipe_revisions")
+		db.Exec("DELETE FROM recipe_tags")
+		db.Exec("DELETE FROM tags")
+		db.Exec("DELETE FROM recipe_step_ingredients")
+		db.Exec("DELETE FROM recipe_steps")
 		db.Exec("DELETE FROM recipe_ingredients")
 		db.Exec("DELETE FROM recipes")
+		db.Exec("DELETE FROM ingredient_nutrients")
+		db.Exec("DELETE FROM ingredient_prices")
+		db.Exec("DELETE FROM ingredient_substitutions")
 		db.Exec("DELETE FROM ingredients")
 		db.Exec("DELETE FROM users")
 
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"golang.org/x/crypto/bcrypt"
+
 	"github.com/davidsonmarra/receitas-app/internal/http/handlers"
 	"github.com/davidsonmarra/receitas-app/internal/models"
 	"github.com/davidsonmarra/receitas-app/pkg/auth"
@@ -20,7 +23,7 @@ func TestRegister_Success(t *testing.T) {
 	payload := map[string]string{
 		"name":     "João Silva",
 		"email":    "joao@test.com",
-		"password": "senha123",
+		"password": "tempero-secreto-42",
 	}
 
 	body, _ := json.Marshal(payload)
@@ -57,7 +60,7 @@ func TestRegister_DuplicateEmail(t *testing.T) {
 	payload := map[string]string{
 		"name":     "Primeiro Usuário",
 		"email":    "duplicate@test.com",
-		"password": "senha123",
+		"password": "tempero-secreto-42",
 	}
 
 	body, _ := json.Marshal(payload)
@@ -100,12 +103,12 @@ func TestRegister_ValidationErrors(t *testing.T) {
 	}{
 		{
 			name:     "nome muito curto",
-			payload:  map[string]string{"name": "Jo", "email": "jo@test.com", "password": "senha123"},
+			payload:  map[string]string{"name": "Jo", "email": "jo@test.com", "password": "tempero-secreto-42"},
 			expected: http.StatusBadRequest,
 		},
 		{
 			name:     "email inválido",
-			payload:  map[string]string{"name": "João", "email": "email-invalido", "password": "senha123"},
+			payload:  map[string]string{"name": "João", "email": "email-invalido", "password": "tempero-secreto-42"},
 			expected: http.StatusBadRequest,
 		},
 		{
@@ -274,3 +277,64 @@ func TestLogout_InvalidToken(t *testing.T) {
 		t.Errorf("esperado status 401, obteve %d", rec.Code)
 	}
 }
+
+func TestRegister_CommonPasswordRejected(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+
+	payload := map[string]string{
+		"name":     "João Silva",
+		"email":    "comum@test.com",
+		"password": "senha123",
+	}
+
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", "/users/register", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handlers.Register(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("esperado status 400 para senha comum, obteve %d", rec.Code)
+	}
+}
+
+func TestLogin_UpgradesLegacyHash(t *testing.T) {
+	testdb.SetupWithCleanup(t)
+
+	// Usuário com hash bcrypt legado
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("senha-legada"), bcrypt.MinCost)
+	user := models.User{
+		Name:     "Legacy User",
+		Email:    "legacy@test.com",
+		Password: string(legacy),
+	}
+	database.DB.Create(&user)
+
+	payload := map[string]string{
+		"email":    "legacy@test.com",
+		"password": "senha-legada",
+	}
+
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", "/users/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handlers.Login(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("esperado status 200, obteve %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var updated models.User
+	database.DB.First(&updated, user.ID)
+
+	if !strings.HasPrefix(updated.Password, "$argon2id$") {
+		t.Errorf("hash deveria ter sido atualizado para argon2id, obteve %s", updated.Password)
+	}
+
+	if !auth.CheckPassword(updated.Password, "senha-legada") {
+		t.Error("novo hash deveria validar a senha original")
+	}
+}